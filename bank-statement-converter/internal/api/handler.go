@@ -2,8 +2,11 @@ package api
 
 import (
 	"bytes"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,36 +14,59 @@ import (
 	"github.com/insightdelivered/bank-statement-converter/internal/extractor"
 	"github.com/insightdelivered/bank-statement-converter/internal/models"
 	"github.com/insightdelivered/bank-statement-converter/internal/parser"
+	"github.com/insightdelivered/bank-statement-converter/internal/validator"
 	"github.com/insightdelivered/bank-statement-converter/internal/writer"
 )
 
 // ConvertResponse is the JSON response from the /api/convert endpoint.
 type ConvertResponse struct {
-	Success      bool                 `json:"success"`
-	Error        string               `json:"error,omitempty"`
-	Bank         string               `json:"bank,omitempty"`
-	AccountInfo  *AccountInfo         `json:"accountInfo,omitempty"`
-	Transactions []models.Transaction `json:"transactions"`
-	CSV          string               `json:"csv,omitempty"`
-	TotalDebit   float64              `json:"totalDebit"`
-	TotalCredit  float64              `json:"totalCredit"`
-	Count        int                  `json:"count"`
-	RawText      string               `json:"rawText,omitempty"`
-	Version      string               `json:"version,omitempty"`
-	DebugLines   []models.DebugLine   `json:"debugLines,omitempty"`
+	Success      bool                     `json:"success"`
+	Error        string                   `json:"error,omitempty"`
+	ErrorCode    string                   `json:"errorCode,omitempty"` // see ErrorPasswordRequired
+	Bank         string                   `json:"bank,omitempty"`
+	AccountInfo  *AccountInfo             `json:"accountInfo,omitempty"`
+	Detection    []models.BankCandidate   `json:"detection,omitempty"`
+	Validation   *models.ValidationReport `json:"validation,omitempty"`
+	Transactions []models.Transaction     `json:"transactions"`
+	CSV          string                   `json:"csv,omitempty"`
+	Format       string                   `json:"format,omitempty"` // format of Output
+	Output       string                   `json:"output,omitempty"` // the statement in Format, unless CSV
+	// "base64" when Output is a binary format such as XLSX
+	OutputEncoding string             `json:"outputEncoding,omitempty"`
+	TotalDebit     models.Money       `json:"totalDebit"`
+	TotalCredit    models.Money       `json:"totalCredit"`
+	Count          int                `json:"count"`
+	RawText        string             `json:"rawText,omitempty"`
+	Version        string             `json:"version,omitempty"`
+	DebugLines     []models.DebugLine `json:"debugLines,omitempty"`
 }
 
 // AccountInfo holds account metadata for the JSON response.
 type AccountInfo struct {
-	Holder         string  `json:"holder,omitempty"`
-	Number         string  `json:"number,omitempty"`
-	SortCode       string  `json:"sortCode,omitempty"`
-	Period         string  `json:"period,omitempty"`
-	OpeningBalance float64 `json:"openingBalance,omitempty"`
+	Holder         string       `json:"holder,omitempty"`
+	Number         string       `json:"number,omitempty"`
+	SortCode       string       `json:"sortCode,omitempty"`
+	Period         string       `json:"period,omitempty"`
+	OpeningBalance models.Money `json:"openingBalance,omitzero"`
+	ClosingBalance models.Money `json:"closingBalance,omitzero"`
+
+	// Credit card statements only
+	CreditCard       bool         `json:"creditCard,omitempty"`
+	StatementBalance models.Money `json:"statementBalance,omitzero"`
+	MinimumPayment   models.Money `json:"minimumPayment,omitzero"`
+	PaymentDueDate   string       `json:"paymentDueDate,omitempty"`
 }
 
 const apiVersion = "2.0.0"
 
+// Error codes in ConvertResponse.ErrorCode, for the errors a client acts
+// on rather than only shows: asking for the statement's password, or for
+// it again.
+const (
+	ErrorPasswordRequired = "password_required"
+	ErrorWrongPassword    = "wrong_password"
+)
+
 // HandleHealth returns a simple health check.
 func HandleHealth(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{
@@ -52,20 +78,134 @@ func HandleHealth(c *fiber.Ctx) error {
 
 // HandleConvert processes a PDF upload and returns parsed transactions.
 func HandleConvert(c *fiber.Ctx) error {
+	includeHeader := c.FormValue("header") != "false"
+	// Uploaded PDFs are untrusted: CSV cells that would run as spreadsheet
+	// formulas are neutralised unless the caller opts out
+	sanitize := c.FormValue("sanitize") != "false"
+	dateLayout, err := writer.DateLayout(c.FormValue("dateFormat"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, err.Error())
+	}
+	format, err := writer.LookupFormat(c.FormValue("format"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, err.Error())
+	}
+	var csvSchema *writer.CSVSchema
+	if raw := c.FormValue("csvSchema"); raw != "" {
+		csvSchema, err = writer.ParseCSVSchema([]byte(raw))
+		if err != nil {
+			return writeError(c, fiber.StatusBadRequest, fmt.Sprintf("Invalid csvSchema: %v", err))
+		}
+		if dateLayout == "" {
+			dateLayout = csvSchema.DateFormat
+		}
+	}
+
+	up, err := parseUpload(c)
+	if err != nil {
+		return writeUploadError(c, err)
+	}
+	info := up.info
+
+	// Generate CSV string
+	var csvBuf bytes.Buffer
+	csvWriter := &writer.CSVWriter{IncludeHeader: includeHeader, DateFormat: dateLayout, Schema: csvSchema, Sanitize: sanitize}
+	if err := csvWriter.Write(&csvBuf, info); err != nil {
+		return writeError(c, fiber.StatusInternalServerError, fmt.Sprintf("CSV generation failed: %v", err))
+	}
+
+	// Generate the requested format alongside the CSV the UI displays
+	var output bytes.Buffer
+	if format.Name != "csv" {
+		w := format.New(writer.Options{IncludeHeader: includeHeader, DateFormat: dateLayout, CSVSchema: csvSchema, Sanitize: sanitize})
+		if err := w.Write(&output, info); err != nil {
+			return writeError(c, fiber.StatusUnprocessableEntity, fmt.Sprintf("%s generation failed: %v", strings.ToUpper(format.Name), err))
+		}
+	}
+
+	// The JSON dates follow the same format as the CSV
+	writer.ApplyDateFormat(info.Transactions, dateLayout)
+
+	resp := NewConvertResponse(info, up.bank)
+	resp.Detection = up.detection
+	resp.CSV = csvBuf.String()
+	resp.Format = format.Name
+	resp.Output = output.String()
+	if format.Binary {
+		resp.Output = base64.StdEncoding.EncodeToString(output.Bytes())
+		resp.OutputEncoding = "base64"
+	}
+
+	// Always include raw extracted text (helps debug parser issues)
+	resp.RawText = strings.Join(up.pages, "\n--- PAGE BREAK ---\n")
+
+	return c.JSON(resp)
+}
+
+// HandleReport processes a PDF upload like HandleConvert and returns the
+// conversion report as a file download: self-contained HTML, or a PDF with
+// form field format=pdf.
+func HandleReport(c *fiber.Ctx) error {
+	dateLayout, err := writer.DateLayout(c.FormValue("dateFormat"))
+	if err != nil {
+		return writeError(c, fiber.StatusBadRequest, err.Error())
+	}
+	formatName := "report"
+	switch strings.ToLower(c.FormValue("format")) {
+	case "", "html":
+	case "pdf":
+		formatName = "report-pdf"
+	default:
+		return writeError(c, fiber.StatusBadRequest, fmt.Sprintf("Unknown report format %q. Use html or pdf.", c.FormValue("format")))
+	}
+	format, err := writer.LookupFormat(formatName)
+	if err != nil {
+		return writeError(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	up, err := parseUpload(c)
+	if err != nil {
+		return writeUploadError(c, err)
+	}
+
+	var report bytes.Buffer
+	if err := format.New(writer.Options{DateFormat: dateLayout}).Write(&report, up.info); err != nil {
+		return writeError(c, fiber.StatusInternalServerError, fmt.Sprintf("Report generation failed: %v", err))
+	}
+	name := strings.TrimSuffix(up.filename, filepath.Ext(up.filename)) + "-report" + format.Extension
+	c.Attachment(name)
+	c.Set(fiber.HeaderContentType, format.ContentType)
+	return c.Send(report.Bytes())
+}
+
+// upload is a parsed statement from a PDF upload.
+type upload struct {
+	info      *models.StatementInfo
+	bank      models.BankType
+	detection []models.BankCandidate // empty when the bank was given
+	pages     []string
+	filename  string // the uploaded file's name
+}
+
+// parseUpload extracts and parses the uploaded PDF (form field file), or
+// the client-extracted text (extractedText) when it is readable, with the
+// bank, dryRunDetect and password form fields. Errors are *fiber.Error with
+// the status to respond with, or the extractor's password errors.
+func parseUpload(c *fiber.Ctx) (*upload, error) {
 	// Get the uploaded file
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
-		return writeError(c, fiber.StatusBadRequest, "No file uploaded. Use form field 'file'.")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "No file uploaded. Use form field 'file'.")
 	}
 
 	// Validate it's a PDF
 	if !strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".pdf") {
-		return writeError(c, fiber.StatusBadRequest, "Only PDF files are supported.")
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Only PDF files are supported.")
 	}
 
-	// Get optional parameters
 	bankParam := c.FormValue("bank")
-	includeHeader := c.FormValue("header") != "false"
+	dryRunDetect := c.FormValue("dryRunDetect") == "true"
+	password := c.FormValue("password")
 
 	// Check if pre-extracted text was provided (from client-side pdf.js extraction)
 	extractedText := c.FormValue("extractedText")
@@ -93,69 +233,86 @@ func HandleConvert(c *fiber.Ctx) error {
 	if len(pages) == 0 {
 		tmpFile, err := os.CreateTemp("", "statement-*.pdf")
 		if err != nil {
-			return writeError(c, fiber.StatusInternalServerError, "Failed to create temp file.")
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to create temp file.")
 		}
 		defer os.Remove(tmpFile.Name())
 		defer tmpFile.Close()
 
-		// Save the uploaded file to a temp location
+		// Save the uploaded file to a temp location. Encrypted uploads are
+		// decrypted in memory, never written out decrypted.
 		if err := c.SaveFile(fileHeader, tmpFile.Name()); err != nil {
-			return writeError(c, fiber.StatusInternalServerError, "Failed to save uploaded file.")
+			return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to save uploaded file.")
 		}
 
 		var extractErr error
-		pages, extractErr = extractor.ExtractText(tmpFile.Name())
+		pages, extractErr = extractor.ExtractTextWithPassword(tmpFile.Name(), password)
+		if errors.Is(extractErr, extractor.ErrPasswordRequired) || errors.Is(extractErr, extractor.ErrWrongPassword) {
+			return nil, extractErr
+		}
 		if extractErr != nil {
-			return writeError(c, fiber.StatusUnprocessableEntity, fmt.Sprintf("PDF extraction failed: %v", extractErr))
+			return nil, fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("PDF extraction failed: %v", extractErr))
 		}
 	}
 
 	// Determine bank type
 	var bankType models.BankType
+	var detection []models.BankCandidate
 	if bankParam != "" {
-		switch strings.ToLower(bankParam) {
-		case "metro", "metrobank":
-			bankType = models.BankMetro
-		case "hsbc":
-			bankType = models.BankHSBC
-		case "barclays":
-			bankType = models.BankBarclays
-		default:
-			return writeError(c, fiber.StatusBadRequest, fmt.Sprintf("Unknown bank: %q. Use metro, hsbc, or barclays.", bankParam))
+		bt, ok := parser.LookupBank(bankParam)
+		if !ok {
+			return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Unknown bank: %q. Use %s.", bankParam, strings.Join(parser.SupportedBanks(), ", ")))
 		}
+		bankType = bt
 	} else {
-		detected, err := parser.AutoDetect(pages)
+		detected, candidates, err := parser.DetectBank(pages, dryRunDetect)
 		if err != nil {
-			return writeError(c, fiber.StatusUnprocessableEntity, err.Error())
+			return nil, fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
 		}
 		bankType = detected
+		detection = candidates
 	}
 
 	// Parse
 	p, err := parser.New(bankType)
 	if err != nil {
-		return writeError(c, fiber.StatusInternalServerError, err.Error())
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
 	}
 
 	info, err := p.Parse(pages)
 	if err != nil {
-		return writeError(c, fiber.StatusUnprocessableEntity, fmt.Sprintf("Parsing failed: %v", err))
+		return nil, fiber.NewError(fiber.StatusUnprocessableEntity, fmt.Sprintf("Parsing failed: %v", err))
 	}
+	return &upload{info: info, bank: bankType, detection: detection, pages: pages, filename: fileHeader.Filename}, nil
+}
 
-	// Generate CSV string
-	var csvBuf bytes.Buffer
-	csvWriter := &writer.CSVWriter{IncludeHeader: includeHeader}
-	if err := csvWriter.Write(&csvBuf, info); err != nil {
-		return writeError(c, fiber.StatusInternalServerError, fmt.Sprintf("CSV generation failed: %v", err))
+// writeUploadError responds with a parseUpload error.
+func writeUploadError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, extractor.ErrPasswordRequired):
+		return writeErrorCode(c, fiber.StatusUnprocessableEntity, ErrorPasswordRequired,
+			"The PDF is password-protected. Send its password in form field 'password'.")
+	case errors.Is(err, extractor.ErrWrongPassword):
+		return writeErrorCode(c, fiber.StatusUnprocessableEntity, ErrorWrongPassword,
+			"The PDF password is incorrect.")
+	}
+	if e, ok := err.(*fiber.Error); ok {
+		return writeError(c, e.Code, e.Message)
 	}
+	return writeError(c, fiber.StatusInternalServerError, err.Error())
+}
 
+// NewConvertResponse builds the successful response for a parsed
+// statement: account info, transactions, totals, validation and the
+// parser's debug lines. The CLI's json and ndjson formats write the same
+// document.
+func NewConvertResponse(info *models.StatementInfo, bank models.BankType) ConvertResponse {
 	// Calculate totals
-	var totalDebit, totalCredit float64
+	var totalDebit, totalCredit models.Money
 	for _, txn := range info.Transactions {
 		if txn.Type == "DEBIT" {
-			totalDebit += txn.Amount
+			totalDebit = totalDebit.Add(txn.Amount)
 		} else {
-			totalCredit += txn.Amount
+			totalCredit = totalCredit.Add(txn.Amount)
 		}
 	}
 
@@ -167,37 +324,43 @@ func HandleConvert(c *fiber.Ctx) error {
 
 	resp := ConvertResponse{
 		Success:      true,
-		Bank:         string(bankType),
+		Bank:         string(bank),
+		Validation:   validator.Validate(info),
 		Transactions: txns,
-		CSV:          csvBuf.String(),
 		TotalDebit:   totalDebit,
 		TotalCredit:  totalCredit,
 		Count:        len(txns),
 		Version:      apiVersion,
+		// Include debug lines for diagnosing parse issues
+		DebugLines: info.DebugLines,
 	}
 
-	if info.AccountHolder != "" || info.AccountNumber != "" || info.SortCode != "" || info.StatementPeriod != "" || info.OpeningBalance != 0 {
+	if info.AccountHolder != "" || info.AccountNumber != "" || info.SortCode != "" || info.StatementPeriod != "" || !info.OpeningBalance.IsZero() || !info.ClosingBalance.IsZero() || info.CreditCard {
 		resp.AccountInfo = &AccountInfo{
-			Holder:         info.AccountHolder,
-			Number:         info.AccountNumber,
-			SortCode:       info.SortCode,
-			Period:         info.StatementPeriod,
-			OpeningBalance: info.OpeningBalance,
+			Holder:           info.AccountHolder,
+			Number:           info.AccountNumber,
+			SortCode:         info.SortCode,
+			Period:           info.StatementPeriod,
+			OpeningBalance:   info.OpeningBalance,
+			ClosingBalance:   info.ClosingBalance,
+			CreditCard:       info.CreditCard,
+			StatementBalance: info.StatementBalance,
+			MinimumPayment:   info.MinimumPayment,
+			PaymentDueDate:   info.PaymentDueDate,
 		}
 	}
-
-	// Always include raw extracted text (helps debug parser issues)
-	resp.RawText = strings.Join(pages, "\n--- PAGE BREAK ---\n")
-
-	// Include debug lines for diagnosing parse issues
-	resp.DebugLines = info.DebugLines
-
-	return c.JSON(resp)
+	return resp
 }
 
 func writeError(c *fiber.Ctx, status int, msg string) error {
+	return writeErrorCode(c, status, "", msg)
+}
+
+// writeErrorCode is writeError with an ErrorCode.
+func writeErrorCode(c *fiber.Ctx, status int, code, msg string) error {
 	return c.Status(status).JSON(ConvertResponse{
-		Success: false,
-		Error:   msg,
+		Success:   false,
+		Error:     msg,
+		ErrorCode: code,
 	})
 }
@@ -1,9 +1,17 @@
 package api
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,6 +21,7 @@ func setupTestApp() *fiber.App {
 	app := fiber.New()
 	app.Get("/api/health", HandleHealth)
 	app.Post("/api/convert", HandleConvert)
+	app.Post("/api/report", HandleReport)
 	return app
 }
 
@@ -59,3 +68,342 @@ func TestConvertEndpointRequiresFile(t *testing.T) {
 		t.Error("expected non-200 for missing file")
 	}
 }
+
+// hsbcStatementText is client-side extracted text for a small HSBC statement
+// that mentions Barclays as a payee.
+const hsbcStatementText = `HSBC UK Bank plc
+Your Statement
+Sort Code 40-12-34 Account Number 12345678
+Date Payment type and details Paid out Paid in Balance
+02 Jan 24 BALANCE BROUGHT FORWARD 1,025.00
+02 Jan 24 DD BARCLAYS PARTNER FIN 25.00 1,000.00`
+
+// newConvertRequest builds a multipart /api/convert request with a dummy PDF
+// and the given form fields.
+func newConvertRequest(t *testing.T, fields map[string]string) *http.Request {
+	t.Helper()
+	return newUploadRequest(t, "/api/convert", fields)
+}
+
+// newUploadRequest builds a multipart request to path with a dummy PDF and
+// the given form fields.
+func newUploadRequest(t *testing.T, path string, fields map[string]string) *http.Request {
+	t.Helper()
+	return newPDFUploadRequest(t, path, []byte("%PDF-1.4 placeholder"), fields)
+}
+
+// newPDFUploadRequest uploads pdf as the statement file.
+func newPDFUploadRequest(t *testing.T, path string, pdf []byte, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "statement.pdf")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	fw.Write(pdf)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	mw.Close()
+
+	req := httptest.NewRequest("POST", path, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestConvertEndpointReturnsDetection(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	var result ConvertResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Bank != "hsbc" {
+		t.Errorf("bank: got %q, want hsbc", result.Bank)
+	}
+	if len(result.Detection) < 2 {
+		t.Fatalf("detection: got %+v, want HSBC ranked above Barclays", result.Detection)
+	}
+	if result.Detection[0].Bank != "hsbc" || result.Detection[0].Confidence <= result.Detection[1].Confidence {
+		t.Errorf("detection ranking: got %+v", result.Detection)
+	}
+
+	// An explicit bank skips detection
+	resp, err = app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	result = ConvertResponse{}
+	json.Unmarshal(body, &result)
+	if len(result.Detection) != 0 {
+		t.Errorf("detection should be omitted when bank is given: got %+v", result.Detection)
+	}
+}
+
+func TestConvertEndpointReturnsValidation(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	var result ConvertResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Validation == nil {
+		t.Fatalf("validation missing from response: %s", body)
+	}
+	if !result.Validation.Valid || result.Validation.Checked != 1 {
+		t.Errorf("validation: got %+v, want valid with 1 balance checked", result.Validation)
+	}
+}
+
+func TestConvertEndpointDateFormat(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc", "dateFormat": "iso"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	var result ConvertResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(result.Transactions) == 0 {
+		t.Fatalf("no transactions: %s", body)
+	}
+	txn := result.Transactions[len(result.Transactions)-1]
+	if txn.Date != "2024-01-02" || txn.ISODate.String() != "2024-01-02" {
+		t.Errorf("date: got %q (isoDate %q), want 2024-01-02", txn.Date, txn.ISODate)
+	}
+	if !strings.Contains(result.CSV, "2024-01-02,") {
+		t.Errorf("CSV should use the same date format:\n%s", result.CSV)
+	}
+
+	resp, err = app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "dateFormat": "yyyy"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("invalid dateFormat: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestConvertEndpointFormat(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc", "format": "ofx"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	var result ConvertResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if result.Format != "ofx" {
+		t.Errorf("format: got %q, want ofx", result.Format)
+	}
+	if !strings.Contains(result.Output, "<BANKMSGSRSV1>") || !strings.Contains(result.Output, "<ACCTID>12345678</ACCTID>") {
+		t.Errorf("expected an OFX bank statement:\n%s", result.Output)
+	}
+	if result.CSV == "" {
+		t.Error("CSV should still be returned for the UI")
+	}
+
+	// Binary formats come back base64-encoded
+	resp, err = app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc", "format": "xlsx"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	result = ConvertResponse{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	workbook, err := base64.StdEncoding.DecodeString(result.Output)
+	if result.OutputEncoding != "base64" || err != nil || !bytes.HasPrefix(workbook, []byte("PK")) {
+		t.Errorf("expected a base64 XLSX workbook, got encoding %q: %v", result.OutputEncoding, err)
+	}
+
+	resp, err = app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "format": "pdf"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("unknown format: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestConvertEndpointCSVSchema(t *testing.T) {
+	app := setupTestApp()
+
+	schema := `{"columns": ["date", {"field": "description", "header": "Details"}, "signedAmount"], "dateFormat": "iso", "delimiter": ";"}`
+	resp, err := app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc", "header": "false", "csvSchema": schema}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var result ConvertResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !strings.HasPrefix(result.CSV, "Date;Details;Amount\n") || !strings.Contains(result.CSV, "2024-01-02;DD BARCLAYS PARTNER FIN;-25.00\n") {
+		t.Errorf("CSV should follow the schema:\n%s", result.CSV)
+	}
+
+	resp, err = app.Test(newConvertRequest(t, map[string]string{"extractedText": hsbcStatementText, "csvSchema": `{"columns": ["payee"]}`}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("invalid csvSchema: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestConvertEndpointSanitizesCSV(t *testing.T) {
+	app := setupTestApp()
+	text := strings.Replace(hsbcStatementText, "DD BARCLAYS PARTNER FIN", "=HYPERLINK(\"http://evil.example\")", 1)
+
+	for _, tt := range []struct {
+		sanitize string
+		want     string
+	}{
+		{"", `,"'=HYPERLINK(""http://evil.example"")",`},
+		{"false", `,"=HYPERLINK(""http://evil.example"")",`},
+	} {
+		resp, err := app.Test(newConvertRequest(t, map[string]string{"extractedText": text, "bank": "hsbc", "sanitize": tt.sanitize}))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		var result ConvertResponse
+		if err := json.Unmarshal(body, &result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !strings.Contains(result.CSV, tt.want) {
+			t.Errorf("sanitize=%q: expected %s in CSV:\n%s", tt.sanitize, tt.want, result.CSV)
+		}
+	}
+}
+
+func TestReportEndpoint(t *testing.T) {
+	app := setupTestApp()
+
+	resp, err := app.Test(newUploadRequest(t, "/api/report", map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") ||
+		!strings.Contains(resp.Header.Get("Content-Disposition"), "statement-report.html") {
+		t.Errorf("headers: got %q, %q", resp.Header.Get("Content-Type"), resp.Header.Get("Content-Disposition"))
+	}
+	if !strings.Contains(string(body), "<h2>Reconciliation</h2>") || !strings.Contains(string(body), "DD BARCLAYS PARTNER FIN") {
+		t.Errorf("expected an HTML report:\n%s", body)
+	}
+
+	resp, err = app.Test(newUploadRequest(t, "/api/report", map[string]string{"extractedText": hsbcStatementText, "bank": "hsbc", "format": "pdf"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	if resp.Header.Get("Content-Type") != "application/pdf" || !bytes.HasPrefix(body, []byte("%PDF-")) {
+		t.Errorf("expected a PDF report, got %q: %s", resp.Header.Get("Content-Type"), body)
+	}
+
+	resp, err = app.Test(newUploadRequest(t, "/api/report", map[string]string{"extractedText": hsbcStatementText, "format": "docx"}))
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("unknown report format: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// encryptedPDF is an empty PDF encrypted with RC4 under a user password.
+func encryptedPDF() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, 3)
+	for i, obj := range []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [] /Count 0 >>",
+		"<< /Filter /Standard /V 2 /R 3 /Length 128 /P -3904 /O <" + strings.Repeat("4f", 32) + "> /U <" + strings.Repeat("55", 32) + "> >>",
+	} {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	buf.WriteString("xref\n0 4\n0000000000 65535 f \n")
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size 4 /Root 1 0 R /Encrypt 3 0 R /ID [<0123> <0123>] >>\nstartxref\n%d\n%%%%EOF\n", xref)
+	return buf.Bytes()
+}
+
+func TestConvertEndpointPasswordErrors(t *testing.T) {
+	app := setupTestApp()
+	tempFiles := func() []string {
+		files, _ := filepath.Glob(filepath.Join(os.TempDir(), "statement-*.pdf"))
+		return files
+	}
+	before := len(tempFiles())
+
+	for _, tt := range []struct {
+		password, code string
+	}{
+		{"", ErrorPasswordRequired},
+		{"01011990", ErrorWrongPassword},
+	} {
+		resp, err := app.Test(newPDFUploadRequest(t, "/api/convert", encryptedPDF(), map[string]string{"password": tt.password}))
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		var result ConvertResponse
+		body, _ := io.ReadAll(resp.Body)
+		if err := json.Unmarshal(body, &result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusUnprocessableEntity || result.Success || result.ErrorCode != tt.code {
+			t.Errorf("password %q: got %d %s, want 422 with errorCode %s", tt.password, resp.StatusCode, body, tt.code)
+		}
+	}
+
+	if after := len(tempFiles()); after != before {
+		t.Errorf("temp files: %d before, %d after", before, after)
+	}
+}
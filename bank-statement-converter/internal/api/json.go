@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+	"github.com/insightdelivered/bank-statement-converter/internal/writer"
+)
+
+// JSONFormats returns the json and ndjson output formats, which write the
+// same document /api/convert returns. The writer package doesn't know the
+// response schema, so main registers them with writer.RegisterFormat.
+func JSONFormats() []writer.Format {
+	return []writer.Format{
+		{
+			Name:        "json",
+			Extension:   ".json",
+			ContentType: "application/json",
+			New: func(opts writer.Options) writer.StatementWriter {
+				return &JSONWriter{DateFormat: opts.DateFormat, DebugLines: opts.DebugLines}
+			},
+		},
+		{
+			Name:        "ndjson",
+			Extension:   ".ndjson",
+			ContentType: "application/x-ndjson",
+			New: func(opts writer.Options) writer.StatementWriter {
+				return &JSONWriter{NDJSON: true, DateFormat: opts.DateFormat, DebugLines: opts.DebugLines}
+			},
+		},
+	}
+}
+
+// JSONWriter writes statements as ConvertResponse documents: account info,
+// transactions, totals and validation, as /api/convert returns them
+// without the CSV and raw text. Several statements are a JSON array, or
+// with NDJSON one compact document per line.
+type JSONWriter struct {
+	NDJSON bool
+	// DateFormat is the Go layout for the transactions' date fields (see
+	// writer.DateLayout); empty keeps them as printed.
+	DateFormat string
+	// DebugLines includes the parser's debug lines.
+	DebugLines bool
+}
+
+// Write writes the statement's response document to out.
+func (w *JSONWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	return w.encode(out, w.response(info))
+}
+
+// WriteAll writes the statements' response documents to out.
+func (w *JSONWriter) WriteAll(out io.Writer, infos []*models.StatementInfo) error {
+	if !w.NDJSON {
+		resps := make([]ConvertResponse, len(infos))
+		for i, info := range infos {
+			resps[i] = w.response(info)
+		}
+		return w.encode(out, resps)
+	}
+	for _, info := range infos {
+		if err := w.encode(out, w.response(info)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *JSONWriter) response(info *models.StatementInfo) ConvertResponse {
+	// Reformat dates on a copy; the caller's statement is unchanged
+	copied := *info
+	copied.Transactions = slices.Clone(info.Transactions)
+	writer.ApplyDateFormat(copied.Transactions, w.DateFormat)
+
+	resp := NewConvertResponse(&copied, info.Bank)
+	if !w.DebugLines {
+		resp.DebugLines = nil
+	}
+	return resp
+}
+
+func (w *JSONWriter) encode(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	if !w.NDJSON {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+	"github.com/insightdelivered/bank-statement-converter/internal/writer"
+)
+
+func jsonStatement() *models.StatementInfo {
+	return &models.StatementInfo{
+		Bank:           models.BankHSBC,
+		AccountNumber:  "12345678",
+		SortCode:       "40-12-34",
+		OpeningBalance: models.Pence(102500),
+		Transactions: []models.Transaction{
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "BALANCE BROUGHT FORWARD", Type: "BALANCE", Balance: models.Pence(102500)},
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "DD BARCLAYS PARTNER FIN", Type: "DEBIT", Amount: models.Pence(2500), Balance: models.Pence(100000)},
+		},
+		DebugLines: []models.DebugLine{{LineNum: 1, Text: "02 Jan 24 BALANCE BROUGHT FORWARD", Result: "parsed"}},
+	}
+}
+
+func TestJSONWriter_Write(t *testing.T) {
+	formats := JSONFormats()
+	if len(formats) != 2 || formats[0].Name != "json" || formats[1].Name != "ndjson" {
+		t.Fatalf("JSONFormats: got %v", formats)
+	}
+	f := formats[0]
+	info := jsonStatement()
+	var buf bytes.Buffer
+	if err := f.New(writer.Options{DateFormat: "2006-01-02"}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var resp ConvertResponse
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("output is not a ConvertResponse: %v\n%s", err, buf.String())
+	}
+	if !resp.Success || resp.Bank != "hsbc" || resp.Count != 2 {
+		t.Errorf("response: got success %v bank %q count %d", resp.Success, resp.Bank, resp.Count)
+	}
+	if resp.AccountInfo == nil || resp.AccountInfo.SortCode != "40-12-34" {
+		t.Errorf("accountInfo: got %+v", resp.AccountInfo)
+	}
+	if resp.TotalDebit.String() != "25.00" {
+		t.Errorf("totalDebit: got %s, want 25.00", resp.TotalDebit)
+	}
+	if resp.Validation == nil || !resp.Validation.Valid {
+		t.Errorf("validation: got %+v", resp.Validation)
+	}
+	if resp.Transactions[1].Date != "2024-01-02" {
+		t.Errorf("date: got %q, want 2024-01-02", resp.Transactions[1].Date)
+	}
+	if info.Transactions[1].Date != "02 Jan 24" {
+		t.Error("writing JSON should not change the statement's dates")
+	}
+	if len(resp.DebugLines) != 0 {
+		t.Error("debug lines should be omitted unless requested")
+	}
+
+	buf.Reset()
+	if err := f.New(writer.Options{DebugLines: true}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"debugLines"`) {
+		t.Error("expected debug lines with DebugLines set")
+	}
+}
+
+func TestJSONWriter_WriteAll(t *testing.T) {
+	infos := []*models.StatementInfo{jsonStatement(), jsonStatement()}
+
+	var buf bytes.Buffer
+	if err := (&JSONWriter{NDJSON: true}).WriteAll(&buf, infos); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per statement, got %d:\n%s", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		var resp ConvertResponse
+		if err := json.Unmarshal([]byte(line), &resp); err != nil || resp.Count != 2 {
+			t.Errorf("line %d: %v (count %d)", i, err, resp.Count)
+		}
+	}
+
+	buf.Reset()
+	if err := (&JSONWriter{}).WriteAll(&buf, infos); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var resps []ConvertResponse
+	if err := json.Unmarshal(buf.Bytes(), &resps); err != nil || len(resps) != 2 {
+		t.Errorf("expected a JSON array of 2 responses: %v", err)
+	}
+}
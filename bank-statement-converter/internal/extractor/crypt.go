@@ -0,0 +1,431 @@
+package extractor
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// Errors for encrypted PDFs that cannot be opened. Banks that email
+// statements often protect them with the customer's date of birth or
+// postcode.
+var (
+	// ErrPasswordRequired is returned when no password was given and the
+	// file does not open without one.
+	ErrPasswordRequired = errors.New("PDF is password-protected")
+	// ErrWrongPassword is returned when the password given is neither the
+	// file's user nor its owner password.
+	ErrWrongPassword = errors.New("incorrect PDF password")
+)
+
+// cryptMethod is how a crypt filter encrypts strings or streams.
+type cryptMethod int
+
+const (
+	cryptNone  cryptMethod = iota // Identity: not encrypted
+	cryptRC4                      // V2
+	cryptAESV2                    // AES-128, with per-object keys
+	cryptAESV3                    // AES-256, with the file key
+)
+
+// passwordPad pads passwords to 32 bytes for revisions 2 to 4.
+var passwordPad = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41, 0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80, 0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// securityHandler decrypts a document encrypted with the standard
+// security handler: RC4 of 40 to 128 bits and AES-128 (revisions 2 to 4),
+// and AES-256 (revisions 5 and 6).
+type securityHandler struct {
+	v, r            int
+	length          int // key length in bytes, for revisions 2 to 4
+	o, u, oe, ue    []byte
+	p               uint32
+	id              []byte // the first file identifier
+	encryptMetadata bool
+	stmF, strF      cryptMethod
+
+	key          []byte
+	userPassword []byte // for revisions 2 to 4, the user password
+	encryptNum   int    // the /Encrypt dictionary's object, which is not encrypted
+}
+
+// newSecurityHandler reads the trailer's /Encrypt dictionary and finds the
+// file key from password, tried as the user and then the owner password.
+// Files that open with an empty user password open whatever password is
+// given, as readers do.
+func newSecurityHandler(d *rawDoc, password string) (*securityHandler, error) {
+	enc := d.dict(d.trailer["Encrypt"])
+	if enc == nil {
+		return nil, fmt.Errorf("malformed PDF: unreadable /Encrypt dictionary")
+	}
+	if filter := d.resolve(enc["Filter"]); filter != pdfName("Standard") {
+		return nil, fmt.Errorf("unsupported PDF encryption: %v security handler", filter)
+	}
+	h := &securityHandler{
+		v:               intParm(enc, "V", 0),
+		r:               intParm(enc, "R", 0),
+		o:               pdfBytes(d.resolve(enc["O"])),
+		u:               pdfBytes(d.resolve(enc["U"])),
+		oe:              pdfBytes(d.resolve(enc["OE"])),
+		ue:              pdfBytes(d.resolve(enc["UE"])),
+		p:               uint32(intParm(enc, "P", 0)),
+		encryptMetadata: d.resolve(enc["EncryptMetadata"]) != false,
+	}
+	if ref, ok := d.trailer["Encrypt"].(pdfRef); ok {
+		h.encryptNum = ref.num
+	}
+	if ids := d.array(d.trailer["ID"]); len(ids) > 0 {
+		h.id = pdfBytes(d.resolve(ids[0]))
+	}
+
+	switch h.v {
+	case 1, 2:
+		h.stmF, h.strF = cryptRC4, cryptRC4
+		h.length = intParm(enc, "Length", 40) / 8
+		if h.v == 1 {
+			h.length = 5
+		}
+	case 4, 5:
+		cf := d.dict(enc["CF"])
+		h.stmF = cryptFilterMethod(d, cf, enc["StmF"])
+		h.strF = cryptFilterMethod(d, cf, enc["StrF"])
+		h.length = intParm(enc, "Length", 128) / 8
+	default:
+		return nil, fmt.Errorf("unsupported PDF encryption: version %d", h.v)
+	}
+
+	var minLen int
+	switch h.r {
+	case 2, 3, 4:
+		minLen = 32
+		if h.length < 5 || h.length > 16 {
+			return nil, fmt.Errorf("malformed PDF: %d-bit encryption key", h.length*8)
+		}
+	case 5, 6:
+		minLen = 48
+		if len(h.oe) < 32 || len(h.ue) < 32 {
+			return nil, fmt.Errorf("malformed PDF: missing /OE or /UE")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PDF encryption: revision %d", h.r)
+	}
+	if len(h.o) < minLen || len(h.u) < minLen {
+		return nil, fmt.Errorf("malformed PDF: missing /O or /U")
+	}
+
+	if h.authenticate([]byte(password)) || password != "" && h.authenticate(nil) {
+		return h, nil
+	}
+	if password == "" {
+		return nil, ErrPasswordRequired
+	}
+	return nil, ErrWrongPassword
+}
+
+// cryptFilterMethod returns the method of the crypt filter a /StmF or
+// /StrF names; Identity, the default, does not encrypt.
+func cryptFilterMethod(d *rawDoc, cf pdfDict, name interface{}) cryptMethod {
+	n, _ := d.resolve(name).(pdfName)
+	if n == "" || n == "Identity" {
+		return cryptNone
+	}
+	switch d.resolve(d.dict(cf[n])["CFM"]) {
+	case pdfName("V2"):
+		return cryptRC4
+	case pdfName("AESV2"):
+		return cryptAESV2
+	case pdfName("AESV3"):
+		return cryptAESV3
+	}
+	return cryptNone
+}
+
+// authenticate sets the file key if password is the user or the owner
+// password.
+func (h *securityHandler) authenticate(password []byte) bool {
+	if h.r >= 5 {
+		return h.authenticateAES256(password)
+	}
+	if key := h.rc4FileKey(password); h.checkUserKey(key) {
+		h.key, h.userPassword = key, password
+		return true
+	}
+
+	// The owner password decrypts /O to the user password. Unlike the
+	// file key, each round hashes the whole digest (algorithm 3)
+	key := md5.Sum(padPassword(password))
+	if h.r >= 3 {
+		for i := 0; i < 50; i++ {
+			key = md5.Sum(key[:])
+		}
+	}
+	k := key[:]
+	user := append([]byte(nil), h.o[:32]...)
+	if h.r == 2 {
+		rc4XOR(k[:h.length], user)
+	} else {
+		for i := 19; i >= 0; i-- {
+			rc4XOR(xorKey(k[:h.length], byte(i)), user)
+		}
+	}
+	if key := h.rc4FileKey(user); h.checkUserKey(key) {
+		h.key, h.userPassword = key, unpadPassword(user)
+		return true
+	}
+	return false
+}
+
+// rc4FileKey computes the file key from a user password (algorithm 2).
+func (h *securityHandler) rc4FileKey(password []byte) []byte {
+	m := md5.New()
+	m.Write(padPassword(password))
+	m.Write(h.o[:32])
+	m.Write([]byte{byte(h.p), byte(h.p >> 8), byte(h.p >> 16), byte(h.p >> 24)})
+	m.Write(h.id)
+	if h.r >= 4 && !h.encryptMetadata {
+		m.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+	}
+	key := m.Sum(nil)
+	n := h.length
+	if h.r == 2 {
+		n = 5
+	}
+	if h.r >= 3 {
+		for i := 0; i < 50; i++ {
+			sum := md5.Sum(key[:n])
+			key = sum[:]
+		}
+	}
+	return key[:n]
+}
+
+// checkUserKey reports whether key encrypts to the /U value, as a key made
+// from the right user password does (algorithms 4 and 5).
+func (h *securityHandler) checkUserKey(key []byte) bool {
+	if h.r == 2 {
+		u := append([]byte(nil), passwordPad...)
+		rc4XOR(key, u)
+		return bytes.Equal(u, h.u[:32])
+	}
+	sum := md5.Sum(append(append([]byte(nil), passwordPad...), h.id...))
+	u := sum[:]
+	for i := 0; i < 20; i++ {
+		rc4XOR(xorKey(key, byte(i)), u)
+	}
+	return bytes.Equal(u, h.u[:16])
+}
+
+// authenticateAES256 checks password against /U and then /O, each a
+// 32-byte hash, a validation salt and a key salt, and decrypts the file
+// key from /UE or /OE (algorithm 2.A).
+func (h *securityHandler) authenticateAES256(password []byte) bool {
+	if len(password) > 127 {
+		password = password[:127]
+	}
+	var intermediate, wrapped []byte
+	switch {
+	case bytes.Equal(h.hash(password, h.u[32:40], nil), h.u[:32]):
+		intermediate, wrapped = h.hash(password, h.u[40:48], nil), h.ue
+	case bytes.Equal(h.hash(password, h.o[32:40], h.u[:48]), h.o[:32]):
+		intermediate, wrapped = h.hash(password, h.o[40:48], h.u[:48]), h.oe
+	default:
+		return false
+	}
+	block, err := aes.NewCipher(intermediate)
+	if err != nil {
+		return false
+	}
+	h.key = make([]byte, 32)
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(h.key, wrapped[:32])
+	return true
+}
+
+// hash is the password hash of revision 5, a SHA-256, or of revision 6,
+// rounds of AES and SHA-2 (algorithm 2.B).
+func (h *securityHandler) hash(password, salt, udata []byte) []byte {
+	k := sha256.Sum256(bytes.Join([][]byte{password, salt, udata}, nil))
+	if h.r == 5 {
+		return k[:]
+	}
+	key := k[:]
+	var e []byte
+	for round := 0; round < 64 || int(e[len(e)-1]) > round-32; round++ {
+		k1 := bytes.Repeat(bytes.Join([][]byte{password, key, udata}, nil), 64)
+		block, _ := aes.NewCipher(key[:16])
+		e = make([]byte, len(k1))
+		cipher.NewCBCEncrypter(block, key[16:32]).CryptBlocks(e, k1)
+
+		// The first 16 bytes of E as a number, modulo 3, choose the hash
+		sum := 0
+		for _, c := range e[:16] {
+			sum += int(c)
+		}
+		var next hash.Hash
+		switch sum % 3 {
+		case 0:
+			next = sha256.New()
+		case 1:
+			next = sha512.New384()
+		default:
+			next = sha512.New()
+		}
+		next.Write(e)
+		key = next.Sum(nil)
+	}
+	return key[:32]
+}
+
+// libraryPassword returns the user password for the PDF library, and
+// whether the library can decrypt the file: it reads revisions 2 to 4, but
+// derives 40-bit object keys wrongly.
+func (h *securityHandler) libraryPassword() (string, bool) {
+	return string(h.userPassword), h.r <= 4 && len(h.key) == 16
+}
+
+// decryptObject decrypts the strings of object num, generation gen, and a
+// stream's data, in place. Cross-reference streams are not encrypted, nor
+// is metadata when /EncryptMetadata is false, nor streams with their own
+// /Crypt filter.
+func (h *securityHandler) decryptObject(v interface{}, num, gen int) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return h.decrypt(h.strF, num, gen, v)
+	case pdfHex:
+		return pdfHex(h.decrypt(h.strF, num, gen, v))
+	case []interface{}:
+		for i := range v {
+			v[i] = h.decryptObject(v[i], num, gen)
+		}
+	case pdfDict:
+		for k, x := range v {
+			v[k] = h.decryptObject(x, num, gen)
+		}
+	case *pdfStream:
+		switch v.dict["Type"] {
+		case pdfName("XRef"):
+			return v
+		case pdfName("Metadata"):
+			if !h.encryptMetadata {
+				return v
+			}
+		}
+		h.decryptObject(v.dict, num, gen)
+		if !hasCryptFilter(v.dict["Filter"]) {
+			v.data = h.decrypt(h.stmF, num, gen, v.data)
+		}
+	}
+	return v
+}
+
+func hasCryptFilter(filter interface{}) bool {
+	if filters, ok := filter.([]interface{}); ok {
+		for _, f := range filters {
+			if f == pdfName("Crypt") {
+				return true
+			}
+		}
+	}
+	return filter == pdfName("Crypt")
+}
+
+// decrypt decrypts a string or stream of object num with method. AES data
+// starts with its initialisation vector and ends with PKCS#5 padding.
+func (h *securityHandler) decrypt(method cryptMethod, num, gen int, data []byte) []byte {
+	if method == cryptNone {
+		return data
+	}
+	key := h.objectKey(method, num, gen)
+	if method == cryptRC4 {
+		out := append([]byte(nil), data...)
+		rc4XOR(key, out)
+		return out
+	}
+
+	data = data[:len(data)/aes.BlockSize*aes.BlockSize]
+	if len(data) < 2*aes.BlockSize {
+		return nil
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil
+	}
+	out := make([]byte, len(data)-aes.BlockSize)
+	cipher.NewCBCDecrypter(block, data[:aes.BlockSize]).CryptBlocks(out, data[aes.BlockSize:])
+	if pad := int(out[len(out)-1]); pad >= 1 && pad <= aes.BlockSize {
+		out = out[:len(out)-pad]
+	}
+	return out
+}
+
+// objectKey returns the key for object num's strings and streams: the
+// file key for AES-256, else one made from it and the object's number
+// (algorithm 1).
+func (h *securityHandler) objectKey(method cryptMethod, num, gen int) []byte {
+	if method == cryptAESV3 {
+		return h.key
+	}
+	m := md5.New()
+	m.Write(h.key)
+	m.Write([]byte{byte(num), byte(num >> 8), byte(num >> 16), byte(gen), byte(gen >> 8)})
+	if method == cryptAESV2 {
+		m.Write([]byte("sAlT"))
+	}
+	key := m.Sum(nil)
+	if n := len(h.key) + 5; n < len(key) {
+		key = key[:n]
+	}
+	return key
+}
+
+// padPassword pads or truncates a password to 32 bytes.
+func padPassword(password []byte) []byte {
+	if len(password) > 32 {
+		password = password[:32]
+	}
+	return append(append([]byte(nil), password...), passwordPad[:32-len(password)]...)
+}
+
+// unpadPassword returns the password a padded one was made from.
+func unpadPassword(padded []byte) []byte {
+	for n := 0; n < len(padded); n++ {
+		if bytes.HasPrefix(passwordPad, padded[n:]) {
+			return padded[:n]
+		}
+	}
+	return padded
+}
+
+func rc4XOR(key, data []byte) {
+	c, err := rc4.NewCipher(key)
+	if err == nil {
+		c.XORKeyStream(data, data)
+	}
+}
+
+func xorKey(key []byte, b byte) []byte {
+	out := make([]byte, len(key))
+	for i := range key {
+		out[i] = key[i] ^ b
+	}
+	return out
+}
+
+// pdfBytes returns a literal or hex string's bytes.
+func pdfBytes(v interface{}) []byte {
+	switch v := v.(type) {
+	case []byte:
+		return v
+	case pdfHex:
+		return v
+	}
+	return nil
+}
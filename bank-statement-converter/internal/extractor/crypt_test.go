@@ -0,0 +1,238 @@
+package extractor
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var testFileID = []byte("statement-file-id")
+
+// rc4Encryption sets up a handler for RC4 (version 1 or 2) or, with
+// version 4, AES-128 encryption.
+func rc4Encryption(v, r, bits int, user, owner string) *securityHandler {
+	h := &securityHandler{v: v, r: r, length: bits / 8, p: 0xFFFFF0C0, id: testFileID, encryptMetadata: true}
+	h.stmF, h.strF = cryptRC4, cryptRC4
+	if v == 4 {
+		h.stmF, h.strF = cryptAESV2, cryptAESV2
+	}
+
+	// /O is the padded user password encrypted with the owner password
+	sum := md5.Sum(padPassword([]byte(owner)))
+	if r >= 3 {
+		for i := 0; i < 50; i++ {
+			sum = md5.Sum(sum[:])
+		}
+	}
+	ownerKey := sum[:]
+	h.o = padPassword([]byte(user))
+	for i := 0; i < 20 && (i == 0 || r >= 3); i++ {
+		rc4XOR(xorKey(ownerKey[:h.length], byte(i)), h.o)
+	}
+
+	h.key = h.rc4FileKey([]byte(user))
+	if r == 2 {
+		h.u = append([]byte(nil), passwordPad...)
+		rc4XOR(h.key, h.u)
+	} else {
+		sum = md5.Sum(append(append([]byte(nil), passwordPad...), testFileID...))
+		h.u = append(sum[:], make([]byte, 16)...)
+		for i := 0; i < 20; i++ {
+			rc4XOR(xorKey(h.key, byte(i)), h.u[:16])
+		}
+	}
+	return h
+}
+
+// aes256Encryption sets up a handler for AES-256 encryption, revision 6.
+func aes256Encryption(user, owner string) *securityHandler {
+	h := &securityHandler{v: 5, r: 6, stmF: cryptAESV3, strF: cryptAESV3, encryptMetadata: true}
+	h.key = []byte("0123456789abcdef0123456789ABCDEF")
+	wrap := func(key []byte) []byte {
+		block, _ := aes.NewCipher(key)
+		out := make([]byte, 32)
+		cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(out, h.key)
+		return out
+	}
+	h.u = append(h.hash([]byte(user), []byte("uvsaltxx"), nil), "uvsaltxxukeysalt"...)
+	h.ue = wrap(h.hash([]byte(user), []byte("ukeysalt"), nil))
+	h.o = append(h.hash([]byte(owner), []byte("ovsaltxx"), h.u), "ovsaltxxokeysalt"...)
+	h.oe = wrap(h.hash([]byte(owner), []byte("okeysalt"), h.u))
+	return h
+}
+
+// encryptDict returns the /Encrypt dictionary for h.
+func encryptDict(h *securityHandler) string {
+	enc := fmt.Sprintf("<< /Filter /Standard /V %d /R %d /Length %d /P -3904 /O <%x> /U <%x>", h.v, h.r, len(h.key)*8, h.o, h.u)
+	switch h.v {
+	case 4:
+		enc += " /CF << /StdCF << /CFM /AESV2 /AuthEvent /DocOpen /Length 16 >> >> /StmF /StdCF /StrF /StdCF"
+	case 5:
+		enc += fmt.Sprintf(" /OE <%x> /UE <%x> /Perms <%x>", h.oe, h.ue, make([]byte, 16)) +
+			" /CF << /StdCF << /CFM /AESV3 /AuthEvent /DocOpen /Length 32 >> >> /StmF /StdCF /StrF /StdCF"
+	}
+	return enc + " >>"
+}
+
+// encrypt encrypts object num's string or stream data as h decrypts it.
+func encrypt(h *securityHandler, method cryptMethod, num int, data []byte) []byte {
+	if method != cryptAESV2 && method != cryptAESV3 {
+		return h.decrypt(method, num, 0, data)
+	}
+	pad := aes.BlockSize - len(data)%aes.BlockSize
+	data = append(append([]byte(nil), data...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	iv := []byte("initialisation v")
+	block, _ := aes.NewCipher(h.objectKey(method, num, 0))
+	out := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+	return append(iv, out...)
+}
+
+const encryptedContent = "BT /F1 10 Tf 50 750 Td (Statement for Miss J Smith, sort code 12-34-56) Tj ET\n" +
+	"BT /F1 10 Tf 50 730 Td (01 Mar 2024 Card payment TESCO STORES 12.50 987.50) Tj ET\n" +
+	"BT /F1 10 Tf 50 710 Td (02 Mar 2024 Salary ACME LTD 2,000.00 2,987.50) Tj ET"
+
+// encryptedPDF writes a one-page PDF encrypted by h, with its /Encrypt
+// dictionary enc, and an encrypted /Title in its document information.
+func encryptedPDF(h *securityHandler, enc string) []byte {
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [4 0 R] /Count 1 >>",
+		helvetica,
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R >> >> /Contents 5 0 R >>",
+		streamObject("", string(encrypt(h, h.stmF, 5, []byte(encryptedContent)))),
+		enc,
+		fmt.Sprintf("<< /Title <%x> >>", encrypt(h, h.strF, 7, []byte("March 2024"))),
+	)
+	trailer := fmt.Sprintf("/Root 1 0 R /Encrypt 6 0 R /Info 7 0 R /ID [<%x> <%x>] >>", testFileID, testFileID)
+	return bytes.Replace(data, []byte("/Root 1 0 R >>"), []byte(trailer), 1)
+}
+
+func TestExtractTextWithPassword(t *testing.T) {
+	const owner = "bank-owner"
+	tests := []struct {
+		name      string
+		h         *securityHandler
+		user      string
+		byLibrary bool
+	}{
+		{"RC4 40-bit", rc4Encryption(1, 2, 40, "01011990", owner), "01011990", false},
+		{"RC4 40-bit revision 3", rc4Encryption(2, 3, 40, "01011990", owner), "01011990", false},
+		{"RC4 128-bit", rc4Encryption(2, 3, 128, "01011990", owner), "01011990", true},
+		{"AES-128", rc4Encryption(4, 4, 128, "01011990", owner), "01011990", true},
+		{"AES-256", aes256Encryption("SW1A 1AA", owner), "SW1A 1AA", false},
+	}
+	for _, tt := range tests {
+		data := encryptedPDF(tt.h, encryptDict(tt.h))
+		path := writePDFFile(t, data)
+
+		if _, err := ExtractText(path); !errors.Is(err, ErrPasswordRequired) {
+			t.Errorf("%s: ExtractText error = %v, want ErrPasswordRequired", tt.name, err)
+		}
+		if _, err := ExtractTextRaw(path); !errors.Is(err, ErrPasswordRequired) {
+			t.Errorf("%s: ExtractTextRaw error = %v, want ErrPasswordRequired", tt.name, err)
+		}
+		if _, err := ExtractTextWithPassword(path, "31121999"); !errors.Is(err, ErrWrongPassword) {
+			t.Errorf("%s: wrong password error = %v, want ErrWrongPassword", tt.name, err)
+		}
+
+		for _, password := range []string{tt.user, owner} {
+			pages, err := ExtractTextWithPassword(path, password)
+			if err != nil {
+				t.Errorf("%s: password %q: %v", tt.name, password, err)
+				continue
+			}
+			text := strings.Join(pages, "\n")
+			if !strings.Contains(text, "TESCO STORES 12.50 987.50") || !strings.Contains(text, "Salary ACME LTD") {
+				t.Errorf("%s: password %q: text = %q", tt.name, password, text)
+			}
+
+			doc, err := openRawDoc(data, password)
+			if err != nil {
+				t.Fatalf("%s: openRawDoc: %v", tt.name, err)
+			}
+			if title := string(pdfBytes(doc.dict(doc.trailer["Info"])["Title"])); title != "March 2024" {
+				t.Errorf("%s: password %q: title = %q", tt.name, password, title)
+			}
+			raw, err := extractRawText(doc)
+			if err != nil || len(raw) != 1 || !strings.HasPrefix(raw[0], "Statement for Miss J Smith") {
+				t.Errorf("%s: password %q: raw text = %q, %v", tt.name, password, raw, err)
+			}
+		}
+
+		// The PDF library decrypts the revisions it supports itself, which
+		// checks the encryption above against another implementation
+		doc, _ := openRawDoc(data, owner)
+		password, ok := doc.crypt.libraryPassword()
+		if ok != tt.byLibrary {
+			t.Errorf("%s: library decrypts = %v, want %v", tt.name, ok, tt.byLibrary)
+		}
+		if ok {
+			if password != tt.user {
+				t.Errorf("%s: user password from owner password = %q, want %q", tt.name, password, tt.user)
+			}
+			pw := func() string { p := password; password = ""; return p }
+			pages, err := extractWithLibraryFrom(bytes.NewReader(data), int64(len(data)), pw)
+			if err != nil || !strings.Contains(strings.Join(pages, "\n"), "TESCO STORES") {
+				t.Errorf("%s: library text = %q, %v", tt.name, pages, err)
+			}
+		}
+	}
+}
+
+func TestExtractTextEmptyUserPassword(t *testing.T) {
+	// Statements only restricted against editing open without a password,
+	// and with any password given
+	h := rc4Encryption(2, 3, 128, "", "bank-owner")
+	path := writePDFFile(t, encryptedPDF(h, encryptDict(h)))
+	for _, password := range []string{"", "anything"} {
+		pages, err := ExtractTextWithPassword(path, password)
+		if err != nil || !strings.Contains(strings.Join(pages, "\n"), "TESCO STORES") {
+			t.Errorf("password %q: pages = %q, %v", password, pages, err)
+		}
+	}
+}
+
+func TestUnsupportedEncryption(t *testing.T) {
+	path := writePDFFile(t, encryptedPDF(&securityHandler{}, "<< /Filter /Adobe.PubSec /V 4 /R 4 >>"))
+	_, err := ExtractText(path)
+	if err == nil || errors.Is(err, ErrPasswordRequired) || !strings.Contains(err.Error(), "Adobe.PubSec") {
+		t.Errorf("error = %v, want unsupported security handler", err)
+	}
+}
+
+func TestExtractEncryptedRawParserCrash(t *testing.T) {
+	// A raw parser crash is an error, and the PDF library reads the file
+	// instead where it can decrypt it
+	for _, tt := range []struct {
+		name      string
+		h         *securityHandler
+		byLibrary bool
+	}{
+		{"RC4 128-bit", rc4Encryption(2, 3, 128, "01011990", "bank-owner"), true},
+		{"RC4 40-bit revision 3", rc4Encryption(2, 3, 40, "01011990", "bank-owner"), false},
+	} {
+		doc, err := openRawDoc(encryptedPDF(tt.h, encryptDict(tt.h)), "01011990")
+		if err != nil {
+			t.Fatalf("%s: openRawDoc: %v", tt.name, err)
+		}
+		doc.objects = nil // the next object read panics
+
+		if _, err := extractRawText(doc); !errors.Is(err, errRawParserCrashed) {
+			t.Errorf("%s: extractRawText error = %v, want errRawParserCrashed", tt.name, err)
+		}
+		pages, err := extractEncrypted(doc, "01011990")
+		if tt.byLibrary {
+			if err != nil || !strings.Contains(strings.Join(pages, "\n"), "TESCO STORES") {
+				t.Errorf("%s: pages = %q, %v", tt.name, pages, err)
+			}
+		} else if err == nil && !isReadableText(pages) {
+			t.Errorf("%s: unreadable pages returned without an error: %q", tt.name, pages)
+		}
+	}
+}
@@ -0,0 +1,601 @@
+package extractor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// rawDoc is a PDF read through its cross-reference table or streams:
+// objects are parsed from their offsets, or from the object streams holding
+// them, as they are needed. When the cross-reference data is missing or an
+// offset is wrong, the file is scanned for every "n g obj" instead, later
+// definitions of an object replacing earlier ones as incremental updates
+// do.
+type rawDoc struct {
+	data     []byte
+	xref     map[int]xrefEntry
+	xrefDone bool
+	objects  map[int]interface{}
+	loading  map[int]bool
+	objStms  map[int]map[int]interface{} // parsed object streams
+	trailer  pdfDict
+	order    []int // object numbers in file order, once scanned
+	scanned  bool
+	fonts    map[pdfRef]*fontDecoder
+	crypt    *securityHandler // nil unless the file is encrypted
+}
+
+// xrefEntry locates an object: at a byte offset, or compressed in an object
+// stream.
+type xrefEntry struct {
+	offset int
+	stream int // the object stream's number, or 0
+	free   bool
+}
+
+func newRawDoc(data []byte) *rawDoc {
+	d := &rawDoc{
+		data:    data,
+		xref:    make(map[int]xrefEntry),
+		objects: make(map[int]interface{}),
+		loading: make(map[int]bool),
+		objStms: make(map[int]map[int]interface{}),
+		fonts:   make(map[pdfRef]*fontDecoder),
+	}
+	ok := d.readXref()
+	d.xrefDone = true
+	if !ok {
+		d.scan()
+	}
+	return d
+}
+
+// errRawParserCrashed wraps a panic in the raw parser on a malformed file.
+var errRawParserCrashed = errors.New("raw PDF parser crashed")
+
+// openRawDoc reads data as a document, decrypting it with password, the
+// user or the owner password, if it is encrypted. Decrypted objects are
+// only ever held in memory.
+func openRawDoc(data []byte, password string) (doc *rawDoc, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			doc, err = nil, fmt.Errorf("%w: %v", errRawParserCrashed, r)
+		}
+	}()
+
+	d := newRawDoc(data)
+	if d.trailer["Encrypt"] == nil {
+		return d, nil
+	}
+	h, err := newSecurityHandler(d, password)
+	if err != nil {
+		return nil, err
+	}
+	d.crypt = h
+
+	// Objects read before the key was known are read again, decrypted
+	d.objects = make(map[int]interface{})
+	d.objStms = make(map[int]map[int]interface{})
+	d.fonts = make(map[pdfRef]*fontDecoder)
+	d.order, d.scanned = nil, false
+	return d, nil
+}
+
+// readXref reads the cross-reference sections, from the one startxref
+// points to back through each trailer's /Prev; entries in newer sections
+// win. A section is a classic xref table or, from PDF 1.5, a
+// cross-reference stream; hybrid files list compressed objects in the
+// stream a table's /XRefStm points to. It reports whether a section and
+// trailer were read.
+func (d *rawDoc) readXref() bool {
+	i := bytes.LastIndex(d.data, []byte("startxref"))
+	if i < 0 {
+		return false
+	}
+	l := &pdfLexer{data: d.data, pos: i + len("startxref")}
+	v, _ := l.next()
+	off, ok := v.(int)
+	seen := make(map[int]bool)
+	for ok && off >= 0 && off < len(d.data) && !seen[off] {
+		seen[off] = true
+		entries, trailer, read := d.readXrefSection(off)
+		if !read {
+			break
+		}
+		if stm, ok := trailer["XRefStm"].(int); ok {
+			if more, _, read := d.readXrefSection(stm); read {
+				d.addXref(more)
+			}
+		}
+		d.addXref(entries)
+		if d.trailer == nil {
+			d.trailer = trailer
+		}
+		off, ok = trailer["Prev"].(int)
+	}
+	return d.trailer != nil
+}
+
+// addXref adds entries for objects no newer section has listed.
+func (d *rawDoc) addXref(entries map[int]xrefEntry) {
+	for num, e := range entries {
+		if _, seen := d.xref[num]; !seen {
+			d.xref[num] = e
+		}
+	}
+}
+
+// readXrefSection reads the xref table or stream at off, and its trailer
+// (a stream's dictionary is its trailer).
+func (d *rawDoc) readXrefSection(off int) (map[int]xrefEntry, pdfDict, bool) {
+	l := &pdfLexer{data: d.data, pos: off}
+	v, _ := l.next()
+	if num, ok := v.(int); ok {
+		obj, ok := d.parseAt(off, num)
+		s, isStream := obj.(*pdfStream)
+		if !ok || !isStream || s.dict["Type"] != pdfName("XRef") {
+			return nil, nil, false
+		}
+		entries, ok := d.readXrefStream(s)
+		return entries, s.dict, ok
+	}
+	if v != pdfKeyword("xref") {
+		return nil, nil, false
+	}
+
+	entries := make(map[int]xrefEntry)
+	for {
+		v, err := l.next()
+		if err != nil {
+			return nil, nil, false
+		}
+		if v == pdfKeyword("trailer") {
+			l.refs = true
+			v, _ = l.next()
+			trailer, ok := v.(pdfDict)
+			return entries, trailer, ok
+		}
+
+		// A subsection: the first object number and the count, then an
+		// "offset generation n|f" entry per object
+		start, ok := v.(int)
+		c, _ := l.next()
+		count, ok2 := c.(int)
+		if !ok || !ok2 {
+			return nil, nil, false
+		}
+		for i := 0; i < count; i++ {
+			o, _ := l.next()
+			l.next() // generation
+			kind, _ := l.next()
+			offset, ok := o.(int)
+			if !ok {
+				return nil, nil, false
+			}
+			entries[start+i] = xrefEntry{offset: offset, free: kind != pdfKeyword("n")}
+		}
+	}
+}
+
+// readXrefStream reads a cross-reference stream's entries: rows of /W
+// byte-wide fields (type, then offset and generation, or object stream and
+// index), for the object numbers in /Index.
+func (d *rawDoc) readXrefStream(s *pdfStream) (map[int]xrefEntry, bool) {
+	w := d.array(s.dict["W"])
+	if len(w) != 3 {
+		return nil, false
+	}
+	var widths [3]int
+	rowLen := 0
+	for i, v := range w {
+		n, ok := v.(int)
+		if !ok || n < 0 || n > 8 {
+			return nil, false
+		}
+		widths[i] = n
+		rowLen += n
+	}
+	index := d.array(s.dict["Index"])
+	if index == nil {
+		size, _ := s.dict["Size"].(int)
+		index = []interface{}{0, size}
+	}
+	data := d.decode(s)
+	if data == nil || rowLen == 0 {
+		return nil, false
+	}
+
+	entries := make(map[int]xrefEntry)
+	row := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, _ := index[i].(int)
+		count, _ := index[i+1].(int)
+		for j := 0; j < count && (row+1)*rowLen <= len(data); j, row = j+1, row+1 {
+			var fields [3]int
+			pos := row * rowLen
+			for f, width := range widths {
+				for k := 0; k < width; k++ {
+					fields[f] = fields[f]<<8 | int(data[pos])
+					pos++
+				}
+			}
+			if widths[0] == 0 {
+				fields[0] = 1 // the type defaults to an uncompressed object
+			}
+			switch fields[0] {
+			case 0:
+				entries[start+j] = xrefEntry{free: true}
+			case 1:
+				entries[start+j] = xrefEntry{offset: fields[1]}
+			case 2:
+				entries[start+j] = xrefEntry{stream: fields[1]}
+			}
+		}
+	}
+	return entries, true
+}
+
+// object returns object num, parsing it on first use.
+func (d *rawDoc) object(num int) interface{} {
+	if v, ok := d.objects[num]; ok {
+		return v
+	}
+	if d.loading[num] {
+		return nil // a stream whose /Length refers to itself
+	}
+	e, listed := d.xref[num]
+	if listed && e.free {
+		return nil
+	}
+	if listed {
+		d.loading[num] = true
+		var v interface{}
+		var ok bool
+		if e.stream > 0 {
+			v, ok = d.objectStream(e.stream)[num]
+		} else {
+			v, ok = d.parseAt(e.offset, num)
+		}
+		delete(d.loading, num)
+		if ok {
+			d.objects[num] = v
+			return v
+		}
+	}
+	if !d.scanned && d.xrefDone {
+		d.scan()
+		return d.objects[num]
+	}
+	return nil
+}
+
+// objectStream returns the objects compressed in object stream num, by
+// number. The stream starts with an "number offset" pair per object, its
+// /N objects following from /First.
+func (d *rawDoc) objectStream(num int) map[int]interface{} {
+	if objs, ok := d.objStms[num]; ok {
+		return objs
+	}
+	objs := make(map[int]interface{})
+	d.objStms[num] = objs
+	s := d.stream(pdfRef{num: num})
+	if s == nil || s.dict["Type"] != pdfName("ObjStm") {
+		return objs
+	}
+	n, _ := s.dict["N"].(int)
+	first, _ := s.dict["First"].(int)
+	data := d.decode(s)
+	header := &pdfLexer{data: data}
+	for i := 0; i < n; i++ {
+		v1, _ := header.next()
+		v2, _ := header.next()
+		objNum, ok1 := v1.(int)
+		off, ok2 := v2.(int)
+		if !ok1 || !ok2 || first < 0 || off < 0 || first+off < 0 || first+off >= len(data) {
+			break
+		}
+		l := &pdfLexer{data: data, pos: first + off, refs: true}
+		if v, err := l.next(); err == nil {
+			objs[objNum] = v
+		}
+	}
+	return objs
+}
+
+// parseAt parses object num at off, checking its "num gen obj" header.
+func (d *rawDoc) parseAt(off, num int) (interface{}, bool) {
+	if off < 0 || off >= len(d.data) {
+		return nil, false
+	}
+	l := &pdfLexer{data: d.data, pos: off}
+	n, _ := l.next()
+	g, _ := l.next()
+	if kw, _ := l.next(); n != interface{}(num) || kw != pdfKeyword("obj") {
+		return nil, false
+	}
+	l.refs = true
+	v, err := l.next()
+	if err != nil {
+		return nil, false
+	}
+	gen, _ := g.(int)
+	return d.decrypted(d.withStream(l, v), num, gen), true
+}
+
+// decrypted returns object num, generation gen, decrypted if the file is
+// encrypted.
+func (d *rawDoc) decrypted(v interface{}, num, gen int) interface{} {
+	if d.crypt == nil || num == d.crypt.encryptNum {
+		return v
+	}
+	return d.crypt.decryptObject(v, num, gen)
+}
+
+// withStream returns v, or the stream it begins if stream data follows.
+func (d *rawDoc) withStream(l *pdfLexer, v interface{}) interface{} {
+	dict, ok := v.(pdfDict)
+	if !ok {
+		return v
+	}
+	length := -1
+	switch n := dict["Length"].(type) {
+	case int:
+		length = n
+	case pdfRef:
+		if v, ok := d.object(n.num).(int); ok {
+			length = v
+		}
+	}
+	if raw := l.streamData(length); raw != nil {
+		return &pdfStream{dict: dict, data: raw}
+	}
+	return v
+}
+
+var objHeaderRe = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// scan parses every object in the file, and those in its object streams,
+// for files without usable cross-reference data. Objects already read
+// through it are kept.
+func (d *rawDoc) scan() {
+	d.scanned = true
+	found := make(map[int]interface{})
+	pos := 0
+	for pos < len(d.data) {
+		loc := objHeaderRe.FindSubmatchIndex(d.data[pos:])
+		if loc == nil {
+			break
+		}
+		num, _ := strconv.Atoi(string(d.data[pos+loc[2] : pos+loc[3]]))
+		gen, _ := strconv.Atoi(string(d.data[pos+loc[4] : pos+loc[5]]))
+		l := &pdfLexer{data: d.data, pos: pos + loc[1], refs: true}
+		pos += loc[1]
+		v, err := l.next()
+		if err != nil {
+			continue
+		}
+		if _, seen := found[num]; !seen {
+			d.order = append(d.order, num)
+		}
+		found[num] = d.decrypted(d.withStream(l, v), num, gen)
+		pos = l.pos
+	}
+	for num, v := range found {
+		if _, ok := d.objects[num]; !ok {
+			d.objects[num] = v
+		}
+	}
+	// Objects compressed in object streams, unless defined outright
+	for _, num := range append([]int(nil), d.order...) {
+		if s, ok := found[num].(*pdfStream); ok && s.dict["Type"] == pdfName("ObjStm") {
+			for objNum, v := range d.objectStream(num) {
+				if _, ok := d.objects[objNum]; !ok {
+					d.objects[objNum] = v
+					d.order = append(d.order, objNum)
+				}
+			}
+		}
+	}
+
+	if d.trailer != nil {
+		return
+	}
+	if i := bytes.LastIndex(d.data, []byte("trailer")); i >= 0 {
+		l := &pdfLexer{data: d.data, pos: i + len("trailer"), refs: true}
+		if v, _ := l.next(); v != nil {
+			d.trailer, _ = v.(pdfDict)
+		}
+	}
+	if d.trailer == nil || d.trailer["Root"] == nil {
+		for _, num := range d.order {
+			if dict, ok := d.objects[num].(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				d.trailer = pdfDict{"Root": pdfRef{num: num}}
+				break
+			}
+		}
+	}
+}
+
+// resolve follows references to the object they refer to.
+func (d *rawDoc) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = d.object(ref.num)
+	}
+	return nil
+}
+
+// dict returns v, resolved, as a dictionary; a stream's dictionary counts.
+func (d *rawDoc) dict(v interface{}) pdfDict {
+	switch v := d.resolve(v).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+func (d *rawDoc) array(v interface{}) []interface{} {
+	a, _ := d.resolve(v).([]interface{})
+	return a
+}
+
+func (d *rawDoc) stream(v interface{}) *pdfStream {
+	s, _ := d.resolve(v).(*pdfStream)
+	return s
+}
+
+// decode returns the stream's data with its filters undone, or nil if they
+// cannot be.
+func (d *rawDoc) decode(s *pdfStream) []byte {
+	data, err := d.decodeFilters(s.data, s.dict["Filter"], s.dict["DecodeParms"])
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// rawPage is a page and the resources it has or inherits.
+type rawPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages returns the pages in order, walking the page tree from the
+// catalog. Files whose tree cannot be walked fall back to their page
+// objects in file order.
+func (d *rawDoc) pages() []rawPage {
+	var pages []rawPage
+	visited := make(map[pdfRef]bool)
+	var walk func(v interface{}, resources pdfDict, depth int)
+	walk = func(v interface{}, resources pdfDict, depth int) {
+		if ref, ok := v.(pdfRef); ok {
+			if visited[ref] {
+				return
+			}
+			visited[ref] = true
+		}
+		node := d.dict(v)
+		if node == nil || depth > 64 {
+			return
+		}
+		if res := d.dict(node["Resources"]); res != nil {
+			resources = res
+		}
+		if node["Type"] == pdfName("Page") || node["Kids"] == nil {
+			pages = append(pages, rawPage{dict: node, resources: resources})
+			return
+		}
+		for _, kid := range d.array(node["Kids"]) {
+			walk(kid, resources, depth+1)
+		}
+	}
+	if root := d.dict(d.trailer["Root"]); root != nil && root["Pages"] != nil {
+		walk(root["Pages"], nil, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	if !d.scanned {
+		d.scan()
+	}
+	for _, num := range d.order {
+		page, ok := d.objects[num].(pdfDict)
+		if ok && page["Type"] == pdfName("Page") {
+			pages = append(pages, rawPage{dict: page, resources: d.dict(d.inherited(page, "Resources"))})
+		}
+	}
+	return pages
+}
+
+// inherited looks key up on the page, then on its ancestors in the page
+// tree.
+func (d *rawDoc) inherited(page pdfDict, key pdfName) interface{} {
+	for i := 0; page != nil && i < 32; i++ {
+		if v, ok := page[key]; ok {
+			return v
+		}
+		page = d.dict(page["Parent"])
+	}
+	return nil
+}
+
+// contentStream is decoded content and the resources its operators name.
+// Streams read without the object structure have no doc or resources.
+type contentStream struct {
+	doc       *rawDoc
+	data      []byte
+	resources pdfDict
+	fonts     map[pdfName]*fontDecoder
+}
+
+// pageContent returns the page's content: its /Contents streams decoded
+// and joined in order, as one stream, since a page may split its content
+// anywhere between operators.
+func (d *rawDoc) pageContent(p rawPage) contentStream {
+	contents := p.dict["Contents"]
+	items, isArray := d.resolve(contents).([]interface{})
+	if !isArray {
+		items = []interface{}{contents}
+	}
+	var data []byte
+	for _, item := range items {
+		if s := d.stream(item); s != nil {
+			data = append(data, d.decode(s)...)
+			data = append(data, '\n')
+		}
+	}
+	return d.newContentStream(data, p.resources)
+}
+
+// form returns the content of the form XObject the resources name, or
+// false if it is not a form. Forms without resources of their own use the
+// resources of the content that draws them.
+func (s contentStream) form(name pdfName) (contentStream, bool) {
+	if s.doc == nil {
+		return contentStream{}, false
+	}
+	xobj := s.doc.stream(s.doc.dict(s.resources["XObject"])[name])
+	if xobj == nil || xobj.dict["Subtype"] != pdfName("Form") {
+		return contentStream{}, false
+	}
+	resources := s.doc.dict(xobj.dict["Resources"])
+	if resources == nil {
+		resources = s.resources
+	}
+	return s.doc.newContentStream(s.doc.decode(xobj), resources), true
+}
+
+func (d *rawDoc) newContentStream(data []byte, resources pdfDict) contentStream {
+	return contentStream{doc: d, data: data, resources: resources, fonts: d.fontsOf(resources)}
+}
+
+// fontsOf returns decoders for the fonts in a resource dictionary.
+func (d *rawDoc) fontsOf(resources pdfDict) map[pdfName]*fontDecoder {
+	fonts := make(map[pdfName]*fontDecoder)
+	for name, v := range d.dict(resources["Font"]) {
+		ref, isRef := v.(pdfRef)
+		if f, ok := d.fonts[ref]; isRef && ok {
+			fonts[name] = f
+			continue
+		}
+		font := d.dict(v)
+		if font == nil {
+			continue
+		}
+		f := newFontDecoder(d, font)
+		if isRef {
+			d.fonts[ref] = f
+		}
+		fonts[name] = f
+	}
+	return fonts
+}
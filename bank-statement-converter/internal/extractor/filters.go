@@ -0,0 +1,283 @@
+package extractor
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"io"
+)
+
+// decodeFilters undoes a stream's /Filter chain, in order, with each
+// filter's /DecodeParms. filter is a name or an array of names, parms a
+// dictionary or an array of them (with nulls), as in the stream dictionary.
+// Image filters (DCT, JPX, JBIG2, CCITT) are an error: no text is behind
+// them.
+func (d *rawDoc) decodeFilters(data []byte, filter, parms interface{}) ([]byte, error) {
+	filters, isArray := d.resolve(filter).([]interface{})
+	if !isArray {
+		filters = []interface{}{filter}
+	}
+	parmList, isArray := d.resolve(parms).([]interface{})
+	if !isArray {
+		parmList = []interface{}{parms}
+	}
+
+	for i, f := range filters {
+		name, _ := d.resolve(f).(pdfName)
+		if name == "" {
+			continue
+		}
+		var p pdfDict
+		if i < len(parmList) {
+			p = d.dict(parmList[i])
+		}
+		var err error
+		switch name {
+		case "FlateDecode", "Fl":
+			if data, err = flateDecode(data); err == nil {
+				data, err = unpredict(data, p)
+			}
+		case "LZWDecode", "LZW":
+			if data, err = lzwDecode(data, intParm(p, "EarlyChange", 1)); err == nil {
+				data, err = unpredict(data, p)
+			}
+		case "ASCIIHexDecode", "AHx":
+			data = asciiHexDecode(data)
+		case "ASCII85Decode", "A85":
+			data, err = ascii85Decode(data)
+		case "RunLengthDecode", "RL":
+			data = runLengthDecode(data)
+		case "Crypt":
+			// The Identity crypt filter; others are applied with the
+			// document's encryption
+		default:
+			err = fmt.Errorf("unsupported filter %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// intParm returns an integer decode parameter, or def.
+func intParm(p pdfDict, key pdfName, def int) int {
+	if v, ok := p[key].(int); ok {
+		return v
+	}
+	return def
+}
+
+// flateDecode inflates zlib data. A truncated or damaged stream still gives
+// what inflated before the damage, as readers show it.
+func flateDecode(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("flate: %w", err)
+	}
+	defer r.Close()
+	out, err := io.ReadAll(r)
+	if err != nil && len(out) == 0 {
+		return nil, fmt.Errorf("flate: %w", err)
+	}
+	return out, nil
+}
+
+// unpredict reverses a Flate or LZW /Predictor: 2 is TIFF's horizontal
+// differencing (8-bit components), 10 and up PNG's, where each row starts
+// with its own filter type.
+func unpredict(data []byte, p pdfDict) ([]byte, error) {
+	predictor := intParm(p, "Predictor", 1)
+	if predictor <= 1 {
+		return data, nil
+	}
+	colors := intParm(p, "Colors", 1)
+	bpc := intParm(p, "BitsPerComponent", 8)
+	columns := intParm(p, "Columns", 1)
+	if colors < 1 || bpc < 1 || columns < 1 {
+		return nil, fmt.Errorf("invalid predictor parameters")
+	}
+	bpp := (colors*bpc + 7) / 8 // bytes per pixel, at least one
+	rowLen := (colors*bpc*columns + 7) / 8
+
+	if predictor == 2 {
+		if bpc != 8 {
+			return nil, fmt.Errorf("TIFF predictor with %d-bit components", bpc)
+		}
+		out := append([]byte(nil), data...)
+		for row := 0; row < len(out); row += rowLen {
+			for i := row + bpp; i < row+rowLen && i < len(out); i++ {
+				out[i] += out[i-bpp]
+			}
+		}
+		return out, nil
+	}
+
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowLen)
+	for pos := 0; pos < len(data); pos += rowLen + 1 {
+		if pos+1 >= len(data) {
+			break
+		}
+		kind := data[pos]
+		end := pos + 1 + rowLen
+		if end > len(data) {
+			end = len(data)
+		}
+		cur := make([]byte, rowLen)
+		copy(cur, data[pos+1:end])
+		for i := 0; i < rowLen; i++ {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = cur[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch kind {
+			case 0:
+			case 1:
+				cur[i] += left
+			case 2:
+				cur[i] += up
+			case 3:
+				cur[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				cur[i] += paeth(left, up, upLeft)
+			default:
+				return nil, fmt.Errorf("invalid PNG predictor %d", kind)
+			}
+		}
+		out = append(out, cur[:end-pos-1]...)
+		prev = cur
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// lzwDecode decodes PDF's LZW: variable-width codes of 9 to 12 bits, most
+// significant bit first, with 256 clearing the table and 257 ending the
+// data. With earlyChange 1 (the default) the code width grows one code
+// early.
+func lzwDecode(data []byte, earlyChange int) ([]byte, error) {
+	var out []byte
+	bitPos := 0
+	read := func(width int) (int, bool) {
+		if bitPos+width > len(data)*8 {
+			return 0, false
+		}
+		v := 0
+		for i := 0; i < width; i++ {
+			bit := data[bitPos/8] >> (7 - bitPos%8) & 1
+			v = v<<1 | int(bit)
+			bitPos++
+		}
+		return v, true
+	}
+
+	var table [][]byte
+	reset := func() {
+		table = table[:0]
+		for i := 0; i < 256; i++ {
+			table = append(table, []byte{byte(i)})
+		}
+		table = append(table, nil, nil) // clear and end of data
+	}
+	reset()
+	width := 9
+	var prev []byte
+	for {
+		code, ok := read(width)
+		if !ok || code == 257 {
+			return out, nil
+		}
+		if code == 256 {
+			reset()
+			width, prev = 9, nil
+			continue
+		}
+
+		var entry []byte
+		switch {
+		case code < len(table):
+			entry = table[code]
+		case code == len(table) && prev != nil:
+			// The code being defined: the previous entry and its first byte
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return nil, fmt.Errorf("invalid LZW code %d", code)
+		}
+		out = append(out, entry...)
+		if prev != nil && len(table) < 4096 {
+			table = append(table, append(append([]byte(nil), prev...), entry[0]))
+		}
+		prev = entry
+		if len(table)+earlyChange >= 1<<width && width < 12 {
+			width++
+		}
+	}
+}
+
+// asciiHexDecode decodes hex digits up to '>', ignoring anything else; a
+// missing last digit is taken as 0.
+func asciiHexDecode(data []byte) []byte {
+	l := &pdfLexer{data: append(append([]byte("<"), data...), '>')}
+	return l.hex()
+}
+
+// ascii85Decode decodes base-85 data up to its ~> end marker.
+func ascii85Decode(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(bytes.TrimLeft(data, " \t\r\n\f"), []byte("<~"))
+	if i := bytes.Index(data, []byte("~>")); i >= 0 {
+		data = data[:i]
+	}
+	out := make([]byte, 4*len(data)+4) // 'z' stands for four bytes
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, fmt.Errorf("ascii85: %w", err)
+	}
+	return out[:n], nil
+}
+
+// runLengthDecode decodes runs: a length byte n below 128 copies the next
+// n+1 bytes, one above repeats the next byte 257-n times, and 128 ends the
+// data.
+func runLengthDecode(data []byte) []byte {
+	var out []byte
+	for i := 0; i < len(data); {
+		n := int(data[i])
+		i++
+		switch {
+		case n == 128:
+			return out
+		case n < 128:
+			end := i + n + 1
+			if end > len(data) {
+				end = len(data)
+			}
+			out = append(out, data[i:end]...)
+			i = end
+		case i < len(data):
+			out = append(out, bytes.Repeat(data[i:i+1], 257-n)...)
+			i++
+		}
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+package extractor
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/ascii85"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFilters(t *testing.T) {
+	d := newRawDoc(nil)
+	tests := []struct {
+		name   string
+		data   string
+		filter interface{}
+		parms  interface{}
+		want   string
+	}{
+		{"ASCIIHex", "48 65 6c6C 6f 2>", pdfName("ASCIIHexDecode"), nil, "Hello "},
+		{"ASCII85", "<~87cURD_*#PFCB9&D.RU,+T~>", pdfName("A85"), nil, "Hello, statement!"},
+		{"ASCII85 zeros", "z@:B~>", pdfName("ASCII85Decode"), nil, "\x00\x00\x00\x00ab"},
+		{"RunLength", "\x02abc\xfdx\x00!\x80ignored", pdfName("RunLengthDecode"), nil, "abcxxxx!"},
+		// The example from the PDF specification
+		{"LZW", "\x80\x0b\x60\x50\x22\x0c\x0c\x85\x01", pdfName("LZWDecode"), nil, "-----A---B"},
+		{"chain", "<~" + a85(flate("Paid out")) + "~>", []interface{}{pdfName("ASCII85Decode"), pdfName("FlateDecode")}, nil, "Paid out"},
+		{"Identity crypt", "plain", pdfName("Crypt"), nil, "plain"},
+		// PNG Up rows: each row adds to the one above
+		{"PNG predictor", flate("\x02\x01\x02\x03\x02\x01\x01\x01\x00\x05\x05\x05"), pdfName("FlateDecode"),
+			pdfDict{"Predictor": 12, "Columns": 3}, "\x01\x02\x03\x02\x03\x04\x05\x05\x05"},
+		{"PNG Sub and Paeth", flate("\x01\x01\x01\x01\x04\x01\x01\x01"), pdfName("FlateDecode"),
+			[]interface{}{pdfDict{"Predictor": 15, "Columns": 3}}, "\x01\x02\x03\x02\x03\x04"},
+		{"TIFF predictor", flate("\x0a\x01\x01\x05\x00\x01"), pdfName("FlateDecode"),
+			pdfDict{"Predictor": 2, "Columns": 3}, "\x0a\x0b\x0c\x05\x05\x06"},
+	}
+	for _, tt := range tests {
+		got, err := d.decodeFilters([]byte(tt.data), tt.filter, tt.parms)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := d.decodeFilters([]byte("\xff\xd8"), pdfName("DCTDecode"), nil); err == nil {
+		t.Error("expected an error for an image filter")
+	}
+}
+
+func flate(s string) string {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write([]byte(s))
+	zw.Close()
+	return buf.String()
+}
+
+func a85(s string) string {
+	out := make([]byte, ascii85.MaxEncodedLen(len(s)))
+	return string(out[:ascii85.Encode(out, []byte(s))])
+}
+
+func TestExtractTextRawCompressedObjects(t *testing.T) {
+	// A PDF 1.5 file: the catalog, pages and font are compressed in an
+	// object stream, located through a cross-reference stream whose rows
+	// use the PNG Up predictor, and the content is ASCII85 over Flate
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	offsets := map[int]int{}
+	object := func(num int, body string) {
+		offsets[num] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", num, body)
+	}
+
+	content := "<~" + a85(flate("BT /F1 10 Tf 50 700 Td (Money in) Tj 0 -14 Td (1,250.00) Tj ET")) + "~>"
+	object(4, streamObject("/Filter [/A85 /Fl] ", content))
+
+	compressed := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		helvetica,
+	}
+	var header, body strings.Builder
+	for i, num := range []int{1, 2, 3, 5} {
+		fmt.Fprintf(&header, "%d %d ", num, body.Len())
+		body.WriteString(compressed[i] + "\n")
+	}
+	objStm := flate(header.String() + body.String())
+	object(6, streamObject(fmt.Sprintf("/Type /ObjStm /N 4 /First %d /Filter /FlateDecode ", header.Len()), objStm))
+
+	// Rows of type, two-byte offset or stream number, and index
+	xrefOffset := buf.Len()
+	rows := [][4]byte{{0, 0, 0, 0}, {2, 0, 6, 0}, {2, 0, 6, 1}, {2, 0, 6, 2}}
+	rows = append(rows, row(1, offsets[4]), [4]byte{2, 0, 6, 3}, row(1, offsets[6]), row(1, xrefOffset))
+	var predicted []byte
+	var prev [4]byte
+	for _, r := range rows {
+		predicted = append(predicted, 2)
+		for i := range r {
+			predicted = append(predicted, r[i]-prev[i])
+		}
+		prev = r
+	}
+	object(7, streamObject("/Type /XRef /Size 8 /W [1 2 1] /Root 1 0 R /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 4 >> ",
+		flate(string(predicted))))
+	fmt.Fprintf(&buf, "startxref\n%d\n%%%%EOF\n", xrefOffset)
+
+	data := buf.Bytes()
+	pages, err := ExtractTextRaw(writePDFFile(t, data))
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	if got := strings.Join(pages, "|"); got != "Money in\n1,250.00" {
+		t.Errorf("text = %q", got)
+	}
+
+	// Without the cross-reference stream the objects are still found, in
+	// the object stream, by scanning
+	noXref := data[:xrefOffset]
+	pages, err = ExtractTextRaw(writePDFFile(t, noXref))
+	if err != nil {
+		t.Fatalf("ExtractTextRaw without xref: %v", err)
+	}
+	if got := strings.Join(pages, "|"); got != "Money in\n1,250.00" {
+		t.Errorf("text without xref = %q", got)
+	}
+}
+
+func TestExtractTextRawCorruptObjectStream(t *testing.T) {
+	// A negative /First or object offset is not read before the start of
+	// the stream; the font it holds is missing, not a crash
+	for header, offsets := range map[string]string{"/N 1 /First -40": "6 0 ", "/N 1 /First 5": "6 -9 "} {
+		data := buildPDF(
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+			"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 6 0 R >> >> /Contents 4 0 R >>",
+			streamObject("", "BT /F1 10 Tf 50 700 Td (Money out) Tj ET"),
+			streamObject("/Type /ObjStm "+header+" ", offsets+helvetica),
+		)
+		noXref := data[:bytes.Index(data, []byte("xref"))]
+		if _, err := ExtractTextRaw(writePDFFile(t, noXref)); err != nil {
+			t.Errorf("%s: ExtractTextRaw: %v", header, err)
+		}
+	}
+}
+
+func row(kind byte, offset int) [4]byte {
+	return [4]byte{kind, byte(offset >> 8), byte(offset), 0}
+}
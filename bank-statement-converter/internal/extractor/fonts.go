@@ -0,0 +1,199 @@
+package extractor
+
+import (
+	"strconv"
+	"strings"
+)
+
+// fontDecoder turns the codes a font's strings are written in into text:
+// through the font's ToUnicode CMap where it has one, else through its
+// encoding. Composite (Type0) fonts use two-byte codes; without a CMap
+// they are read as UTF-16, which is what Identity-encoded fonts from most
+// statement generators amount to.
+type fontDecoder struct {
+	toUnicode *CMap
+	composite bool
+	codes     [256]rune // simple fonts: the character for each code, 0 if none
+}
+
+func newFontDecoder(d *rawDoc, font pdfDict) *fontDecoder {
+	f := &fontDecoder{composite: font["Subtype"] == pdfName("Type0")}
+	if s := d.stream(font["ToUnicode"]); s != nil {
+		if cm := ParseCMap(string(d.decode(s))); len(cm.charMap) > 0 {
+			f.toUnicode = cm
+		}
+	}
+	if !f.composite {
+		f.codes = simpleEncoding(d, font["Encoding"])
+	}
+	return f
+}
+
+// decode returns the text for a shown string. In simple fonts a code the
+// CMap lacks falls back to the encoding.
+func (f *fontDecoder) decode(raw []byte) string {
+	if f.composite {
+		if f.toUnicode != nil {
+			if s := f.toUnicode.Decode(raw); s != "" {
+				return s
+			}
+		}
+		return utf16Text(raw)
+	}
+	var b strings.Builder
+	for _, c := range raw {
+		if f.toUnicode != nil {
+			if s, ok := f.toUnicode.charMap[byteCodes[c]]; ok {
+				b.WriteString(s)
+				continue
+			}
+		}
+		if r := f.codes[c]; r != 0 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// byteCodes are the CMap keys of the one-byte codes.
+var byteCodes [256]string
+
+// simpleEncoding returns the code-to-character table for a simple font's
+// /Encoding: a base encoding name, or a dictionary with a base encoding and
+// a /Differences array. Fonts with no encoding, or StandardEncoding, are
+// read as WinAnsi, which agrees with it on letters, digits and the
+// punctuation statements use.
+func simpleEncoding(d *rawDoc, enc interface{}) [256]rune {
+	base := enc
+	var diffs []interface{}
+	if dict, ok := d.resolve(enc).(pdfDict); ok {
+		base = dict["BaseEncoding"]
+		diffs = d.array(dict["Differences"])
+	}
+	codes := winAnsiEncoding
+	if d.resolve(base) == pdfName("MacRomanEncoding") {
+		codes = macRomanEncoding
+	}
+
+	// [code /name /name ... code /name ...]: names replace the codes
+	// counting up from the number before them
+	code := -1
+	for _, v := range diffs {
+		switch v := v.(type) {
+		case int:
+			code = v
+		case pdfName:
+			if code >= 0 && code < 256 {
+				codes[code] = glyphRune(string(v))
+				code++
+			}
+		}
+	}
+	return codes
+}
+
+// glyphRune returns the character a glyph name stands for: a name from the
+// glyph list, or uniXXXX or uXXXX[XX]; 0 for names such as g12 that say
+// nothing about the character.
+func glyphRune(name string) rune {
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		name = name[:i] // a.sc, one.oldstyle
+	}
+	if len(name) == 1 && (name[0] >= 'A' && name[0] <= 'Z' || name[0] >= 'a' && name[0] <= 'z') {
+		return rune(name[0])
+	}
+	if r, ok := glyphNames[name]; ok {
+		return r
+	}
+	for _, prefix := range []string{"uni", "u"} {
+		hex := strings.TrimPrefix(name, prefix)
+		if hex == name || len(hex) < 4 || len(hex) > 6 {
+			continue
+		}
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil {
+			return rune(v)
+		}
+	}
+	return 0
+}
+
+// glyphNames are the glyph list names of the characters in the standard
+// Latin encodings, less the single letters.
+var glyphNames = map[string]rune{
+	"space": ' ', "exclam": '!', "quotedbl": '"', "numbersign": '#', "dollar": '$',
+	"percent": '%', "ampersand": '&', "quotesingle": '\'', "parenleft": '(', "parenright": ')',
+	"asterisk": '*', "plus": '+', "comma": ',', "hyphen": '-', "period": '.', "slash": '/',
+	"zero": '0', "one": '1', "two": '2', "three": '3', "four": '4',
+	"five": '5', "six": '6', "seven": '7', "eight": '8', "nine": '9',
+	"colon": ':', "semicolon": ';', "less": '<', "equal": '=', "greater": '>', "question": '?',
+	"at": '@', "bracketleft": '[', "backslash": '\\', "bracketright": ']', "asciicircum": '^',
+	"underscore": '_', "grave": '`', "braceleft": '{', "bar": '|', "braceright": '}', "asciitilde": '~',
+	"Euro": '€', "quotesinglbase": '‚', "florin": 'ƒ', "quotedblbase": '„', "ellipsis": '…',
+	"dagger": '†', "daggerdbl": '‡', "circumflex": 'ˆ', "perthousand": '‰', "Scaron": 'Š',
+	"guilsinglleft": '‹', "OE": 'Œ', "Zcaron": 'Ž', "quoteleft": '‘', "quoteright": '’',
+	"quotedblleft": '“', "quotedblright": '”', "bullet": '•', "endash": '–', "emdash": '—',
+	"tilde": '˜', "trademark": '™', "scaron": 'š', "guilsinglright": '›', "oe": 'œ',
+	"zcaron": 'ž', "Ydieresis": 'Ÿ', "nbspace": ' ', "exclamdown": '¡', "cent": '¢',
+	"sterling": '£', "currency": '¤', "yen": '¥', "brokenbar": '¦', "section": '§',
+	"dieresis": '¨', "copyright": '©', "ordfeminine": 'ª', "guillemotleft": '«', "logicalnot": '¬',
+	"sfthyphen": '-', "registered": '®', "macron": '¯', "degree": '°', "plusminus": '±',
+	"twosuperior": '²', "threesuperior": '³', "acute": '´', "mu": 'µ', "paragraph": '¶',
+	"periodcentered": '·', "cedilla": '¸', "onesuperior": '¹', "ordmasculine": 'º',
+	"guillemotright": '»', "onequarter": '¼', "onehalf": '½', "threequarters": '¾',
+	"questiondown": '¿', "multiply": '×', "divide": '÷', "germandbls": 'ß', "dotlessi": 'ı',
+	"fi": 'ﬁ', "fl": 'ﬂ', "minus": '−', "fraction": '⁄', "notequal": '≠', "infinity": '∞',
+	"lessequal": '≤', "greaterequal": '≥', "partialdiff": '∂', "summation": '∑', "product": '∏',
+	"pi": 'π', "integral": '∫', "Omega": 'Ω', "radical": '√', "approxequal": '≈', "Delta": '∆',
+	"lozenge": '◊', "breve": '˘', "dotaccent": '˙', "ring": '˚', "hungarumlaut": '˝',
+	"ogonek": '˛', "caron": 'ˇ',
+}
+
+// latin1Names are the glyph names of 0xC0 to 0xDF in Latin-1; the
+// characters 0x20 above are the small letters, named in lower case, apart
+// from ae, divide and ydieresis.
+var latin1Names = []string{
+	"Agrave", "Aacute", "Acircumflex", "Atilde", "Adieresis", "Aring", "AE", "Ccedilla",
+	"Egrave", "Eacute", "Ecircumflex", "Edieresis", "Igrave", "Iacute", "Icircumflex", "Idieresis",
+	"Eth", "Ntilde", "Ograve", "Oacute", "Ocircumflex", "Otilde", "Odieresis", "multiply",
+	"Oslash", "Ugrave", "Uacute", "Ucircumflex", "Udieresis", "Yacute", "Thorn", "germandbls",
+}
+
+// The standard encodings. WinAnsi is Latin-1 with typographic characters
+// at 0x80 to 0x9F; MacRoman has its own upper half.
+var winAnsiEncoding, macRomanEncoding [256]rune
+
+func init() {
+	for c := range byteCodes {
+		byteCodes[c] = strings.ToUpper(strconv.FormatInt(int64(0x100+c), 16)[1:])
+	}
+	for i, name := range latin1Names {
+		glyphNames[name] = rune(0xC0 + i)
+		switch {
+		case name == "AE":
+			glyphNames["ae"] = 'æ'
+		case name[0] >= 'A' && name[0] <= 'Z':
+			glyphNames[strings.ToLower(name[:1])+name[1:]] = rune(0xE0 + i)
+		}
+	}
+	glyphNames["ydieresis"] = 'ÿ'
+
+	for c := 0x20; c < 0x7F; c++ {
+		winAnsiEncoding[c] = rune(c)
+		macRomanEncoding[c] = rune(c)
+	}
+	for i, r := range []rune("€\x00‚ƒ„…†‡ˆ‰Š‹Œ\x00Ž\x00\x00‘’“”•–—˜™š›œ\x00žŸ") {
+		winAnsiEncoding[0x80+i] = r
+	}
+	for c := 0xA0; c <= 0xFF; c++ {
+		winAnsiEncoding[c] = rune(c)
+	}
+	winAnsiEncoding[0xA0], winAnsiEncoding[0xAD] = ' ', '-'
+
+	mac := []rune("ÄÅÇÉÑÖÜáàâäãåçéèêëíìîïñóòôöõúùûü" +
+		"†°¢£§•¶ß®©™´¨≠ÆØ∞±≤≥¥µ∂∑∏π∫ªºΩæø" +
+		"¿¡¬√ƒ≈∆«»… ÀÃÕŒœ–—“”‘’÷◊ÿŸ⁄¤‹›ﬁﬂ" +
+		"‡·‚„‰ÂÊÁËÈÍÎÏÌÓÔ\x00ÒÚÛÙıˆ˜¯˘˙˚¸˝˛ˇ")
+	for i, r := range mac {
+		macRomanEncoding[0x80+i] = r
+	}
+}
@@ -0,0 +1,359 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// PDF object types, as pdfLexer returns them. Integers are int, reals
+// float64, literal strings []byte, booleans bool and null nil.
+type (
+	pdfName    string
+	pdfKeyword string // an operator, or obj, R, stream and the like
+	pdfHex     []byte // a <hex> string, decoded
+	pdfDict    map[pdfName]interface{}
+	pdfRef     struct{ num, gen int }
+)
+
+// pdfStream is a stream object: its dictionary and its data, still
+// encoded.
+type pdfStream struct {
+	dict pdfDict
+	data []byte
+}
+
+// pdfLexer reads PDF objects, and content stream operands and operators,
+// from data.
+type pdfLexer struct {
+	data []byte
+	pos  int
+	refs bool // read "n g R" as a reference (not in content streams)
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelim(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+// skipSpace skips whitespace and comments.
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next returns the next object or keyword; io.EOF at the end of data.
+// Unbalanced delimiters are skipped, so it always makes progress.
+func (l *pdfLexer) next() (interface{}, error) {
+	l.skipSpace()
+	for l.pos < len(l.data) && bytes.IndexByte([]byte(")]>{}"), l.data[l.pos]) >= 0 {
+		l.pos++
+		l.skipSpace()
+	}
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+	switch c := l.data[l.pos]; {
+	case c == '/':
+		return l.name(), nil
+	case c == '(':
+		return l.literal(), nil
+	case c == '<' && l.peek(1) == '<':
+		return l.dict()
+	case c == '<':
+		return l.hex(), nil
+	case c == '[':
+		return l.array()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return l.number()
+	default:
+		start := l.pos
+		for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+			l.pos++
+		}
+		switch word := string(l.data[start:l.pos]); word {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		default:
+			return pdfKeyword(word), nil
+		}
+	}
+}
+
+func (l *pdfLexer) peek(n int) byte {
+	if l.pos+n < len(l.data) {
+		return l.data[l.pos+n]
+	}
+	return 0
+}
+
+func (l *pdfLexer) name() pdfName {
+	l.pos++ // '/'
+	var b []byte
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		c := l.data[l.pos]
+		if c == '#' && l.pos+2 < len(l.data) {
+			if v, err := strconv.ParseUint(string(l.data[l.pos+1:l.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				l.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		l.pos++
+	}
+	return pdfName(b)
+}
+
+// literal reads a (string), with nested parentheses and escapes.
+func (l *pdfLexer) literal() []byte {
+	l.pos++ // '('
+	var b []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return b
+			}
+		case '\r':
+			// An end of line in a string is a newline, however it is written
+			if l.peek(0) == '\n' {
+				l.pos++
+			}
+			c = '\n'
+		case '\\':
+			if l.pos >= len(l.data) {
+				return b
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A backslash at the end of a line continues the string
+				if e == '\r' && l.peek(0) == '\n' {
+					l.pos++
+				}
+				continue
+			default:
+				if e < '0' || e > '7' {
+					c = e
+					break
+				}
+				v := int(e - '0')
+				for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+					v = v*8 + int(l.data[l.pos]-'0')
+					l.pos++
+				}
+				c = byte(v)
+			}
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// hex reads a <hex> string; a missing last digit is taken as 0.
+func (l *pdfLexer) hex() pdfHex {
+	l.pos++ // '<'
+	var b []byte
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if v, ok := hexDigit(l.data[l.pos]); ok {
+			digits = append(digits, v)
+		}
+		l.pos++
+	}
+	l.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, 0)
+	}
+	for i := 0; i < len(digits); i += 2 {
+		b = append(b, digits[i]<<4|digits[i+1])
+	}
+	return pdfHex(b)
+}
+
+func hexDigit(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+func (l *pdfLexer) number() (interface{}, error) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.data) && (l.data[l.pos] == '.' || (l.data[l.pos] >= '0' && l.data[l.pos] <= '9')) {
+		l.pos++
+	}
+	s := string(l.data[start:l.pos])
+	if n, err := strconv.Atoi(s); err == nil {
+		if l.refs && n >= 0 {
+			if ref, ok := l.ref(n); ok {
+				return ref, nil
+			}
+		}
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		// Malformed numbers such as "--5" are read as 0, as readers do
+		return 0.0, nil
+	}
+	return f, nil
+}
+
+// ref reads the "g R" of a reference to object num, leaving the position
+// unchanged if it is not one.
+func (l *pdfLexer) ref(num int) (pdfRef, bool) {
+	save := l.pos
+	l.skipSpace()
+	start := l.pos
+	for l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9' {
+		l.pos++
+	}
+	if gen, err := strconv.Atoi(string(l.data[start:l.pos])); err == nil {
+		l.skipSpace()
+		if l.peek(0) == 'R' && (l.pos+1 >= len(l.data) || isPDFSpace(l.peek(1)) || isPDFDelim(l.peek(1))) {
+			l.pos++
+			return pdfRef{num, gen}, true
+		}
+	}
+	l.pos = save
+	return pdfRef{}, false
+}
+
+func (l *pdfLexer) array() ([]interface{}, error) {
+	l.pos++ // '['
+	var a []interface{}
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return a, fmt.Errorf("unterminated array")
+		}
+		if l.data[l.pos] == ']' {
+			l.pos++
+			return a, nil
+		}
+		v, err := l.next()
+		if err != nil {
+			return a, err
+		}
+		a = append(a, v)
+	}
+}
+
+func (l *pdfLexer) dict() (pdfDict, error) {
+	l.pos += 2 // '<<'
+	d := pdfDict{}
+	for {
+		l.skipSpace()
+		if l.pos >= len(l.data) {
+			return d, fmt.Errorf("unterminated dictionary")
+		}
+		if l.data[l.pos] == '>' && l.peek(1) == '>' {
+			l.pos += 2
+			return d, nil
+		}
+		k, err := l.next()
+		if err != nil {
+			return d, err
+		}
+		key, ok := k.(pdfName)
+		if !ok {
+			continue
+		}
+		v, err := l.next()
+		if err != nil {
+			return d, err
+		}
+		d[key] = v
+	}
+}
+
+// streamData reads the data of the stream whose dictionary was just read,
+// or returns nil if no stream follows. It trusts length, the stream's
+// /Length (-1 if unknown), only if endstream follows it, and otherwise
+// searches for endstream.
+func (l *pdfLexer) streamData(length int) []byte {
+	save := l.pos
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		l.pos = save
+		return nil
+	}
+	start := l.pos + len("stream")
+	if start < len(l.data) && l.data[start] == '\r' {
+		start++
+	}
+	if start < len(l.data) && l.data[start] == '\n' {
+		start++
+	}
+
+	if n := length; n >= 0 && start+n <= len(l.data) {
+		rest := bytes.TrimLeft(l.data[start+n:], "\r\n \t")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			l.pos = start + n
+			return l.data[start : start+n]
+		}
+	}
+	end := bytes.Index(l.data[start:], []byte("endstream"))
+	if end < 0 {
+		l.pos = len(l.data)
+		return l.data[start:]
+	}
+	l.pos = start + end + len("endstream")
+	return bytes.TrimRight(l.data[start:start+end], "\r\n")
+}
+
+// skipInlineImage skips an inline image's data, which follows its ID
+// operator and ends at an EI operator.
+func (l *pdfLexer) skipInlineImage() {
+	for i := l.pos + 1; i+2 <= len(l.data); i++ {
+		if l.data[i] == 'E' && l.data[i+1] == 'I' && isPDFSpace(l.data[i-1]) &&
+			(i+2 == len(l.data) || isPDFSpace(l.data[i+2])) {
+			l.pos = i + 2
+			return
+		}
+	}
+	l.pos = len(l.data)
+}
@@ -1,9 +1,12 @@
 package extractor
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"math"
+	"os"
 	"os/exec"
 	"sort"
 	"strconv"
@@ -17,9 +20,33 @@ import (
 // It tries multiple extraction methods to handle different PDF encodings.
 // If the structured PDF library fails, falls back to raw stream parsing
 // and then to the external pdftotext command (poppler-utils).
+// Encrypted PDFs need ExtractTextWithPassword unless they open without a
+// password.
 func ExtractText(filePath string) ([]string, error) {
+	return ExtractTextWithPassword(filePath, "")
+}
+
+// ExtractTextWithPassword is ExtractText for PDFs that may be encrypted,
+// with password the user or owner password; files that are not encrypted
+// ignore it. Encrypted files that do not open return ErrPasswordRequired
+// when password is empty and ErrWrongPassword otherwise.
+func ExtractTextWithPassword(filePath, password string) ([]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	// A file the raw parser crashes on may still open in the library,
+	// which decrypts it itself with the password
+	doc, err := openRawDoc(data, password)
+	if err != nil && !errors.Is(err, errRawParserCrashed) {
+		return nil, err
+	}
+	if err == nil && doc.crypt != nil {
+		return extractEncrypted(doc, password)
+	}
+
 	// First, try the structured library (best layout preservation)
-	pages, libErr := extractWithLibrary(filePath)
+	pages, libErr := extractWithLibraryFrom(bytes.NewReader(data), int64(len(data)), passwordOnce(password))
 	if libErr == nil && isReadableText(pages) {
 		return pages, nil
 	}
@@ -71,6 +98,46 @@ func ExtractText(filePath string) ([]string, error) {
 	return nil, fmt.Errorf("no readable text could be extracted from PDF (the file may be image-based/scanned, or uses custom fonts; try using the web UI which uses browser-based extraction)")
 }
 
+// extractEncrypted extracts a decrypted document's text: through the PDF
+// library when it can decrypt the file, else through the raw extractor,
+// and through the library after all when the raw text is unreadable. Both
+// decrypt in memory. The pdftotext and OCR fallbacks are not tried, as
+// they would need the password on their command line or the decrypted
+// pages written to disk.
+func extractEncrypted(doc *rawDoc, password string) ([]string, error) {
+	var pages []string
+	libErr := fmt.Errorf("encryption not supported by the PDF library")
+	libPassword, libOK := doc.crypt.libraryPassword()
+	if libPassword == "" {
+		libPassword = password
+	}
+	library := func() bool {
+		pages, libErr = extractWithLibraryFrom(bytes.NewReader(doc.data), int64(len(doc.data)), passwordOnce(libPassword))
+		return libErr == nil && isReadableText(pages)
+	}
+	if libOK && library() {
+		return pages, nil
+	}
+
+	rawPages, rawErr := extractRawText(doc)
+	if rawErr == nil && isReadableText(rawPages) {
+		return rawPages, nil
+	}
+	if !libOK && library() {
+		return pages, nil
+	}
+	if totalTextLen(pages) > 0 && textQuality(pages) > 0.3 {
+		return pages, nil
+	}
+	if totalTextLen(rawPages) > 0 && textQuality(rawPages) > 0.3 {
+		return rawPages, nil
+	}
+	if libErr != nil && rawErr != nil {
+		return nil, fmt.Errorf("encrypted PDF extraction failed: %v; raw extraction also failed: %v", libErr, rawErr)
+	}
+	return nil, fmt.Errorf("no readable text could be extracted from the encrypted PDF (scanned statements cannot be read once encrypted, as OCR would need the decrypted pages on disk)")
+}
+
 // textQuality returns the ratio of readable characters (ASCII letters, digits,
 // common punctuation, whitespace) to total characters. Returns 0.0-1.0.
 // Binary garbage typically scores below 0.4; real text scores above 0.7.
@@ -160,19 +227,33 @@ func extractWithPdftotext(filePath string) ([]string, error) {
 	return pages, nil
 }
 
-// extractWithLibrary uses the ledongthuc/pdf library with multiple methods.
-func extractWithLibrary(filePath string) (pages []string, err error) {
+// passwordOnce returns a pdf.NewReaderEncrypted password callback that
+// offers password once, then gives up.
+func passwordOnce(password string) func() string {
+	tried := false
+	return func() string {
+		if tried {
+			return ""
+		}
+		tried = true
+		return password
+	}
+}
+
+// extractWithLibraryFrom uses the ledongthuc/pdf library with multiple
+// methods on a PDF of size bytes read from f, asking pw for the password if
+// it is encrypted (see pdf.NewReaderEncrypted).
+func extractWithLibraryFrom(f io.ReaderAt, size int64, pw func() string) (pages []string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("PDF library crashed: %v", r)
 		}
 	}()
 
-	f, r, openErr := pdf.Open(filePath)
+	r, openErr := pdf.NewReaderEncrypted(f, size, pw)
 	if openErr != nil {
 		return nil, openErr
 	}
-	defer f.Close()
 
 	numPages := r.NumPage()
 	if numPages == 0 {
@@ -3,10 +3,9 @@ package extractor
 import (
 	"bytes"
 	"compress/zlib"
-	"encoding/hex"
+	"fmt"
 	"io"
 	"os"
-	"regexp"
 	"strings"
 	"unicode"
 )
@@ -14,34 +13,73 @@ import (
 // ExtractTextRaw is a fallback PDF text extractor that works directly with
 // the raw PDF byte stream. It does not rely on the ledongthuc/pdf library.
 //
-// It handles PDFs with custom font encodings (CIDFont/Type0) by:
-//  1. Finding all ToUnicode CMap streams and building character mappings
-//  2. Finding content streams with text operators (Tj, TJ)
-//  3. Decoding both literal strings (...) and hex strings <...>
-//  4. Applying CMap translations to produce readable Unicode text
+// It reads the PDF's own structure:
+//  1. The xref table locates the objects, and the /Pages tree gives the
+//     pages in order, each with the resources it has or inherits
+//  2. Each page's /Contents streams are joined in order, and form XObjects
+//     drawn with Do are read where they are drawn
+//  3. Each font gets a decoder from its ToUnicode CMap, or from its
+//     /Encoding and /Differences (WinAnsi, MacRoman) when it has none, and
+//     strings decode in the font the last Tf selected, so subset fonts that
+//     reuse the same codes each decode through their own map
+//
+// It returns one string per page, as ExtractText does. Files whose pages
+// cannot be found fall back to the text of every stream in file order, as
+// one page, with one CMap merged from all the ToUnicode streams found.
+// Encrypted files are read if they open without a password, and are
+// otherwise ErrPasswordRequired; ExtractTextWithPassword takes one.
 func ExtractTextRaw(filePath string) ([]string, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-
-	streams := extractStreams(data)
-	if len(streams) == 0 {
-		return nil, nil
+	doc, err := openRawDoc(data, "")
+	if err != nil {
+		return nil, err
 	}
+	return extractRawText(doc)
+}
 
-	// Step 1: Find and parse all ToUnicode CMap tables
-	cmaps := FindCMaps(data)
+// extractRawText is ExtractTextRaw for a document already read. Objects
+// are parsed as they are needed, so a malformed file can still crash it
+// here; that is returned as an error.
+func extractRawText(doc *rawDoc) (pages []string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pages, err = nil, fmt.Errorf("%w: %v", errRawParserCrashed, r)
+		}
+	}()
+
+	// The merged CMap decodes strings in fonts that could not be resolved
 	var cmap *CMap
-	if len(cmaps) > 0 {
+	if cmaps := FindCMaps(doc.data); len(cmaps) > 0 && doc.crypt == nil {
 		cmap = MergeCMaps(cmaps)
 	}
 
-	// Step 2: Extract text from content streams
+	if pages := doc.pages(); len(pages) > 0 {
+		texts := make([]string, len(pages))
+		found := false
+		for i, p := range pages {
+			texts[i] = extractTextFromStream(doc.pageContent(p), cmap)
+			found = found || texts[i] != ""
+		}
+		if !found {
+			return nil, nil
+		}
+		return texts, nil
+	}
+
+	// Streams found by searching the bytes cannot be decrypted
+	if doc.crypt != nil {
+		return nil, nil
+	}
 	var allText []string
-	for _, stream := range streams {
-		decompressed := tryDecompress(stream)
-		text := extractTextFromStream(decompressed, cmap)
+	for _, s := range extractStreams(doc.data) {
+		decompressed := tryDecompress(s)
+		if !hasTextOperators(decompressed) {
+			continue
+		}
+		text := extractTextFromStream(contentStream{data: decompressed}, cmap)
 		if text != "" {
 			allText = append(allText, text)
 		}
@@ -106,187 +144,143 @@ func tryDecompress(data []byte) []byte {
 	return out
 }
 
-// Patterns for PDF text operators
-var (
-	// Matches hex strings for Tj: <hex> Tj
-	hexTjPattern = regexp.MustCompile(`<([0-9A-Fa-f]+)>\s*Tj`)
-	// Matches literal strings for Tj: (text) Tj
-	litTjPattern = regexp.MustCompile(`\(([^)]*)\)\s*Tj`)
-	// Matches TJ arrays: [...] TJ
-	tjArrayPattern = regexp.MustCompile(`\[([^\]]*)\]\s*TJ`)
-	// Matches hex strings within TJ arrays
-	hexInArrayRe = regexp.MustCompile(`<([0-9A-Fa-f]+)>`)
-	// Matches literal strings within TJ arrays
-	litInArrayRe = regexp.MustCompile(`\(([^)]*)\)`)
-	// Matches ' operator
-	tickPattern = regexp.MustCompile(`\(([^)]*)\)\s*'`)
-	// Matches Td/TD operators for line detection (text positioning)
-	tdPattern = regexp.MustCompile(`([\d.\-]+)\s+([\d.\-]+)\s+T[dD]`)
-)
+// tjSpace is the TJ adjustment, in thousandths of an em, beyond which a
+// gap between strings is read as a space.
+const tjSpace = -200
 
-// extractTextFromStream parses a PDF content stream and extracts text.
-func extractTextFromStream(data []byte, cmap *CMap) string {
-	content := string(data)
+// maxFormDepth limits how deeply form XObjects may draw other forms.
+const maxFormDepth = 8
 
-	// Check if this is a content stream with text operators
-	if !strings.Contains(content, "Tj") && !strings.Contains(content, "TJ") &&
-		!strings.Contains(content, "BT") {
-		return ""
-	}
+// hasTextOperators reports whether a stream looks like it shows text.
+func hasTextOperators(data []byte) bool {
+	return bytes.Contains(data, []byte("Tj")) || bytes.Contains(data, []byte("TJ")) ||
+		bytes.Contains(data, []byte("BT"))
+}
 
-	// Process the stream sequentially to preserve text order and detect line breaks
-	// We walk through BT...ET blocks and track text position operators
-	var lines []string
-	var currentLine strings.Builder
+// extractTextFromStream walks a content stream's text operators and returns
+// its text, a line per text line. Strings are decoded in the font the last
+// Tf selected from the stream's fonts, or with the fallback CMap when it is
+// not there.
+func extractTextFromStream(s contentStream, fallback *CMap) string {
+	w := &textWriter{fallback: fallback}
+	w.run(s, 0)
+	w.endLine()
+	return strings.TrimSpace(strings.Join(w.lines, "\n"))
+}
 
-	// Split into BT...ET text blocks
-	btBlocks := splitBTBlocks(content)
-	for _, block := range btBlocks {
-		blockLines := processTextBlock(block, cmap)
-		lines = append(lines, blockLines...)
-	}
+// textWriter collects the lines of text content streams show.
+type textWriter struct {
+	fallback *CMap
+	lines    []string
+	line     strings.Builder
+}
 
-	// If no BT blocks found, try global extraction
-	if len(lines) == 0 {
-		text := extractAllText(content, cmap)
-		if text != "" {
-			lines = append(lines, text)
-		}
+func (w *textWriter) endLine() {
+	if text := strings.TrimSpace(w.line.String()); text != "" {
+		w.lines = append(w.lines, text)
 	}
-
-	_ = currentLine // used in processTextBlock
-	result := strings.Join(lines, "\n")
-	return strings.TrimSpace(result)
+	w.line.Reset()
 }
 
-// splitBTBlocks extracts content between BT and ET operators.
-func splitBTBlocks(content string) []string {
-	var blocks []string
-	remaining := content
+// run interprets the stream's operators; forms it draws are run in turn,
+// depth deep.
+func (w *textWriter) run(s contentStream, depth int) {
+	var font *fontDecoder
+	show := func(v interface{}) {
+		w.line.WriteString(decodeShown(v, font, w.fallback))
+	}
+
+	lex := &pdfLexer{data: s.data}
+	var operands []interface{}
 	for {
-		btIdx := strings.Index(remaining, "BT")
-		if btIdx < 0 {
+		v, err := lex.next()
+		if err != nil {
 			break
 		}
-		etIdx := strings.Index(remaining[btIdx:], "ET")
-		if etIdx < 0 {
-			break
+		op, ok := v.(pdfKeyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
 		}
-		block := remaining[btIdx : btIdx+etIdx+2]
-		blocks = append(blocks, block)
-		remaining = remaining[btIdx+etIdx+2:]
-	}
-	return blocks
-}
-
-// processTextBlock extracts lines of text from a BT...ET block.
-func processTextBlock(block string, cmap *CMap) []string {
-	var lines []string
-	var currentLine strings.Builder
-
-	// Process line by line within the block
-	ops := strings.Split(block, "\n")
-	for _, op := range ops {
-		op = strings.TrimSpace(op)
-
-		// Check for text positioning that implies a new line
-		// Td/TD with negative Y offset means new line
-		if tdPattern.MatchString(op) {
-			if currentLine.Len() > 0 {
-				line := strings.TrimSpace(currentLine.String())
-				if line != "" {
-					lines = append(lines, line)
-				}
-				currentLine.Reset()
+		n := len(operands)
+		switch op {
+		case "Tf":
+			if n >= 2 {
+				name, _ := operands[n-2].(pdfName)
+				font = s.fonts[name]
 			}
-		}
-
-		// T* operator means new line
-		if op == "T*" {
-			if currentLine.Len() > 0 {
-				line := strings.TrimSpace(currentLine.String())
-				if line != "" {
-					lines = append(lines, line)
-				}
-				currentLine.Reset()
+		case "Td", "TD", "Tm", "T*", "ET":
+			// Text positioning starts a new line
+			w.endLine()
+		case "Tj":
+			if n >= 1 {
+				show(operands[n-1])
 			}
-		}
-
-		// Extract text from Tj with hex strings
-		for _, m := range hexTjPattern.FindAllStringSubmatch(op, -1) {
-			text := decodeHexString(m[1], cmap)
-			currentLine.WriteString(text)
-		}
-
-		// Extract text from Tj with literal strings
-		for _, m := range litTjPattern.FindAllStringSubmatch(op, -1) {
-			text := decodeLiteralString(m[1], cmap)
-			currentLine.WriteString(text)
-		}
-
-		// Extract text from TJ arrays
-		for _, m := range tjArrayPattern.FindAllStringSubmatch(op, -1) {
-			text := decodeTJArray(m[1], cmap)
-			currentLine.WriteString(text)
-		}
-
-		// Extract text from ' operator
-		for _, m := range tickPattern.FindAllStringSubmatch(op, -1) {
-			if currentLine.Len() > 0 {
-				line := strings.TrimSpace(currentLine.String())
-				if line != "" {
-					lines = append(lines, line)
+		case "'", "\"":
+			w.endLine()
+			if n >= 1 {
+				show(operands[n-1])
+			}
+		case "TJ":
+			if n < 1 {
+				break
+			}
+			array, _ := operands[n-1].([]interface{})
+			for _, el := range array {
+				if gap, ok := pdfNumber(el); ok {
+					if gap < tjSpace && !strings.HasSuffix(w.line.String(), " ") {
+						w.line.WriteByte(' ')
+					}
+					continue
 				}
-				currentLine.Reset()
+				show(el)
 			}
-			text := decodeLiteralString(m[1], cmap)
-			currentLine.WriteString(text)
-		}
-	}
-
-	if currentLine.Len() > 0 {
-		line := strings.TrimSpace(currentLine.String())
-		if line != "" {
-			lines = append(lines, line)
+		case "Do":
+			if n < 1 || depth >= maxFormDepth {
+				break
+			}
+			name, _ := operands[n-1].(pdfName)
+			if form, ok := s.form(name); ok {
+				w.run(form, depth+1)
+			}
+		case "ID":
+			lex.skipInlineImage()
 		}
+		operands = operands[:0]
 	}
-
-	return lines
 }
 
-// extractAllText extracts all text from content without BT/ET block structure.
-func extractAllText(content string, cmap *CMap) string {
-	var parts []string
-
-	for _, m := range hexTjPattern.FindAllStringSubmatch(content, -1) {
-		text := decodeHexString(m[1], cmap)
-		if text != "" {
-			parts = append(parts, text)
+// decodeShown decodes a string operand of a text-showing operator.
+func decodeShown(v interface{}, font *fontDecoder, fallback *CMap) string {
+	switch s := v.(type) {
+	case pdfHex:
+		if font != nil {
+			return font.decode(s)
 		}
-	}
-	for _, m := range litTjPattern.FindAllStringSubmatch(content, -1) {
-		text := decodeLiteralString(m[1], cmap)
-		if text != "" {
-			parts = append(parts, text)
-		}
-	}
-	for _, m := range tjArrayPattern.FindAllStringSubmatch(content, -1) {
-		text := decodeTJArray(m[1], cmap)
-		if text != "" {
-			parts = append(parts, text)
+		return decodeHexString(s, fallback)
+	case []byte:
+		if font != nil {
+			return font.decode(s)
 		}
+		return decodeLiteralString(s, fallback)
 	}
-
-	return strings.Join(parts, " ")
+	return ""
 }
 
-// decodeHexString decodes a hex-encoded PDF string using CMap if available.
-func decodeHexString(hexStr string, cmap *CMap) string {
-	raw, err := hex.DecodeString(hexStr)
-	if err != nil {
-		return ""
+// pdfNumber returns an integer or real operand as a float.
+func pdfNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
 	}
+	return 0, false
+}
 
+// decodeHexString decodes a hex string in an unknown font using CMap if
+// available.
+func decodeHexString(raw []byte, cmap *CMap) string {
 	// Try CMap decoding first
 	if cmap != nil && len(cmap.charMap) > 0 {
 		result := cmap.Decode(raw)
@@ -296,141 +290,42 @@ func decodeHexString(hexStr string, cmap *CMap) string {
 	}
 
 	// Fallback: try as direct UTF-16BE
-	if len(raw)%2 == 0 && len(raw) >= 2 {
-		var result strings.Builder
-		for i := 0; i+1 < len(raw); i += 2 {
-			cp := rune(raw[i])<<8 | rune(raw[i+1])
-			if unicode.IsPrint(cp) || cp == ' ' {
-				result.WriteRune(cp)
-			}
-		}
-		if result.Len() > 0 {
-			return result.String()
-		}
+	if result := utf16Text(raw); result != "" {
+		return result
 	}
 
 	// Last resort: treat as ASCII
 	return cleanString(string(raw))
 }
 
-// decodeLiteralString decodes a literal PDF string using CMap if available.
-func decodeLiteralString(s string, cmap *CMap) string {
-	decoded := decodePDFEscapes(s)
+// utf16Text reads raw as UTF-16BE, dropping unprintable characters; it
+// returns "" for odd-length input.
+func utf16Text(raw []byte) string {
+	if len(raw)%2 != 0 || len(raw) < 2 {
+		return ""
+	}
+	var result strings.Builder
+	for i := 0; i+1 < len(raw); i += 2 {
+		cp := rune(raw[i])<<8 | rune(raw[i+1])
+		if unicode.IsPrint(cp) || cp == ' ' {
+			result.WriteRune(cp)
+		}
+	}
+	return result.String()
+}
 
+// decodeLiteralString decodes a literal string in an unknown font using
+// CMap if available.
+func decodeLiteralString(raw []byte, cmap *CMap) string {
 	// Try CMap decoding
 	if cmap != nil && len(cmap.charMap) > 0 {
-		result := cmap.Decode([]byte(decoded))
+		result := cmap.Decode(raw)
 		if result != "" && isPrintable(result) {
 			return result
 		}
 	}
 
-	return cleanString(decoded)
-}
-
-// decodeTJArray decodes a TJ array, which contains a mix of strings and numbers.
-func decodeTJArray(arrayContent string, cmap *CMap) string {
-	var parts []string
-
-	// Extract hex strings
-	hexMatches := hexInArrayRe.FindAllStringSubmatchIndex(arrayContent, -1)
-	litMatches := litInArrayRe.FindAllStringSubmatchIndex(arrayContent, -1)
-
-	// Combine and sort by position
-	type match struct {
-		pos    int
-		isHex  bool
-		groups []string
-	}
-	var all []match
-
-	for _, idx := range hexMatches {
-		all = append(all, match{
-			pos:   idx[0],
-			isHex: true,
-			groups: []string{
-				arrayContent[idx[0]:idx[1]],
-				arrayContent[idx[2]:idx[3]],
-			},
-		})
-	}
-	for _, idx := range litMatches {
-		all = append(all, match{
-			pos:   idx[0],
-			isHex: false,
-			groups: []string{
-				arrayContent[idx[0]:idx[1]],
-				arrayContent[idx[2]:idx[3]],
-			},
-		})
-	}
-
-	// Sort by position
-	for i := 1; i < len(all); i++ {
-		for j := i; j > 0 && all[j].pos < all[j-1].pos; j-- {
-			all[j], all[j-1] = all[j-1], all[j]
-		}
-	}
-
-	for _, m := range all {
-		var text string
-		if m.isHex {
-			text = decodeHexString(m.groups[1], cmap)
-		} else {
-			text = decodeLiteralString(m.groups[1], cmap)
-		}
-		if text != "" {
-			parts = append(parts, text)
-		}
-	}
-
-	return strings.Join(parts, "")
-}
-
-// decodePDFEscapes handles basic PDF string escape sequences.
-func decodePDFEscapes(s string) string {
-	var buf strings.Builder
-	i := 0
-	for i < len(s) {
-		if s[i] == '\\' && i+1 < len(s) {
-			i++
-			switch s[i] {
-			case 'n':
-				buf.WriteByte('\n')
-			case 'r':
-				buf.WriteByte('\r')
-			case 't':
-				buf.WriteByte('\t')
-			case 'b':
-				buf.WriteByte('\b')
-			case 'f':
-				buf.WriteByte('\f')
-			case '(':
-				buf.WriteByte('(')
-			case ')':
-				buf.WriteByte(')')
-			case '\\':
-				buf.WriteByte('\\')
-			default:
-				if s[i] >= '0' && s[i] <= '7' {
-					val := int(s[i] - '0')
-					for j := 1; j < 3 && i+j < len(s) && s[i+j] >= '0' && s[i+j] <= '7'; j++ {
-						val = val*8 + int(s[i+j]-'0')
-						i++
-					}
-					if val >= 0 && val < 256 {
-						buf.WriteByte(byte(val))
-					}
-				} else {
-					buf.WriteByte(s[i])
-				}
-			}
-		} else {
-			buf.WriteByte(s[i])
-		}
-		i++
-	}
-	return buf.String()
+	return cleanString(string(raw))
 }
 
 // cleanString removes non-printable characters.
@@ -457,7 +352,10 @@ func isPrintable(s string) bool {
 	return float64(printable)/float64(len([]rune(s))) > 0.5
 }
 
-// mergePageText groups extracted text into logical pages.
+// mergePageText joins the text of streams read without the page tree into
+// one page. Fragments of 10 characters or fewer are dropped: without the
+// page tree every stream is read, and those are mostly noise from font and
+// image data.
 func mergePageText(texts []string) []string {
 	var pages []string
 	var current strings.Builder
@@ -0,0 +1,245 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// buildPDF assembles a PDF from object bodies, numbered from 1, with an
+// xref table and object 1 as the catalog.
+func buildPDF(objects ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// streamObject is a stream object body with data, and entries added to its
+// dictionary.
+func streamObject(entries, data string) string {
+	return fmt.Sprintf("<< /Length %d %s>>\nstream\n%s\nendstream", len(data), entries, data)
+}
+
+func writePDFFile(t *testing.T, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.pdf")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// toUnicode is a ToUnicode CMap mapping one-byte codes to characters.
+func toUnicode(chars map[byte]rune) string {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	fmt.Fprintf(&b, "%d beginbfchar\n", len(chars))
+	for code := 0; code < 256; code++ {
+		if r, ok := chars[byte(code)]; ok {
+			fmt.Fprintf(&b, "<%02X> <%04X>\n", code, r)
+		}
+	}
+	b.WriteString("endbfchar\nendcmap\nend\nend")
+	return streamObject("", b.String())
+}
+
+func TestExtractTextRawPerFontCMaps(t *testing.T) {
+	// Two subset fonts use the same codes for different letters; each
+	// must decode through its own ToUnicode CMap
+	content := "BT /F1 10 Tf 50 700 Td <010203> Tj ET\n" +
+		"BT /F2 10 Tf 50 680 Td <010203> Tj ET\n" +
+		"BT /F1 10 Tf 50 660 Td [<01> -400 <0203>] TJ ET"
+	path := writePDFFile(t, buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R /F2 6 0 R >> >> /Contents 4 0 R >>",
+		streamObject("", content),
+		"<< /Type /Font /Subtype /TrueType /BaseFont /AAAAAA+Arial /ToUnicode 7 0 R >>",
+		"<< /Type /Font /Subtype /TrueType /BaseFont /BBBBBB+Arial-Bold /ToUnicode 8 0 R >>",
+		toUnicode(map[byte]rune{1: 'T', 2: 'O', 3: 'M'}),
+		toUnicode(map[byte]rune{1: 'B', 2: 'U', 3: 'S'}),
+	))
+
+	pages, err := ExtractTextRaw(path)
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	if got := strings.Join(pages, "\n"); got != "TOM\nBUS\nT OM" {
+		t.Errorf("text = %q, want %q", got, "TOM\nBUS\nT OM")
+	}
+}
+
+func TestExtractTextRawEncodings(t *testing.T) {
+	// Without a CMap, fonts decode through their encoding: WinAnsi,
+	// MacRoman, or a base encoding patched by /Differences
+	content := `BT /W 10 Tf 50 700 Td (Caf\351 \200 \2433.50) Tj ET
+BT /M 10 Tf 50 680 Td (Caf\216 \243) Tj ET
+BT /D 10 Tf 50 660 Td (\001\002\003 \004 \005) Tj ET`
+	path := writePDFFile(t, buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /W 5 0 R /M 6 0 R /D 7 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("", content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /MacRomanEncoding >>",
+		"<< /Type /Font /Subtype /Type3 /Encoding 8 0 R >>",
+		"<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [1 /P /a /y 4 /sterling /uni20AC] >>",
+	))
+
+	pages, err := ExtractTextRaw(path)
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	want := "Café € £3.50\nCafé £\nPay £ €"
+	if got := strings.Join(pages, "\n"); got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestExtractTextRawUnresolvedFont(t *testing.T) {
+	// A font missing from the resources decodes as before: literal strings
+	// as they are, hex strings through the merged CMaps or as UTF-16
+	content := "BT /F9 10 Tf 50 700 Td (Statement \\(copy\\)) Tj ET\n" +
+		"BT /F9 10 Tf 50 680 Td <00500061006900640020006F00750074> Tj ET"
+	path := writeTestPDF(t, content)
+
+	pages, err := ExtractTextRaw(path)
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	want := "Statement (copy)\nPaid out"
+	if got := strings.Join(pages, "\n"); got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+}
+
+func TestGlyphRune(t *testing.T) {
+	tests := map[string]rune{
+		"A": 'A', "eacute": 'é', "Eacute": 'É', "ae": 'æ', "divide": '÷', "sterling": '£',
+		"uni20AC": '€', "u1F600": '\U0001F600', "one.oldstyle": '1', "g12": 0, "": 0,
+	}
+	for name, want := range tests {
+		if got := glyphRune(name); got != want {
+			t.Errorf("glyphRune(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// helvetica is a font dictionary for the tests' resources.
+const helvetica = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"
+
+func TestExtractTextRawPageTree(t *testing.T) {
+	// The page tree orders the pages, not the file: page 1 is the last
+	// object, inside a nested /Pages node that its resources come from.
+	// Page 2's content is split across two streams mid text object, and
+	// its short text is kept as a page of its own.
+	path := writePDFFile(t, buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [9 0 R 3 0 R] /Count 2 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 6 0 R >> >> /Contents [4 0 R 5 0 R] >>",
+		streamObject("", "BT /F1 10 Tf 50 700 Td (Page"),
+		streamObject("", " 2) Tj ET"),
+		helvetica,
+		streamObject("", "BT /F1 10 Tf 50 700 Td (Opening balance 100.00) Tj 0 -20 Td (Closing balance 90.00) Tj ET"),
+		"<< /Type /Page /Parent 9 0 R /Contents 7 0 R >>",
+		"<< /Type /Pages /Parent 2 0 R /Kids [8 0 R] /Count 1 /Resources << /Font << /F1 6 0 R >> >> >>",
+	))
+
+	pages, err := ExtractTextRaw(path)
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	want := []string{"Opening balance 100.00\nClosing balance 90.00", "Page 2"}
+	if fmt.Sprintf("%q", pages) != fmt.Sprintf("%q", want) {
+		t.Errorf("pages = %q, want %q", pages, want)
+	}
+}
+
+func TestExtractTextRawForms(t *testing.T) {
+	// Text drawn from a form XObject is read where the page draws it, in
+	// the form's own fonts
+	path := writePDFFile(t, buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> /XObject << /Fm1 6 0 R >> >> /Contents 4 0 R >>",
+		streamObject("", "BT /F1 10 Tf 50 750 Td (Header) Tj ET q /Fm1 Do Q BT /F1 10 Tf 50 650 Td (Footer) Tj ET"),
+		helvetica,
+		streamObject("/Type /XObject /Subtype /Form /BBox [0 0 595 842] /Resources << /Font << /F1 7 0 R >> >> ",
+			"BT /F1 10 Tf 50 700 Td (\\001\\002) Tj ET"),
+		"<< /Type /Font /Subtype /Type3 /Encoding << /Differences [1 /T /o] >> >>",
+	))
+
+	pages, err := ExtractTextRaw(path)
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	if got := strings.Join(pages, "|"); got != "Header\nTo\nFooter" {
+		t.Errorf("text = %q, want %q", got, "Header\nTo\nFooter")
+	}
+}
+
+func TestExtractTextRawIncrementalUpdate(t *testing.T) {
+	// An update appends a new version of the page content and an xref
+	// section pointing to it, with /Prev pointing to the original
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /Resources << /Font << /F1 5 0 R >> >> >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		streamObject("", "BT /F1 10 Tf 50 700 Td (Draft statement) Tj ET"),
+		helvetica,
+	)
+	prev := string(bytes.Fields(data[bytes.LastIndex(data, []byte("startxref")):])[1])
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	offset := buf.Len()
+	fmt.Fprintf(&buf, "4 0 obj\n%s\nendobj\n", streamObject("", "BT /F1 10 Tf 50 700 Td (Final statement) Tj ET"))
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n4 1\n%010d 00000 n \ntrailer\n<< /Size 6 /Root 1 0 R /Prev %s >>\nstartxref\n%d\n%%%%EOF\n", offset, prev, xref)
+
+	pages, err := ExtractTextRaw(writePDFFile(t, buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ExtractTextRaw: %v", err)
+	}
+	if got := strings.Join(pages, "|"); got != "Final statement" {
+		t.Errorf("text = %q, want the updated content", got)
+	}
+}
+
+func TestExtractTextRawBrokenXref(t *testing.T) {
+	// Offsets that do not point at their objects, and a file with no xref
+	// at all, are read by scanning for the objects
+	data := buildPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 5 0 R >> >> /Contents 4 0 R >>",
+		streamObject("", "BT /F1 10 Tf 50 700 Td (Balance carried forward) Tj ET"),
+		helvetica,
+	)
+	shifted := bytes.Replace(data, []byte("%PDF-1.4\n"), []byte("%PDF-1.4\n%% padding\n"), 1)
+	noXref := data[:bytes.Index(data, []byte("xref"))]
+
+	for name, pdf := range map[string][]byte{"shifted": shifted, "no xref": noXref} {
+		pages, err := ExtractTextRaw(writePDFFile(t, pdf))
+		if err != nil {
+			t.Fatalf("%s: ExtractTextRaw: %v", name, err)
+		}
+		if got := strings.Join(pages, "|"); got != "Balance carried forward" {
+			t.Errorf("%s: text = %q", name, got)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Word is a run of text on a page with its position, so callers can tell
+// which table column it sits in. Coordinates are in points from the bottom
+// left of the page, as in the PDF itself.
+type Word struct {
+	Page     int     // 1-based page number
+	X, Y     float64 // start of the word's baseline
+	Width    float64
+	Font     string
+	FontSize float64
+	Text     string
+}
+
+// Right returns the x coordinate of the end of the word.
+func (w Word) Right() float64 {
+	return w.X + w.Width
+}
+
+// estimatedGlyphWidth is the width, as a fraction of the font size, given to
+// glyphs whose font has no width table (the standard 14 fonts often don't):
+// about the width of a digit in the common sans-serif fonts.
+const estimatedGlyphWidth = 0.55
+
+// ExtractWords reads a PDF file and returns every word on every page with its
+// position, font and size, in content-stream order. Unlike ExtractText it
+// uses only the structured PDF library: there are no positions to recover
+// from the raw-stream, pdftotext or OCR fallbacks.
+func ExtractWords(filePath string) (words []Word, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("PDF library crashed: %v", r)
+		}
+	}()
+
+	f, r, err := pdf.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	numPages := r.NumPage()
+	if numPages == 0 {
+		return nil, fmt.Errorf("PDF has no pages")
+	}
+	for i := 1; i <= numPages; i++ {
+		page := r.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		words = append(words, wordsFromText(i, page.Content().Text)...)
+	}
+	return words, nil
+}
+
+// wordsFromText joins the library's per-glyph text into words. A glyph
+// continues the current word when it is on the same baseline and starts
+// where the word ends, allowing for kerning; spaces and gaps wider than a
+// kern end it. When the font has no widths the library leaves every glyph
+// of a string at the string's start, so such glyphs are laid end to end
+// using the estimated width.
+func wordsFromText(page int, glyphs []pdf.Text) []Word {
+	var words []Word
+	var cur *Word
+	var lastX, lastEnd float64 // where the library put the last glyph, and where it ends
+	flush := func() {
+		if cur != nil {
+			words = append(words, *cur)
+			cur = nil
+		}
+	}
+	for i, g := range glyphs {
+		size := math.Max(math.Abs(g.FontSize), 1)
+		width := g.W
+		if width <= 0 {
+			width = estimatedGlyphWidth * size * float64(len([]rune(g.S)))
+		}
+		start := g.X
+		if i > 0 && g.W <= 0 && g.X == lastX {
+			start = lastEnd // the library did not advance past the last glyph
+		}
+		lastX, lastEnd = g.X, start+width
+
+		if strings.TrimSpace(g.S) == "" {
+			flush()
+			continue
+		}
+		if cur != nil {
+			gap := start - cur.Right()
+			if g.Font != cur.Font || math.Abs(g.Y-cur.Y) > 0.2*size || gap < -0.3*size || gap > 0.15*size {
+				flush()
+			}
+		}
+		if cur == nil {
+			cur = &Word{Page: page, X: start, Y: g.Y, Font: g.Font, FontSize: g.FontSize}
+		}
+		cur.Text += g.S
+		cur.Width = start + width - cur.X
+	}
+	flush()
+	return words
+}
+
+// Line is the words sharing a baseline, left to right.
+type Line struct {
+	Page  int
+	Y     float64
+	Words []Word
+}
+
+// Text returns the line's words separated by single spaces.
+func (l Line) Text() string {
+	parts := make([]string, len(l.Words))
+	for i, w := range l.Words {
+		parts[i] = w.Text
+	}
+	return strings.Join(parts, " ")
+}
+
+// GroupLines groups words into lines, in reading order: by page, then top to
+// bottom. Words join a line when their baselines are within a third of the
+// font size of its first word's.
+func GroupLines(words []Word) []Line {
+	sorted := make([]Word, len(words))
+	copy(sorted, words)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Page != sorted[j].Page {
+			return sorted[i].Page < sorted[j].Page
+		}
+		return sorted[i].Y > sorted[j].Y
+	})
+
+	var lines []Line
+	for _, w := range sorted {
+		if n := len(lines); n > 0 {
+			l := &lines[n-1]
+			tolerance := math.Max(l.Words[0].FontSize, 1) / 3
+			if l.Page == w.Page && l.Y-w.Y <= tolerance {
+				l.Words = append(l.Words, w)
+				continue
+			}
+		}
+		lines = append(lines, Line{Page: w.Page, Y: w.Y, Words: []Word{w}})
+	}
+	for _, l := range lines {
+		sort.SliceStable(l.Words, func(i, j int) bool { return l.Words[i].X < l.Words[j].X })
+	}
+	return lines
+}
+
+// Column is a table column: its heading and the horizontal extent of the
+// heading and the words found under it.
+type Column struct {
+	Name        string
+	Left, Right float64
+}
+
+// Table is a statement's transaction table on one page.
+type Table struct {
+	Page int
+	// Header is the heading line; it is empty on a page without one, which
+	// carries on the previous page's columns.
+	Header  Line
+	Columns []Column // left to right
+	Rows    []Line   // the lines below the header
+}
+
+// DetectTables finds the transaction table on each page from its header
+// row: the first line on the page containing at least two of headings (for
+// example "Date", "Description", "Paid out", "Paid in", "Balance"), matched
+// case-insensitively as whole words. Pages without a header row continue
+// the previous page's table, with every line a row; pages before the first
+// header row have no table.
+//
+// Each column starts as the extent of its heading, then grows to cover the
+// words below that overlap it and no other heading, so right-aligned amounts
+// wider than their heading still fall inside their column.
+func DetectTables(words []Word, headings ...string) []Table {
+	var tables []Table
+	var columns []Column
+	lines := GroupLines(words)
+	for start := 0; start < len(lines); {
+		page := lines[start].Page
+		end := start
+		for end < len(lines) && lines[end].Page == page {
+			end++
+		}
+
+		t := Table{Page: page, Rows: lines[start:end]}
+		for i, l := range lines[start:end] {
+			if cols := headerColumns(l, headings); len(cols) >= 2 {
+				t.Header, t.Rows, columns = l, lines[start+i+1:end], cols
+				growColumns(columns, t.Rows)
+				break
+			}
+		}
+		if columns != nil {
+			t.Columns = columns
+			tables = append(tables, t)
+		}
+		start = end
+	}
+	return tables
+}
+
+// headerColumns returns a column for each heading found in the line, left
+// to right.
+func headerColumns(l Line, headings []string) []Column {
+	var cols []Column
+	for _, h := range headings {
+		want := strings.Fields(strings.ToLower(h))
+		if len(want) == 0 {
+			continue
+		}
+	search:
+		for i := 0; i+len(want) <= len(l.Words); i++ {
+			for j, part := range want {
+				if strings.ToLower(l.Words[i+j].Text) != part {
+					continue search
+				}
+			}
+			last := l.Words[i+len(want)-1]
+			cols = append(cols, Column{Name: h, Left: l.Words[i].X, Right: last.Right()})
+			break
+		}
+	}
+	sort.SliceStable(cols, func(i, j int) bool { return cols[i].Left < cols[j].Left })
+	return cols
+}
+
+// growColumns widens each column to the words below it that overlap its
+// heading and no other.
+func growColumns(cols []Column, rows []Line) {
+	headings := make([]Column, len(cols))
+	copy(headings, cols)
+	for _, l := range rows {
+		for _, w := range l.Words {
+			match := -1
+			for i, h := range headings {
+				if w.X < h.Right && w.Right() > h.Left {
+					if match >= 0 {
+						match = -1
+						break
+					}
+					match = i
+				}
+			}
+			if match < 0 {
+				continue
+			}
+			cols[match].Left = math.Min(cols[match].Left, w.X)
+			cols[match].Right = math.Max(cols[match].Right, w.Right())
+		}
+	}
+}
+
+// Column returns the name of the column a word belongs to: the one whose
+// span contains the word's centre, where neighbouring columns divide the
+// space between them at its midpoint. Words left of the first column or
+// right of the last belong to it.
+func (t Table) Column(w Word) string {
+	if len(t.Columns) == 0 {
+		return ""
+	}
+	centre := w.X + w.Width/2
+	for i := 0; i < len(t.Columns)-1; i++ {
+		a, b := t.Columns[i], t.Columns[i+1]
+		if centre < (a.Right+b.Left)/2 {
+			return a.Name
+		}
+	}
+	return t.Columns[len(t.Columns)-1].Name
+}
+
+// Cells splits a row into its columns' text, keyed by column name; words in
+// the same column are joined with spaces.
+func (t Table) Cells(l Line) map[string]string {
+	cells := make(map[string]string)
+	for _, w := range l.Words {
+		name := t.Column(w)
+		if cells[name] != "" {
+			cells[name] += " "
+		}
+		cells[name] += w.Text
+	}
+	return cells
+}
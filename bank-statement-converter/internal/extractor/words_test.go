@@ -0,0 +1,205 @@
+package extractor
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// writeTestPDF writes a PDF with one page per content stream, in
+// Helvetica as font /F1, and returns its path.
+func writeTestPDF(t *testing.T, contents ...string) string {
+	t.Helper()
+	kids := make([]string, len(contents))
+	for i := range contents {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(contents)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	for i, content := range contents {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i),
+			streamObject("", content))
+	}
+	return writePDFFile(t, buildPDF(objects...))
+}
+
+// glyphs spells s one glyph per character from x, each w wide, as the PDF
+// library reports text.
+func glyphs(s string, x, y, w float64) []pdf.Text {
+	var out []pdf.Text
+	for _, r := range s {
+		out = append(out, pdf.Text{Font: "Helvetica", FontSize: 10, X: x, Y: y, W: w, S: string(r)})
+		x += w
+	}
+	return out
+}
+
+func TestWordsFromText(t *testing.T) {
+	var text []pdf.Text
+	text = append(text, glyphs("Card payment", 50, 700, 5)...)
+	text = append(text, glyphs("12.50", 300, 700, 5)...)
+	// A font without widths: every glyph of the string is at its start
+	for _, r := range "Paid out" {
+		text = append(text, pdf.Text{Font: "Helvetica", FontSize: 10, X: 400, Y: 700, S: string(r)})
+	}
+
+	got := wordsFromText(2, text)
+	want := []Word{
+		{Page: 2, X: 50, Y: 700, Width: 20, Text: "Card"},
+		{Page: 2, X: 75, Y: 700, Width: 35, Text: "payment"},
+		{Page: 2, X: 300, Y: 700, Width: 25, Text: "12.50"},
+		{Page: 2, X: 400, Y: 700, Width: 22, Text: "Paid"},
+		{Page: 2, X: 427.5, Y: 700, Width: 16.5, Text: "out"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d words %+v, want %d", len(got), got, len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Text != w.Text || g.Page != w.Page || !near(g.X, w.X) || !near(g.Y, w.Y) || !near(g.Width, w.Width) {
+			t.Errorf("word %d = %q page %d at (%.2f, %.2f) width %.2f, want %q page %d at (%.2f, %.2f) width %.2f",
+				i, g.Text, g.Page, g.X, g.Y, g.Width, w.Text, w.Page, w.X, w.Y, w.Width)
+		}
+		if g.Font != "Helvetica" || g.FontSize != 10 {
+			t.Errorf("word %d font = %s %.1f, want Helvetica 10", i, g.Font, g.FontSize)
+		}
+	}
+}
+
+func TestWordsFromTextKerning(t *testing.T) {
+	// A small kern inside a word keeps it whole; a word-sized gap splits it
+	text := glyphs("AV", 10, 500, 6)
+	text = append(text, glyphs("E", 21.5, 500, 6)...)
+	text = append(text, glyphs("X", 40, 500, 6)...)
+	text = append(text, glyphs("Y", 46, 480, 6)...)
+
+	var got []string
+	for _, w := range wordsFromText(1, text) {
+		got = append(got, w.Text)
+	}
+	if strings.Join(got, "|") != "AVE|X|Y" {
+		t.Errorf("words = %q, want AVE|X|Y", got)
+	}
+}
+
+// statementWords lays out a two-page statement table; the second page
+// carries on without a header row.
+func statementWords() []Word {
+	word := func(page int, x, y float64, s string) Word {
+		return Word{Page: page, X: x, Y: y, Width: 5 * float64(len(s)), FontSize: 10, Text: s}
+	}
+	return []Word{
+		word(1, 50, 800, "Balance"), word(1, 100, 800, "summary"),
+		word(1, 50, 700, "Date"), word(1, 100, 700, "Description"),
+		word(1, 300, 700, "Paid"), word(1, 325, 700, "out"),
+		word(1, 400, 700, "Paid"), word(1, 425, 700, "in"),
+		word(1, 500, 700, "Balance"),
+		// Amounts right-aligned under their headings, wider than them
+		word(1, 50, 680, "01/03"), word(1, 100, 680, "TESCO"),
+		word(1, 295, 680, "1,234.56"), word(1, 495, 680, "8,765.44"),
+		word(1, 50, 660, "02/03"), word(1, 100, 660, "SALARY"),
+		word(1, 380, 661, "2,000.00"), word(1, 495, 660, "10,765.44"),
+		word(2, 50, 800, "03/03"), word(2, 100, 800, "RENT"),
+		word(2, 310, 800, "950.00"), word(2, 495, 800, "9,815.44"),
+	}
+}
+
+func TestDetectTables(t *testing.T) {
+	tables := DetectTables(statementWords(), "Date", "Description", "Paid out", "Paid in", "Balance")
+	if len(tables) != 2 {
+		t.Fatalf("got %d tables, want 2", len(tables))
+	}
+
+	first := tables[0]
+	if first.Header.Text() != "Date Description Paid out Paid in Balance" {
+		t.Errorf("header = %q", first.Header.Text())
+	}
+	var names []string
+	for _, c := range first.Columns {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, "|") != "Date|Description|Paid out|Paid in|Balance" {
+		t.Errorf("columns = %q", names)
+	}
+	if paidIn := first.Columns[3]; paidIn.Left != 380 || paidIn.Right != 435 {
+		t.Errorf("Paid in column = %.0f-%.0f, want 380-435 (grown to its amounts)", paidIn.Left, paidIn.Right)
+	}
+
+	wantRows := []map[string]string{
+		{"Date": "01/03", "Description": "TESCO", "Paid out": "1,234.56", "Balance": "8,765.44"},
+		{"Date": "02/03", "Description": "SALARY", "Paid in": "2,000.00", "Balance": "10,765.44"},
+		{"Date": "03/03", "Description": "RENT", "Paid out": "950.00", "Balance": "9,815.44"},
+	}
+	rows := append(append([]Line{}, first.Rows...), tables[1].Rows...)
+	if len(rows) != len(wantRows) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(wantRows))
+	}
+	for i, want := range wantRows {
+		cells := tables[0].Cells(rows[i])
+		if rows[i].Page == 2 {
+			cells = tables[1].Cells(rows[i])
+		}
+		if fmt.Sprint(cells) != fmt.Sprint(want) {
+			t.Errorf("row %d cells = %v, want %v", i, cells, want)
+		}
+	}
+
+	if tables[1].Page != 2 || len(tables[1].Header.Words) != 0 {
+		t.Errorf("second table = page %d with header %q, want page 2 without one", tables[1].Page, tables[1].Header.Text())
+	}
+}
+
+func TestDetectTablesNoHeader(t *testing.T) {
+	// One heading alone is not a header row
+	words := []Word{{Page: 1, X: 50, Y: 700, Width: 35, FontSize: 10, Text: "Balance"}}
+	if tables := DetectTables(words, "Paid out", "Balance"); len(tables) != 0 {
+		t.Errorf("got %d tables, want none", len(tables))
+	}
+}
+
+func TestExtractWords(t *testing.T) {
+	path := writeTestPDF(t,
+		"BT /F1 10 Tf 50 700 Td (Date) Tj ET\n"+
+			"BT /F1 10 Tf 300 700 Td (Paid out) Tj ET\n"+
+			"BT /F1 10 Tf 400 700 Td (Balance) Tj ET\n"+
+			"BT /F1 10 Tf 50 680 Td (01/03) Tj ET\n"+
+			"BT /F1 10 Tf 298 680 Td (12.50) Tj ET\n"+
+			"BT /F1 10 Tf 395 680 Td (100.00) Tj ET",
+		"BT /F1 12 Tf 72 720 Td (Page two) Tj ET")
+
+	words, err := ExtractWords(path)
+	if err != nil {
+		t.Fatalf("ExtractWords: %v", err)
+	}
+	var got []string
+	for _, w := range words {
+		got = append(got, fmt.Sprintf("%d:%s@%.0f,%.0f", w.Page, w.Text, w.X, w.Y))
+	}
+	want := "1:Date@50,700 1:Paid@300,700 1:out@328,700 1:Balance@400,700 " +
+		"1:01/03@50,680 1:12.50@298,680 1:100.00@395,680 2:Page@72,720 2:two@105,720"
+	if strings.Join(got, " ") != want {
+		t.Errorf("words = %s\nwant    %s", strings.Join(got, " "), want)
+	}
+	if words[0].Font != "Helvetica" || words[0].FontSize != 10 {
+		t.Errorf("font = %s %.1f, want Helvetica 10", words[0].Font, words[0].FontSize)
+	}
+
+	tables := DetectTables(words, "Date", "Paid out", "Balance")
+	if len(tables) != 2 || len(tables[0].Rows) != 1 {
+		t.Fatalf("tables = %+v, want two, the first with one row", tables)
+	}
+	if cells := tables[0].Cells(tables[0].Rows[0]); cells["Paid out"] != "12.50" || cells["Balance"] != "100.00" {
+		t.Errorf("cells = %v", cells)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
@@ -0,0 +1,247 @@
+// Package merge combines consecutive statements for one account into a
+// single chronological ledger. Statements often overlap by a few days, so
+// transactions an earlier statement already has are dropped; breaks in the
+// balance from one statement to the next, and days no statement covers,
+// are reported.
+package merge
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+	"github.com/insightdelivered/bank-statement-converter/internal/validator"
+)
+
+// periodLayout is how the merged statement period is written, as the
+// parsers' date resolution reads it.
+const periodLayout = "02/01/2006"
+
+// statement is one input statement with the span of days it covers.
+type statement struct {
+	info       *models.StatementInfo
+	start, end models.Date
+}
+
+// txnKey identifies a transaction across overlapping statements.
+type txnKey struct {
+	date        string
+	typ         string
+	amount      int64
+	currency    string
+	description string
+}
+
+// Merge combines statements for the same account, in any order, into one
+// statement ordered by period. A transaction in a later statement that
+// falls within the days an earlier one covers, and matches one of its
+// transactions on date, type, amount and description, is a duplicate and
+// dropped, as are the later statement's balance rows for those days.
+//
+// Each statement's opening balance is checked against the previous
+// statement's closing balance (its balance the day before, when they
+// overlap), and days between statements are reported as gaps.
+//
+// Statement periods come from PeriodStart and PeriodEnd, or the first and
+// last transaction dates; every statement needs one or the other. The
+// merged statement takes the first statement's opening balance and the
+// last one's closing balance; printed totals are dropped, as they cover
+// only one statement each.
+func Merge(infos []*models.StatementInfo) (*models.StatementInfo, *models.MergeReport, error) {
+	if len(infos) == 0 {
+		return nil, nil, fmt.Errorf("no statements to merge")
+	}
+	stmts := make([]statement, len(infos))
+	for i, info := range infos {
+		if err := sameAccount(infos[0], info); err != nil {
+			return nil, nil, err
+		}
+		start, end := span(info)
+		if start.IsZero() {
+			return nil, nil, fmt.Errorf("statement %d (%s %s) has no statement period or dated transactions to order it by",
+				i+1, info.Bank, info.AccountNumber)
+		}
+		stmts[i] = statement{info: info, start: start, end: end}
+	}
+	sort.SliceStable(stmts, func(i, j int) bool { return stmts[i].start.Before(stmts[j].start.Time) })
+
+	first, last := stmts[0].info, stmts[len(stmts)-1].info
+	merged := &models.StatementInfo{
+		Bank:             first.Bank,
+		AccountHolder:    first.AccountHolder,
+		AccountNumber:    first.AccountNumber,
+		SortCode:         first.SortCode,
+		PeriodStart:      stmts[0].start,
+		OpeningBalance:   first.OpeningBalance,
+		ClosingBalance:   last.ClosingBalance,
+		CreditCard:       first.CreditCard,
+		StatementBalance: last.StatementBalance,
+		MinimumPayment:   last.MinimumPayment,
+		PaymentDueDate:   last.PaymentDueDate,
+	}
+	report := &models.MergeReport{Statements: len(stmts)}
+
+	// seen counts the previous statement's transactions, which the next
+	// one may repeat
+	var seen map[txnKey]int
+	var covered models.Date // last day covered so far
+	var prev statement
+	var prevOpening, prevClosing models.Money
+	prevKnown := false
+	for i, s := range stmts {
+		if merged.AccountHolder == "" {
+			merged.AccountHolder = s.info.AccountHolder
+		}
+		opening, closing, known := balances(s.info)
+		if i > 0 {
+			// Overlapping statements open part way through the previous one
+			if !s.start.After(prev.end.Time) {
+				prevClosing = balanceBefore(prev.info, prevOpening, s.start)
+			}
+			if known && prevKnown && !opening.Equal(prevClosing) {
+				report.Breaks = append(report.Breaks, models.BalanceBreak{Statement: i + 1, PreviousClosing: prevClosing, Opening: opening})
+				report.Issues = append(report.Issues, fmt.Sprintf("statement %d (%s): opening balance %s does not match the previous statement's balance %s",
+					i+1, period(s.start, s.end), opening, prevClosing))
+			}
+			if from := addDays(covered, 1); s.start.After(from.Time) {
+				to := addDays(s.start, -1)
+				days := int(to.Sub(from.Time).Hours()/24) + 1
+				report.Gaps = append(report.Gaps, models.PeriodGap{From: from, To: to, Days: days})
+				report.Issues = append(report.Issues, fmt.Sprintf("no statement covers %s (%d day(s))", period(from, to), days))
+			}
+		}
+
+		for _, txn := range s.info.Transactions {
+			overlap := i > 0 && !txn.ISODate.IsZero() && !txn.ISODate.After(covered.Time)
+			if overlap && txn.Type == "BALANCE" {
+				continue
+			}
+			key := keyOf(txn)
+			if overlap && seen[key] > 0 {
+				seen[key]--
+				report.Duplicates++
+				continue
+			}
+			merged.Transactions = append(merged.Transactions, txn)
+		}
+		seen = make(map[txnKey]int)
+		for _, txn := range s.info.Transactions {
+			if txn.Type != "BALANCE" {
+				seen[keyOf(txn)]++
+			}
+		}
+
+		if s.end.After(covered.Time) {
+			covered = s.end
+		}
+		prev, prevOpening, prevClosing, prevKnown = s, opening, closing, known
+	}
+
+	merged.PeriodEnd = covered
+	merged.StatementPeriod = merged.PeriodStart.Format(periodLayout) + " to " + merged.PeriodEnd.Format(periodLayout)
+	return merged, report, nil
+}
+
+// sameAccount checks that two statements are for the same account: the
+// same kind (bank account or card) and, where both print one, the same
+// sort code and account number.
+func sameAccount(a, b *models.StatementInfo) error {
+	if a.CreditCard != b.CreditCard {
+		return fmt.Errorf("cannot merge a credit card statement with a bank account statement")
+	}
+	keyA, keyB := accountKey(a), accountKey(b)
+	if keyA != "" && keyB != "" && keyA != keyB {
+		return fmt.Errorf("statements are for different accounts: %s and %s", describeAccount(a), describeAccount(b))
+	}
+	return nil
+}
+
+func accountKey(info *models.StatementInfo) string {
+	return models.DigitsOnly(info.SortCode) + models.DigitsOnly(info.AccountNumber)
+}
+
+func describeAccount(info *models.StatementInfo) string {
+	return strings.TrimSpace(info.SortCode + " " + info.AccountNumber)
+}
+
+// span returns the days the statement covers: its printed period, else
+// the range of its transaction dates.
+func span(info *models.StatementInfo) (start, end models.Date) {
+	if !info.PeriodStart.IsZero() && !info.PeriodEnd.IsZero() {
+		return info.PeriodStart, info.PeriodEnd
+	}
+	for _, txn := range info.Transactions {
+		if txn.ISODate.IsZero() {
+			continue
+		}
+		if start.IsZero() || txn.ISODate.Before(start.Time) {
+			start = txn.ISODate
+		}
+		if txn.ISODate.After(end.Time) {
+			end = txn.ISODate
+		}
+	}
+	return start, end
+}
+
+// balances returns the statement's opening and closing balances as the
+// validator reconciles them; known is false when the statement has no
+// balances at all.
+func balances(info *models.StatementInfo) (opening, closing models.Money, known bool) {
+	known = info.CreditCard || !info.OpeningBalance.IsZero()
+	for _, txn := range info.Transactions {
+		if txn.Type == "BALANCE" || !txn.Balance.IsZero() {
+			known = true
+		}
+	}
+	r := validator.Validate(info)
+	closing = r.ClosingBalance
+	if closing.IsZero() {
+		closing = r.ComputedClosingBalance
+	}
+	return r.OpeningBalance, closing, known
+}
+
+// balanceBefore returns the statement's balance at the end of the day
+// before day, walking its transactions from the opening balance.
+func balanceBefore(info *models.StatementInfo, opening models.Money, day models.Date) models.Money {
+	balance := opening
+	for _, txn := range info.Transactions {
+		if !txn.ISODate.IsZero() && !txn.ISODate.Before(day.Time) {
+			break
+		}
+		switch {
+		case txn.Type == "BALANCE" || !txn.Balance.IsZero():
+			balance = txn.Balance
+		case txn.Type == "DEBIT" && !info.CreditCard, txn.Type != "DEBIT" && info.CreditCard:
+			// Card balances are amounts owed: charges increase them
+			balance = balance.Sub(txn.Amount)
+		default:
+			balance = balance.Add(txn.Amount)
+		}
+	}
+	return balance
+}
+
+func keyOf(txn models.Transaction) txnKey {
+	return txnKey{
+		date:        txn.ISODate.String(),
+		typ:         txn.Type,
+		amount:      txn.Amount.Minor,
+		currency:    txn.Currency,
+		description: strings.ToUpper(strings.Join(strings.Fields(txn.Description), " ")),
+	}
+}
+
+func period(from, to models.Date) string {
+	if from.Equal(to.Time) {
+		return from.String()
+	}
+	return from.String() + " to " + to.String()
+}
+
+func addDays(d models.Date, n int) models.Date {
+	t := d.AddDate(0, 0, n)
+	return models.NewDate(t.Year(), t.Month(), t.Day())
+}
@@ -0,0 +1,149 @@
+package merge
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+	"github.com/insightdelivered/bank-statement-converter/internal/validator"
+)
+
+func money(s string) models.Money {
+	m, err := models.ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func txn(day int, desc, typ, amount, balance string) models.Transaction {
+	t := models.Transaction{ISODate: models.NewDate(2024, time.January, day), Description: desc, Type: typ, Balance: money(balance)}
+	if amount != "" {
+		t.Amount = money(amount)
+	}
+	t.Date = t.ISODate.String()
+	return t
+}
+
+// statements returns January in two overlapping statements: 1-20 Jan and
+// 15 Jan-10 Feb, both listing the 18 Jan transactions.
+func statements() []*models.StatementInfo {
+	first := &models.StatementInfo{
+		Bank:           models.BankHSBC,
+		SortCode:       "40-12-34",
+		AccountNumber:  "12345678",
+		AccountHolder:  "MR J SMITH",
+		PeriodStart:    models.NewDate(2024, 1, 1),
+		PeriodEnd:      models.NewDate(2024, 1, 20),
+		OpeningBalance: money("1000.00"),
+		ClosingBalance: money("1455.00"),
+		Transactions: []models.Transaction{
+			txn(1, "BALANCE BROUGHT FORWARD", "BALANCE", "", "1000.00"),
+			txn(3, "TESCO", "DEBIT", "25.00", "975.00"),
+			txn(18, "COSTA", "DEBIT", "20.00", "955.00"),
+			txn(18, "SALARY", "CREDIT", "500.00", "1455.00"),
+		},
+	}
+	second := &models.StatementInfo{
+		Bank:           models.BankHSBC,
+		SortCode:       "401234",
+		AccountNumber:  "12345678",
+		PeriodStart:    models.NewDate(2024, 1, 15),
+		PeriodEnd:      models.NewDate(2024, 2, 10),
+		OpeningBalance: money("975.00"),
+		ClosingBalance: money("1355.00"),
+		Transactions: []models.Transaction{
+			txn(15, "BALANCE BROUGHT FORWARD", "BALANCE", "", "975.00"),
+			txn(18, "Costa", "DEBIT", "20.00", "955.00"),
+			txn(18, "SALARY", "CREDIT", "500.00", "1455.00"),
+			txn(25, "RENT", "DEBIT", "100.00", "1355.00"),
+		},
+	}
+	return []*models.StatementInfo{first, second}
+}
+
+func TestMerge_Overlap(t *testing.T) {
+	infos := statements()
+	// Input order does not matter
+	merged, report, err := Merge([]*models.StatementInfo{infos[1], infos[0]})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var descs []string
+	for _, txn := range merged.Transactions {
+		descs = append(descs, txn.Description)
+	}
+	want := "BALANCE BROUGHT FORWARD,TESCO,COSTA,SALARY,RENT"
+	if got := strings.Join(descs, ","); got != want {
+		t.Errorf("transactions: got %s, want %s", got, want)
+	}
+	if report.Statements != 2 || report.Duplicates != 2 {
+		t.Errorf("report: got %d statements, %d duplicates; want 2, 2", report.Statements, report.Duplicates)
+	}
+	if len(report.Issues) != 0 {
+		t.Errorf("unexpected issues: %v", report.Issues)
+	}
+	if merged.StatementPeriod != "01/01/2024 to 10/02/2024" || merged.AccountHolder != "MR J SMITH" {
+		t.Errorf("merged: period %q, holder %q", merged.StatementPeriod, merged.AccountHolder)
+	}
+	if !merged.OpeningBalance.Equal(money("1000.00")) || !merged.ClosingBalance.Equal(money("1355.00")) {
+		t.Errorf("balances: got %s to %s", merged.OpeningBalance, merged.ClosingBalance)
+	}
+	if r := validator.Validate(merged); !r.Valid {
+		t.Errorf("merged statement should reconcile: %v", r.Issues)
+	}
+}
+
+func TestMerge_BreaksAndGaps(t *testing.T) {
+	infos := statements()
+	second := infos[1]
+	second.PeriodStart = models.NewDate(2024, 1, 25)
+	second.OpeningBalance = money("1400.00")
+	second.Transactions = []models.Transaction{
+		txn(25, "BALANCE BROUGHT FORWARD", "BALANCE", "", "1400.00"),
+		txn(26, "RENT", "DEBIT", "100.00", "1300.00"),
+	}
+	second.ClosingBalance = money("1300.00")
+
+	_, report, err := Merge(infos)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Breaks) != 1 || report.Breaks[0].Statement != 2 ||
+		!report.Breaks[0].PreviousClosing.Equal(money("1455.00")) || !report.Breaks[0].Opening.Equal(money("1400.00")) {
+		t.Errorf("breaks: got %+v", report.Breaks)
+	}
+	if len(report.Gaps) != 1 || report.Gaps[0].From.String() != "2024-01-21" || report.Gaps[0].To.String() != "2024-01-24" || report.Gaps[0].Days != 4 {
+		t.Errorf("gaps: got %+v", report.Gaps)
+	}
+	if len(report.Issues) != 2 {
+		t.Errorf("issues: got %v", report.Issues)
+	}
+}
+
+func TestMerge_DifferentAccounts(t *testing.T) {
+	infos := statements()
+	infos[1].AccountNumber = "87654321"
+	if _, _, err := Merge(infos); err == nil || !strings.Contains(err.Error(), "different accounts") {
+		t.Errorf("expected a different accounts error, got %v", err)
+	}
+
+	infos = statements()
+	infos[1].CreditCard = true
+	if _, _, err := Merge(infos); err == nil {
+		t.Error("expected an error merging a card statement with a bank statement")
+	}
+}
+
+func TestMerge_UndatedStatement(t *testing.T) {
+	infos := statements()
+	infos[1].PeriodStart, infos[1].PeriodEnd = models.Date{}, models.Date{}
+	for i := range infos[1].Transactions {
+		infos[1].Transactions[i].ISODate = models.Date{}
+	}
+	if _, _, err := Merge(infos); err == nil {
+		t.Error("expected an error for a statement without dates")
+	}
+}
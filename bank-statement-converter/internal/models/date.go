@@ -0,0 +1,54 @@
+package models
+
+import (
+	"strconv"
+	"time"
+)
+
+// ISODateLayout is the layout of Date in JSON.
+const ISODateLayout = "2006-01-02"
+
+// Date is a calendar date resolved from a statement's raw date text. The
+// zero Date means the date could not be resolved; it is omitted from JSON
+// (via omitzero) and marshals as "YYYY-MM-DD" otherwise.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the Date for the given day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// String returns the date as YYYY-MM-DD, or "" for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(ISODateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" || s == `""` {
+		*d = Date{}
+		return nil
+	}
+	s, err := strconv.Unquote(s)
+	if err != nil {
+		return err
+	}
+	t, err := time.Parse(ISODateLayout, s)
+	if err != nil {
+		return err
+	}
+	*d = Date{t}
+	return nil
+}
@@ -0,0 +1,169 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount of money: an integer number of minor units
+// (pence, cents) and an ISO 4217 currency code. An empty currency means the
+// statement's currency, GBP unless the transaction says otherwise.
+//
+// Every supported currency has two decimal places. Arithmetic keeps the
+// receiver's currency (or the operand's, when the receiver has none);
+// amounts on one statement line never mix currencies.
+//
+// In JSON a Money is a decimal number with two places ("25.99"), as the
+// float64 amounts it replaces were; strings ("25.99") are also accepted.
+type Money struct {
+	Minor    int64
+	Currency string
+}
+
+// minorPerMajor is the number of minor units in one major unit.
+const minorPerMajor = 100
+
+// Pence returns an amount of minor units in the statement's currency.
+func Pence(minor int64) Money {
+	return Money{Minor: minor}
+}
+
+// MoneyFromFloat rounds f to the nearest minor unit.
+func MoneyFromFloat(f float64) Money {
+	return Money{Minor: int64(math.Round(f * minorPerMajor))}
+}
+
+// ParseMoney parses a plain decimal amount such as "1234.56", "-4.5" or
+// "1,234". Currency symbols and other formatting are the caller's concern.
+func ParseMoney(s string) (Money, error) {
+	orig := s
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", orig)
+	}
+	if len(frac) > 2 {
+		return Money{}, fmt.Errorf("invalid amount %q: more than two decimal places", orig)
+	}
+	if hasFrac && frac == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", orig)
+	}
+	frac += strings.Repeat("0", 2-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+	for _, r := range whole + frac {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("invalid amount %q", orig)
+		}
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/minorPerMajor-1 {
+		return Money{}, fmt.Errorf("invalid amount %q", orig)
+	}
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+	minor := units*minorPerMajor + cents
+	if neg {
+		minor = -minor
+	}
+	return Money{Minor: minor}, nil
+}
+
+// In returns m tagged with currency.
+func (m Money) In(currency string) Money {
+	m.Currency = currency
+	return m
+}
+
+func (m Money) Add(o Money) Money {
+	return Money{Minor: m.Minor + o.Minor, Currency: m.currencyWith(o)}
+}
+
+func (m Money) Sub(o Money) Money {
+	return Money{Minor: m.Minor - o.Minor, Currency: m.currencyWith(o)}
+}
+
+func (m Money) Neg() Money {
+	m.Minor = -m.Minor
+	return m
+}
+
+func (m Money) Abs() Money {
+	if m.Minor < 0 {
+		m.Minor = -m.Minor
+	}
+	return m
+}
+
+func (m Money) currencyWith(o Money) string {
+	if m.Currency != "" {
+		return m.Currency
+	}
+	return o.Currency
+}
+
+// Equal reports whether m and o are the same number of minor units.
+// Currencies are not compared.
+func (m Money) Equal(o Money) bool {
+	return m.Minor == o.Minor
+}
+
+func (m Money) IsZero() bool     { return m.Minor == 0 }
+func (m Money) IsNegative() bool { return m.Minor < 0 }
+func (m Money) IsPositive() bool { return m.Minor > 0 }
+
+// Float64 returns m in major units, for display and ratios only.
+func (m Money) Float64() float64 {
+	return float64(m.Minor) / minorPerMajor
+}
+
+// String returns m as a plain decimal with two places: "1234.56", "-4.50".
+func (m Money) String() string {
+	minor := m.Minor
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/minorPerMajor, minor%minorPerMajor)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a decimal string. Numbers with
+// more than two places (float noise from other encoders) are rounded.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if ferr != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return err
+		}
+		parsed = MoneyFromFloat(f)
+	}
+	m.Minor = parsed.Minor
+	return nil
+}
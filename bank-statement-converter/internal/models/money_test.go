@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int64
+		wantErr bool
+	}{
+		{"25.99", 2599, false},
+		{"1,234.56", 123456, false},
+		{"-4.5", -450, false},
+		{"+2500", 250000, false},
+		{".99", 99, false},
+		{"0.00", 0, false},
+		{"12.345", 0, true},
+		{"12.", 0, true},
+		{"1.2.3", 0, true},
+		{"£25.99", 0, true},
+		{"", 0, true},
+		{"-", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Minor != tt.want {
+				t.Errorf("got %d, want %d", got.Minor, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	// 0.1 + 0.2 is the classic float64 failure; in pence it is exact
+	sum := Pence(10).Add(Pence(20))
+	if !sum.Equal(Pence(30)) || sum.String() != "0.30" {
+		t.Errorf("0.10 + 0.20: got %s", sum)
+	}
+
+	balance := Pence(100000)
+	for i := 0; i < 1000; i++ {
+		balance = balance.Sub(Pence(1))
+	}
+	if balance.String() != "990.00" {
+		t.Errorf("1000.00 - 1000 × 0.01: got %s", balance)
+	}
+
+	if got := Pence(-450).Abs().String(); got != "4.50" {
+		t.Errorf("Abs: got %s", got)
+	}
+	if got := Pence(-5).String(); got != "-0.05" {
+		t.Errorf("String: got %s", got)
+	}
+	if got := Pence(5).In("EUR").Add(Pence(5)); got.Currency != "EUR" {
+		t.Errorf("Add: currency %q, want EUR", got.Currency)
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(Transaction{Amount: Pence(2599), Balance: Pence(-1000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if raw["amount"] != 25.99 || raw["balance"] != -10.0 {
+		t.Errorf("amounts should marshal as numbers: %s", data)
+	}
+
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{`25.99`, 2599},
+		{`"25.99"`, 2599},
+		{`-10`, -1000},
+		{`0.30000000000000004`, 30},
+		{`1e3`, 100000},
+	}
+	for _, tt := range tests {
+		var m Money
+		if err := json.Unmarshal([]byte(tt.input), &m); err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if m.Minor != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.input, m.Minor, tt.want)
+		}
+	}
+
+	var m Money
+	if err := json.Unmarshal([]byte(`"abc"`), &m); err == nil {
+		t.Error("expected error for a non-numeric string")
+	}
+}
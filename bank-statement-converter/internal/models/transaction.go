@@ -1,22 +1,53 @@
 package models
 
+import "strings"
+
 // Transaction represents a single bank statement transaction.
 type Transaction struct {
-	Date        string  `json:"date"`
-	Description string  `json:"description"`
-	Type        string  `json:"type"` // DEBIT or CREDIT
-	Amount      float64 `json:"amount"`
-	Balance     float64 `json:"balance"`
-	ParseMethod string  `json:"parseMethod,omitempty"` // debug: which parser method matched
+	Date        string `json:"date"`
+	PostingDate string `json:"postingDate,omitempty"` // credit cards: date the bank processed it
+	Description string `json:"description"`
+	Type        string `json:"type"` // DEBIT or CREDIT
+	Amount      Money  `json:"amount"`
+	Balance     Money  `json:"balance"`
+	Currency    string `json:"currency,omitempty"`    // ISO 4217 code; empty means GBP
+	ParseMethod string `json:"parseMethod,omitempty"` // debug: which parser method matched
+
+	// ISODate and ISOPostingDate are Date and PostingDate resolved to
+	// calendar dates, with the year inferred from the statement period when
+	// the bank prints none. Zero when the date text is not recognised.
+	ISODate        Date `json:"isoDate,omitzero"`
+	ISOPostingDate Date `json:"isoPostingDate,omitzero"`
+}
+
+// SignedAmount returns the amount, negative for money out. On credit card
+// statements money out is a charge.
+func (t Transaction) SignedAmount() Money {
+	if t.Type == "DEBIT" {
+		return t.Amount.Neg()
+	}
+	return t.Amount
 }
 
 // BankType represents supported bank statement formats.
 type BankType string
 
 const (
-	BankMetro    BankType = "metro"
-	BankHSBC     BankType = "hsbc"
-	BankBarclays BankType = "barclays"
+	BankMetro      BankType = "metro"
+	BankHSBC       BankType = "hsbc"
+	BankBarclays   BankType = "barclays"
+	BankLloyds     BankType = "lloyds"
+	BankNatWest    BankType = "natwest"
+	BankRBS        BankType = "rbs"
+	BankSantander  BankType = "santander"
+	BankNationwide BankType = "nationwide"
+	BankMonzo      BankType = "monzo"
+	BankStarling   BankType = "starling"
+	BankRevolut    BankType = "revolut"
+
+	// Credit card issuers
+	BankAmex        BankType = "amex"
+	BankBarclaycard BankType = "barclaycard"
 )
 
 // DebugLine captures what the parser did with each input line.
@@ -30,14 +61,124 @@ type DebugLine struct {
 	TabParts int    `json:"tabParts,omitempty"`
 }
 
+// BankCandidate is one entry in a ranked bank detection result.
+type BankCandidate struct {
+	Bank       BankType `json:"bank"`
+	Name       string   `json:"name"`
+	Score      float64  `json:"score"`      // sum of evidence weights
+	Confidence float64  `json:"confidence"` // 0-1, derived from Score
+	Reasons    []string `json:"reasons,omitempty"`
+
+	// Set when the candidate's parser was dry-run against the statement
+	DryRun       bool `json:"dryRun,omitempty"`
+	Transactions int  `json:"transactions,omitempty"`
+	Reconciled   bool `json:"reconciled,omitempty"`
+}
+
 // StatementInfo holds metadata extracted from the statement.
+//
+// For credit card statements (CreditCard set) transactions carry no running
+// balance, OpeningBalance and ClosingBalance are the previous and new amounts
+// owed, and DEBIT means a charge to the card.
 type StatementInfo struct {
 	Bank            BankType
 	AccountHolder   string
 	AccountNumber   string
 	SortCode        string
 	StatementPeriod string
-	OpeningBalance  float64
-	Transactions    []Transaction
-	DebugLines      []DebugLine
+	// PeriodStart and PeriodEnd are StatementPeriod resolved to calendar
+	// dates; zero when no period is printed or it is not recognised.
+	PeriodStart    Date
+	PeriodEnd      Date
+	OpeningBalance Money
+	ClosingBalance Money
+	Transactions   []Transaction
+	DebugLines     []DebugLine
+
+	// Totals printed on the statement ("Total payments", "Total receipts");
+	// zero when the statement does not print them.
+	PrintedTotalDebit  Money
+	PrintedTotalCredit Money
+
+	// Credit card statement fields
+	CreditCard       bool
+	StatementBalance Money
+	MinimumPayment   Money
+	PaymentDueDate   string
+}
+
+// DigitsOnly strips separators such as the dashes in a sort code or the
+// spaces in an account number.
+func DigitsOnly(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// ValidationReport is the result of reconciling a statement's transactions
+// against its running balances, closing balance and printed totals.
+type ValidationReport struct {
+	Valid bool `json:"valid"`
+	// Checked is the number of printed balances that could be checked
+	// against the previous balance; Mismatches lists those that failed.
+	Checked    int               `json:"checked"`
+	Mismatches []BalanceMismatch `json:"mismatches,omitempty"`
+
+	OpeningBalance         Money `json:"openingBalance"`
+	ComputedClosingBalance Money `json:"computedClosingBalance"`
+	ClosingBalance         Money `json:"closingBalance,omitzero"` // as printed
+	TotalDebit             Money `json:"totalDebit"`
+	TotalCredit            Money `json:"totalCredit"`
+	PrintedTotalDebit      Money `json:"printedTotalDebit,omitzero"`
+	PrintedTotalCredit     Money `json:"printedTotalCredit,omitzero"`
+
+	// Issues describes every failed check in plain English.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// BalanceMismatch is a transaction whose printed balance is not the previous
+// balance plus or minus its amount.
+type BalanceMismatch struct {
+	Index           int    `json:"index"` // position in Transactions
+	Date            string `json:"date"`
+	Description     string `json:"description"`
+	Type            string `json:"type"`
+	Amount          Money  `json:"amount"`
+	PreviousBalance Money  `json:"previousBalance"`
+	ExpectedBalance Money  `json:"expectedBalance"`
+	Balance         Money  `json:"balance"`
+}
+
+// MergeReport is the result of merging consecutive statements for one
+// account into a single ledger.
+type MergeReport struct {
+	Statements int `json:"statements"`
+	// Duplicates is the number of transactions dropped because an earlier
+	// statement, overlapping the same days, already had them.
+	Duplicates int            `json:"duplicates"`
+	Breaks     []BalanceBreak `json:"breaks,omitempty"`
+	Gaps       []PeriodGap    `json:"gaps,omitempty"`
+
+	// Issues describes every break and gap in plain English.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// BalanceBreak is a statement whose opening balance is not the previous
+// statement's closing balance (or, when they overlap, its balance the day
+// before).
+type BalanceBreak struct {
+	Statement       int   `json:"statement"` // position in the merged order
+	PreviousClosing Money `json:"previousClosing"`
+	Opening         Money `json:"opening"`
+}
+
+// PeriodGap is a run of days between two consecutive statements that
+// neither covers.
+type PeriodGap struct {
+	From Date `json:"from"`
+	To   Date `json:"to"`
+	Days int  `json:"days"`
 }
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// AmexParser handles American Express UK credit card statement PDFs.
+//
+// Amex statements have this layout:
+//
+//	Transaction Date | Process Date | Transaction Details | Amount £
+//
+// Date format: Mon DD (e.g., Jan 02), with no year. Payments and refunds
+// carry a "CR" suffix. Foreign spending adds a detail line with the original
+// currency amount and rate, which is kept as part of the description.
+// Example line: "Jan 02 Jan 03 TESCO STORES LONDON 25.99"
+type AmexParser struct{}
+
+func (p *AmexParser) BankName() string {
+	return "American Express"
+}
+
+func (p *AmexParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankAmex,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findMaskedCardNumber(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Card Member", "Cardmember", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+	extractCreditCardSummary(allText, info)
+
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		info.Transactions = append(info.Transactions, parseCreditCardLines(lines, isAmexFooter)...)
+	}
+
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// isAmexFooter detects footer/boilerplate lines in Amex statements.
+func isAmexFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"american express services europe", "american express payment services",
+		"registered office", "registered in england",
+		"financial conduct authority", "authorised by",
+		"americanexpress.co.uk", "belgrave house",
+		"membership rewards",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
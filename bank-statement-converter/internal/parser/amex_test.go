@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestAmexParser_Parse(t *testing.T) {
+	p := &AmexParser{}
+
+	pages := []string{
+		`American Express
+Platinum Cashback Everyday Credit Card
+Card Member MR JOHN SMITH
+Card Number xxxx-xxxxxx-51005
+Previous Balance £500.00
+New Balance £1,134.48
+Minimum Payment £25.00
+Payment Due 25 February 2024
+Transaction Date Process Date Transaction Details Amount £
+Jan 02 Jan 03 TESCO STORES LONDON 25.99
+Jan 05 Jan 06 CAFE DE FLORE PARIS 26.01
+Foreign Spending 30.00 EUR Rate 1.1534
+Jan 15 Jan 15 PAYMENT RECEIVED - THANK YOU 500.00 CR
+Jan 20 Jan 21 TOTAL FITNESS GYM 1,082.48
+Total new transactions for MR JOHN SMITH 1,134.48
+American Express Services Europe Limited has its registered office at Belgrave House`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankAmex {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankAmex)
+	}
+	if !info.CreditCard {
+		t.Error("expected CreditCard to be set")
+	}
+	if info.AccountNumber != "xxxx-xxxxxx-51005" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "xxxx-xxxxxx-51005")
+	}
+	if info.OpeningBalance.Float64() != 500.00 {
+		t.Errorf("previous balance: got %.2f, want 500.00", info.OpeningBalance.Float64())
+	}
+	if info.StatementBalance.Float64() != 1134.48 {
+		t.Errorf("statement balance: got %.2f, want 1134.48", info.StatementBalance.Float64())
+	}
+	if info.MinimumPayment.Float64() != 25.00 {
+		t.Errorf("minimum payment: got %.2f, want 25.00", info.MinimumPayment.Float64())
+	}
+	if info.PaymentDueDate != "25 February 2024" {
+		t.Errorf("payment due date: got %q, want %q", info.PaymentDueDate, "25 February 2024")
+	}
+
+	if len(info.Transactions) != 4 {
+		t.Fatalf("transactions: got %d, want 4; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx         int
+		date        string
+		postingDate string
+		typ         string
+		amount      float64
+	}{
+		{0, "Jan 02", "Jan 03", "DEBIT", 25.99},
+		{1, "Jan 05", "Jan 06", "DEBIT", 26.01},
+		{2, "Jan 15", "Jan 15", "CREDIT", 500.00},
+		{3, "Jan 20", "Jan 21", "DEBIT", 1082.48},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Date != tt.date {
+			t.Errorf("txn[%d].Date: got %q, want %q", tt.idx, txn.Date, tt.date)
+		}
+		if txn.PostingDate != tt.postingDate {
+			t.Errorf("txn[%d].PostingDate: got %q, want %q", tt.idx, txn.PostingDate, tt.postingDate)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if !txn.Balance.IsZero() {
+			t.Errorf("txn[%d].Balance: got %.2f, want 0", tt.idx, txn.Balance.Float64())
+		}
+	}
+
+	if !strings.Contains(info.Transactions[1].Description, "30.00 EUR") {
+		t.Errorf("txn[1] should include the foreign spending detail: got %q", info.Transactions[1].Description)
+	}
+	if strings.Contains(info.Transactions[3].Description, "Total") {
+		t.Errorf("txn[3] should not absorb the totals row: got %q", info.Transactions[3].Description)
+	}
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// BarclaycardParser handles Barclaycard credit card statement PDFs.
+//
+// Barclaycard statements have this layout:
+//
+//	Transaction date | Posting date | Description | Amount
+//
+// Date format: DD Mon, with no year; the posting date column is missing on
+// some statements. Payments are shown with a "CR" suffix or a leading minus.
+// Example line: "02 Jan 03 Jan TESCO STORES LONDON £25.99"
+type BarclaycardParser struct{}
+
+func (p *BarclaycardParser) BankName() string {
+	return "Barclaycard"
+}
+
+func (p *BarclaycardParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankBarclaycard,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findMaskedCardNumber(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+	extractCreditCardSummary(allText, info)
+
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		info.Transactions = append(info.Transactions, parseCreditCardLines(lines, isBarclaycardFooter)...)
+	}
+
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// isBarclaycardFooter detects footer/boilerplate lines in Barclaycard statements.
+func isBarclaycardFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"barclaycard is a trading name", "barclays bank uk plc",
+		"barclays bank plc", "registered office", "registered in england",
+		"financial conduct authority", "prudential regulation",
+		"authorised by", "barclaycard.co.uk", "churchill place",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
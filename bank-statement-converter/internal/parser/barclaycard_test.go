@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestBarclaycardParser_Parse(t *testing.T) {
+	p := &BarclaycardParser{}
+
+	pages := []string{
+		`Barclaycard Platinum
+Account number **** **** **** 1234
+Previous balance £250.00
+Statement balance £318.49
+Minimum payment £5.00
+Payment due date 14/02/2024
+Transaction date Posting date Description Amount
+02 Jan 03 Jan AMAZON.CO.UK £68.49
+London
+10 Jan PAYMENT THANK YOU -£250.00
+18 Jan 19 Jan NETFLIX.COM £250.00
+21 Jan 21 Jan REFUND NETFLIX.COM £250.00 CR
+22 Jan 23 Jan SHELL FUEL £250.00
+Barclaycard is a trading name of Barclays Bank UK PLC`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankBarclaycard {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankBarclaycard)
+	}
+	if !info.CreditCard {
+		t.Error("expected CreditCard to be set")
+	}
+	if info.AccountNumber != "**** **** **** 1234" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "**** **** **** 1234")
+	}
+	if info.StatementBalance.Float64() != 318.49 || info.ClosingBalance.Float64() != 318.49 {
+		t.Errorf("statement balance: got %.2f/%.2f, want 318.49", info.StatementBalance.Float64(), info.ClosingBalance.Float64())
+	}
+	if info.PaymentDueDate != "14/02/2024" {
+		t.Errorf("payment due date: got %q, want %q", info.PaymentDueDate, "14/02/2024")
+	}
+
+	if len(info.Transactions) != 5 {
+		t.Fatalf("transactions: got %d, want 5; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx         int
+		postingDate string
+		typ         string
+		amount      float64
+	}{
+		{0, "03 Jan", "DEBIT", 68.49},
+		// No posting date column; leading minus marks a payment
+		{1, "", "CREDIT", 250.00},
+		{2, "19 Jan", "DEBIT", 250.00},
+		{3, "21 Jan", "CREDIT", 250.00},
+		{4, "23 Jan", "DEBIT", 250.00},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.PostingDate != tt.postingDate {
+			t.Errorf("txn[%d].PostingDate: got %q, want %q", tt.idx, txn.PostingDate, tt.postingDate)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+	}
+
+	if got := info.Transactions[0].Description; got != "AMAZON.CO.UK London" {
+		t.Errorf("txn[0].Description: got %q", got)
+	}
+}
@@ -82,15 +82,15 @@ func (p *BarclaysParser) Parse(pages []string) (*models.StatementInfo, error) {
 		lines := strings.Split(page, "\n")
 		var txns []models.Transaction
 		if arrowFormat {
-			var openBal float64
+			var openBal models.Money
 			txns, openBal = p.parseLinesArrow(lines)
-			if info.OpeningBalance == 0 && openBal != 0 {
+			if info.OpeningBalance.IsZero() && !openBal.IsZero() {
 				info.OpeningBalance = openBal
 			}
 		} else if sharedDateFormat {
-			var openBal float64
+			var openBal models.Money
 			txns, openBal = p.parseLinesSharedDate(lines)
-			if info.OpeningBalance == 0 && openBal != 0 {
+			if info.OpeningBalance.IsZero() && !openBal.IsZero() {
 				info.OpeningBalance = openBal
 			}
 		} else {
@@ -99,6 +99,9 @@ func (p *BarclaysParser) Parse(pages []string) (*models.StatementInfo, error) {
 		info.Transactions = append(info.Transactions, txns...)
 	}
 
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
 	return info, nil
 }
 
@@ -112,9 +115,9 @@ func (p *BarclaysParser) Parse(pages []string) (*models.StatementInfo, error) {
 //	"5 Dec → Direct Debit to Stripe → 58.80 → 9,397.88"
 //	"Direct Credit From Antalis Limited → 10,500.00 19,749.38"
 //	"Ref: Antalis Limited" (continuation)
-func (p *BarclaysParser) parseLinesArrow(lines []string) ([]models.Transaction, float64) {
+func (p *BarclaysParser) parseLinesArrow(lines []string) ([]models.Transaction, models.Money) {
 	var transactions []models.Transaction
-	var openingBalance float64
+	var openingBalance models.Money
 	inTransactionSection := false
 	currentDate := ""
 
@@ -149,7 +152,7 @@ func (p *BarclaysParser) parseLinesArrow(lines []string) ([]models.Transaction,
 				inTransactionSection = true
 			}
 			// Extract opening balance amount
-			if isOpeningBalanceLine(line) && openingBalance == 0 {
+			if isOpeningBalanceLine(line) && openingBalance.IsZero() {
 				if amounts := amountPattern.FindAllString(line, -1); len(amounts) > 0 {
 					if bal, err := parseAmount(amounts[len(amounts)-1]); err == nil {
 						openingBalance = bal
@@ -166,7 +169,6 @@ func (p *BarclaysParser) parseLinesArrow(lines []string) ([]models.Transaction,
 							Date:        currentDate,
 							Description: balDesc,
 							Type:        "BALANCE",
-							Amount:      0,
 							Balance:     bal,
 						})
 					}
@@ -313,7 +315,7 @@ func parseBarclaysArrowTransaction(parts []string, shortDate, currentDate string
 	}
 
 	// Collect all amounts from the column parts (everything after description)
-	var amounts []float64
+	var amounts []models.Money
 	for _, part := range parts[1:] {
 		part = strings.TrimSpace(part)
 		if part == "" {
@@ -322,7 +324,7 @@ func parseBarclaysArrowTransaction(parts []string, shortDate, currentDate string
 		for _, f := range strings.Fields(part) {
 			if amountPattern.MatchString(f) {
 				a, err := parseAmount(f)
-				if err == nil && a > 0 {
+				if err == nil && a.IsPositive() {
 					amounts = append(amounts, a)
 				}
 			}
@@ -537,157 +539,28 @@ func extractBalanceDescription(line string) string {
 // Dates are "DD Mon" (no year) and appear once per date group — subsequent
 // transactions under the same date have no date prefix.
 
-func (p *BarclaysParser) parseLinesSharedDate(lines []string) ([]models.Transaction, float64) {
-	var transactions []models.Transaction
-	var openingBalance float64
-	inTransactionSection := false
-	currentDate := ""
-
-	for i := 0; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		if containsBarclaysHeader(line) {
-			inTransactionSection = true
-			continue
-		}
-
-		if isBarclaysFooter(line) || isBarclaysSkipLine(line) {
-			continue
-		}
-
-		if isSummaryLine(line) {
-			continue
-		}
-
-		if isBarclaysFXDetailLine(line) {
-			if len(transactions) > 0 {
-				last := &transactions[len(transactions)-1]
-				last.Description += " " + line
-			}
-			continue
-		}
-
-		// Check for short date at start
-		shortDate := extractShortDate(line)
-		if shortDate != "" {
-			currentDate = shortDate
-			inTransactionSection = true
-		}
-
-		// Also check for full date formats
-		if shortDate == "" && startsWithDate(line) {
-			currentDate = extractDate(line)
-			inTransactionSection = true
-		}
-
-		if !inTransactionSection {
-			continue
-		}
-
-		// Try to parse as a transaction (line has trailing amounts)
-		txn := p.parseSharedDateTransactionLine(line, shortDate, currentDate)
-		if txn != nil {
-			// Capture opening balance
-			if txn.Type == "BALANCE" && isOpeningBalanceLine(txn.Description) && openingBalance == 0 {
-				openingBalance = txn.Balance
-			}
-			transactions = append(transactions, *txn)
-			// "Balance carried forward" is always the last item on a statement.
-			if txn.Type == "BALANCE" && isClosingBalanceLine(txn.Description) {
-				return transactions, openingBalance
-			}
-			continue
-		}
-
-		// No amounts found — continuation line.
-		// Never append to BALANCE transactions — their descriptions are
-		// always single-line ("Start Balance", "Balance carried forward").
-		if len(transactions) > 0 {
-			last := &transactions[len(transactions)-1]
-			if last.Type == "BALANCE" {
-				continue
-			}
-			cleanLine := line
-			if shortDate != "" {
-				cleanLine = strings.TrimSpace(strings.TrimPrefix(cleanLine, shortDate))
-			}
-			if cleanLine != "" && !isBarclaysFooter(cleanLine) {
-				last.Description += " " + cleanLine
-			}
-		}
-	}
-
-	return transactions, openingBalance
+func (p *BarclaysParser) parseLinesSharedDate(lines []string) ([]models.Transaction, models.Money) {
+	return parseSharedDateLines(lines, barclaysSharedDateLayout)
 }
 
-// parseSharedDateTransactionLine parses a space/tab-separated line as a
-// transaction.  Returns nil if the line has no monetary amounts.
-func (p *BarclaysParser) parseSharedDateTransactionLine(line, shortDate, currentDate string) *models.Transaction {
-	rest := line
-	if shortDate != "" {
-		idx := strings.Index(rest, shortDate)
-		if idx >= 0 {
-			rest = strings.TrimSpace(rest[idx+len(shortDate):])
+var barclaysSharedDateLayout = sharedDateLayout{
+	isHeader: containsBarclaysHeader,
+	isSkip: func(line string) bool {
+		return isBarclaysFooter(line) || isBarclaysSkipLine(line) || isSummaryLine(line)
+	},
+	isDetail:  isBarclaysFXDetailLine,
+	isBalance: isBalanceLine,
+	isOpening: isOpeningBalanceLine,
+	isClosing: isClosingBalanceLine,
+	classify: func(desc string, amount, balance, prevBalance models.Money) string {
+		if isDebitDescription(desc) {
+			return "DEBIT"
+		}
+		if isCreditDescription(desc) {
+			return "CREDIT"
 		}
-	}
-
-	if rest == "" {
-		return nil
-	}
-
-	allLocs := amountPattern.FindAllStringIndex(rest, -1)
-	if len(allLocs) == 0 {
-		return nil
-	}
-
-	desc := strings.TrimSpace(rest[:allLocs[0][0]])
-	if desc == "" {
-		return nil
-	}
-
-	var amounts []float64
-	for _, loc := range allLocs {
-		a, err := parseAmount(rest[loc[0]:loc[1]])
-		if err == nil {
-			amounts = append(amounts, a)
-		}
-	}
-
-	if len(amounts) == 0 {
-		return nil
-	}
-
-	txn := &models.Transaction{
-		Date:        currentDate,
-		Description: cleanDescription(desc),
-	}
-
-	if isBalanceLine(desc) {
-		txn.Balance = amounts[len(amounts)-1]
-		txn.Amount = 0
-		txn.Type = "BALANCE"
-		return txn
-	}
-
-	if len(amounts) >= 2 {
-		txn.Amount = amounts[0]
-		txn.Balance = amounts[len(amounts)-1]
-	} else {
-		txn.Amount = amounts[0]
-	}
-
-	if isDebitDescription(desc) {
-		txn.Type = "DEBIT"
-	} else if isCreditDescription(desc) {
-		txn.Type = "CREDIT"
-	} else {
-		txn.Type = "DEBIT"
-	}
-
-	return txn
+		return "DEBIT"
+	},
 }
 
 // hasShortDatesOnly detects the shared-date business format by checking
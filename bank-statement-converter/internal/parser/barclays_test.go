@@ -41,7 +41,7 @@ Date Description Money out Money in Balance
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] %s | %s | %s | %.2f | %.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 }
 
@@ -115,15 +115,15 @@ Ref: Antalis Limited`,
 	}
 
 	// Verify opening balance is captured
-	if info.OpeningBalance != 9856.68 {
-		t.Errorf("opening balance: got %.2f, want 9856.68", info.OpeningBalance)
+	if info.OpeningBalance.Float64() != 9856.68 {
+		t.Errorf("opening balance: got %.2f, want 9856.68", info.OpeningBalance.Float64())
 	}
 
-	t.Logf("opening balance: %.2f", info.OpeningBalance)
+	t.Logf("opening balance: %.2f", info.OpeningBalance.Float64())
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	if len(info.Transactions) < 5 {
@@ -133,7 +133,7 @@ Ref: Antalis Limited`,
 	// Verify Start Balance is emitted as a BALANCE transaction
 	found := false
 	for _, txn := range info.Transactions {
-		if txn.Type == "BALANCE" && txn.Balance == 9856.68 {
+		if txn.Type == "BALANCE" && txn.Balance.Float64() == 9856.68 {
 			found = true
 			if txn.Date != "4 Dec" {
 				t.Errorf("Start Balance date: got %q, want %q", txn.Date, "4 Dec")
@@ -148,13 +148,13 @@ Ref: Antalis Limited`,
 	// Verify the bill payment has the correct date from the "Start Balance" line
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 400.00 && txn.Type == "DEBIT" {
+		if txn.Amount.Float64() == 400.00 && txn.Type == "DEBIT" {
 			found = true
 			if txn.Date != "4 Dec" {
 				t.Errorf("Mads Rose Trading txn date: got %q, want %q", txn.Date, "4 Dec")
 			}
-			if txn.Balance != 9456.68 {
-				t.Errorf("Mads Rose Trading txn balance: got %.2f, want 9456.68", txn.Balance)
+			if txn.Balance.Float64() != 9456.68 {
+				t.Errorf("Mads Rose Trading txn balance: got %.2f, want 9456.68", txn.Balance.Float64())
 			}
 			break
 		}
@@ -166,10 +166,10 @@ Ref: Antalis Limited`,
 	// Transaction 2: Direct Debit to Stripe (debit)
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 58.80 && txn.Type == "DEBIT" {
+		if txn.Amount.Float64() == 58.80 && txn.Type == "DEBIT" {
 			found = true
-			if txn.Balance != 9397.88 {
-				t.Errorf("Stripe txn balance: got %.2f, want 9397.88", txn.Balance)
+			if txn.Balance.Float64() != 9397.88 {
+				t.Errorf("Stripe txn balance: got %.2f, want 9397.88", txn.Balance.Float64())
 			}
 			break
 		}
@@ -181,10 +181,10 @@ Ref: Antalis Limited`,
 	// Transaction 3: Direct Credit from Antalis (credit)
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 10500.00 && txn.Type == "CREDIT" {
+		if txn.Amount.Float64() == 10500.00 && txn.Type == "CREDIT" {
 			found = true
-			if txn.Balance != 19749.38 {
-				t.Errorf("Antalis credit balance: got %.2f, want 19749.38", txn.Balance)
+			if txn.Balance.Float64() != 19749.38 {
+				t.Errorf("Antalis credit balance: got %.2f, want 19749.38", txn.Balance.Float64())
 			}
 			break
 		}
@@ -272,15 +272,15 @@ Ref: Inv 2484`,
 	}
 
 	// Verify opening balance from "Balance brought forward" line
-	if info.OpeningBalance != 13234.35 {
-		t.Errorf("opening balance: got %.2f, want 13234.35", info.OpeningBalance)
+	if info.OpeningBalance.Float64() != 13234.35 {
+		t.Errorf("opening balance: got %.2f, want 13234.35", info.OpeningBalance.Float64())
 	}
 
-	t.Logf("opening balance: %.2f", info.OpeningBalance)
+	t.Logf("opening balance: %.2f", info.OpeningBalance.Float64())
 	t.Logf("parsed %d transactions from page 2", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	// Should parse many transactions from this page
@@ -291,7 +291,7 @@ Ref: Inv 2484`,
 	// Verify Antalis credit on page 2
 	found := false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 10500.00 && txn.Type == "CREDIT" {
+		if txn.Amount.Float64() == 10500.00 && txn.Type == "CREDIT" {
 			found = true
 			break
 		}
@@ -303,7 +303,7 @@ Ref: Inv 2484`,
 	// Verify HMRC payment (debit)
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 772.17 && txn.Type == "DEBIT" {
+		if txn.Amount.Float64() == 772.17 && txn.Type == "DEBIT" {
 			found = true
 			break
 		}
@@ -341,16 +341,16 @@ Registered in England. Registered No. 9740322. Registered Office: 1 Churchill Pl
 	t.Logf("parsed %d transactions from page 3", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	// Should find the DigitalOcean transaction
 	found := false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 53.11 && txn.Type == "DEBIT" {
+		if txn.Amount.Float64() == 53.11 && txn.Type == "DEBIT" {
 			found = true
-			if txn.Balance != 3727.12 {
-				t.Errorf("DigitalOcean balance: got %.2f, want 3727.12", txn.Balance)
+			if txn.Balance.Float64() != 3727.12 {
+				t.Errorf("DigitalOcean balance: got %.2f, want 3727.12", txn.Balance.Float64())
 			}
 			break
 		}
@@ -370,8 +370,8 @@ Registered in England. Registered No. 9740322. Registered Office: 1 Churchill Pl
 				t.Errorf("Balance carried forward has trailing text: got %q, want %q",
 					txn.Description, "Balance carried forward")
 			}
-			if txn.Balance != 3727.12 {
-				t.Errorf("Balance carried forward balance: got %.2f, want 3727.12", txn.Balance)
+			if txn.Balance.Float64() != 3727.12 {
+				t.Errorf("Balance carried forward balance: got %.2f, want 3727.12", txn.Balance.Float64())
 			}
 			break
 		}
@@ -379,6 +379,12 @@ Registered in England. Registered No. 9740322. Registered Office: 1 Churchill Pl
 	if !found {
 		t.Error("expected to find Balance carried forward transaction")
 	}
+
+	// The printed totals line is skipped as a transaction but recorded
+	if info.PrintedTotalDebit.Float64() != 27129.56 || info.PrintedTotalCredit.Float64() != 21000.00 {
+		t.Errorf("printed totals: got %.2f/%.2f, want 27129.56/21000.00",
+			info.PrintedTotalDebit.Float64(), info.PrintedTotalCredit.Float64())
+	}
 }
 
 func TestBarclaysParser_SharedDateFormat(t *testing.T) {
@@ -410,7 +416,7 @@ Direct Credit From Antalis Limited 10,500.00 20,213.88
 	t.Logf("parsed %d transactions (shared-date format)", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	// Should parse at least 8 transactions (including Start Balance)
@@ -419,14 +425,14 @@ Direct Credit From Antalis Limited 10,500.00 20,213.88
 	}
 
 	// Verify OpeningBalance is captured
-	if info.OpeningBalance != 9856.68 {
-		t.Errorf("opening balance: got %.2f, want 9856.68", info.OpeningBalance)
+	if info.OpeningBalance.Float64() != 9856.68 {
+		t.Errorf("opening balance: got %.2f, want 9856.68", info.OpeningBalance.Float64())
 	}
 
 	// Verify Start Balance transaction
 	found := false
 	for _, txn := range info.Transactions {
-		if txn.Type == "BALANCE" && txn.Balance == 9856.68 {
+		if txn.Type == "BALANCE" && txn.Balance.Float64() == 9856.68 {
 			found = true
 			if txn.Date != "4 Dec" {
 				t.Errorf("Start Balance date: got %q, want %q", txn.Date, "4 Dec")
@@ -441,10 +447,10 @@ Direct Credit From Antalis Limited 10,500.00 20,213.88
 	// Verify bill payment on same date as Start Balance (shared date "4 Dec")
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 400.00 && txn.Date == "4 Dec" && txn.Type == "DEBIT" {
+		if txn.Amount.Float64() == 400.00 && txn.Date == "4 Dec" && txn.Type == "DEBIT" {
 			found = true
-			if txn.Balance != 9456.68 {
-				t.Errorf("Mads Rose balance: got %.2f, want 9456.68", txn.Balance)
+			if txn.Balance.Float64() != 9456.68 {
+				t.Errorf("Mads Rose balance: got %.2f, want 9456.68", txn.Balance.Float64())
 			}
 			break
 		}
@@ -456,7 +462,7 @@ Direct Credit From Antalis Limited 10,500.00 20,213.88
 	// Verify transaction under "5 Dec" with no date prefix (inherited date)
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 800.00 && txn.Type == "CREDIT" {
+		if txn.Amount.Float64() == 800.00 && txn.Type == "CREDIT" {
 			found = true
 			if txn.Date != "5 Dec" {
 				t.Errorf("Antalis 800.00 credit date: got %q, want %q", txn.Date, "5 Dec")
@@ -482,10 +488,10 @@ Direct Credit From Antalis Limited 10,500.00 20,213.88
 	// Verify Antalis credit on 8 Dec
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 10500.00 && txn.Type == "CREDIT" && txn.Date == "8 Dec" {
+		if txn.Amount.Float64() == 10500.00 && txn.Type == "CREDIT" && txn.Date == "8 Dec" {
 			found = true
-			if txn.Balance != 20213.88 {
-				t.Errorf("Antalis 10,500 balance: got %.2f, want 20213.88", txn.Balance)
+			if txn.Balance.Float64() != 20213.88 {
+				t.Errorf("Antalis 10,500 balance: got %.2f, want 20213.88", txn.Balance.Float64())
 			}
 			break
 		}
@@ -497,7 +503,7 @@ Direct Credit From Antalis Limited 10,500.00 20,213.88
 	// Verify 9 Dec transaction
 	found = false
 	for _, txn := range info.Transactions {
-		if txn.Amount == 14.99 && txn.Date == "9 Dec" && txn.Type == "DEBIT" {
+		if txn.Amount.Float64() == 14.99 && txn.Date == "9 Dec" && txn.Type == "DEBIT" {
 			found = true
 			break
 		}
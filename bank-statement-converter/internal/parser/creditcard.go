@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// Credit card statements list each transaction with a transaction date and
+// usually a posting date, a description and a single amount. There is no
+// running balance; payments and refunds are marked with a "CR" suffix:
+//
+//	Transaction date | Posting date | Description | Amount
+//	02 Jan 03 Jan TESCO STORES LONDON 25.99
+//	15 Jan 15 Jan PAYMENT RECEIVED - THANK YOU 500.00 CR
+//
+// The statement summary (previous balance, new balance, minimum payment and
+// payment due date) is printed as labelled lines above the table. The
+// walking logic is shared; each issuer supplies its boilerplate filter.
+
+const cardMonth = `(?i:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-zA-Z]*`
+
+// cardDateGroup matches "02 Jan", "Jan 02" (Amex) or "02/01/24".
+const cardDateGroup = `(\d{1,2}\s+` + cardMonth + `|` + cardMonth + `\s+\d{1,2}|\d{1,2}/\d{1,2}/\d{2,4})`
+
+// Credit card transaction line pattern:
+// DATE  [POSTING_DATE]  DESCRIPTION  AMOUNT  [CR]
+var creditCardTxnPattern = regexp.MustCompile(
+	`^` + cardDateGroup + `(?:\s+` + cardDateGroup + `)?\s+(.+?)\s+([-\x{2212}]?£?[\d,]+\.\d{2})\s*((?i:cr))?$`,
+)
+
+var creditCardDateStart = regexp.MustCompile(`^` + cardDateGroup + `(?:\s|$)`)
+
+// maskedCardPattern matches masked card numbers such as
+// "xxxx-xxxxxx-51005" (Amex) or "**** **** **** 1234".
+var maskedCardPattern = regexp.MustCompile(`(?i)(?:[x*•]{4,6}[\s-]?){2,3}\d{4,5}\b`)
+
+// parseCreditCardLines walks one page of a credit card statement and returns
+// its transactions. Charges are DEBIT; "CR" or negative amounts are CREDIT.
+func parseCreditCardLines(lines []string, isFooter func(string) bool) []models.Transaction {
+	var transactions []models.Transaction
+	inTransactionSection := false
+	// Continuation lines (merchant location, FX detail) only follow a
+	// transaction directly; summary and total lines end the run.
+	canContinue := false
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if containsTransactionHeader(line) || strings.Contains(strings.ToLower(line), "transaction date") {
+			inTransactionSection = true
+			canContinue = false
+			continue
+		}
+
+		if isFooter(line) || isCreditCardSummaryLine(line) {
+			canContinue = false
+			continue
+		}
+
+		hasDate := creditCardDateStart.MatchString(line)
+		if !inTransactionSection && !hasDate {
+			continue
+		}
+		if hasDate {
+			inTransactionSection = true
+		}
+
+		if m := creditCardTxnPattern.FindStringSubmatch(line); m != nil {
+			amount, err := parseSignedAmount(m[4])
+			if err != nil {
+				continue
+			}
+			txn := models.Transaction{
+				Date:        m[1],
+				PostingDate: m[2],
+				Description: cleanDescription(m[3]),
+			}
+			if m[5] != "" || amount.IsNegative() {
+				txn.Type = "CREDIT"
+			} else {
+				txn.Type = "DEBIT"
+			}
+			txn.Amount = amount.Abs()
+			transactions = append(transactions, txn)
+			canContinue = true
+			continue
+		}
+
+		if canContinue && !hasDate {
+			last := &transactions[len(transactions)-1]
+			last.Description += " " + line
+		}
+	}
+
+	return transactions
+}
+
+// extractCreditCardSummary fills the statement-level credit card fields from
+// the labelled summary lines. Balances in credit ("CR") are negative.
+func extractCreditCardSummary(text string, info *models.StatementInfo) {
+	info.CreditCard = true
+	for _, raw := range strings.Split(text, "\n") {
+		line := normalizeLine(raw)
+		lower := strings.ToLower(line)
+
+		switch {
+		case strings.Contains(lower, "previous balance"):
+			if bal, ok := lastCardAmount(line); ok && info.OpeningBalance.IsZero() {
+				info.OpeningBalance = bal
+			}
+		case strings.Contains(lower, "new balance") || strings.Contains(lower, "statement balance"):
+			if bal, ok := lastCardAmount(line); ok && info.StatementBalance.IsZero() {
+				info.StatementBalance = bal
+			}
+		}
+
+		if strings.Contains(lower, "minimum payment") && info.MinimumPayment.IsZero() {
+			if amt, ok := lastCardAmount(line); ok {
+				info.MinimumPayment = amt
+			}
+		}
+		if info.PaymentDueDate == "" && (strings.Contains(lower, "payment due") ||
+			strings.Contains(lower, "due date") || strings.Contains(lower, "pay by")) {
+			if d := datePatternText.FindString(line); d != "" {
+				info.PaymentDueDate = d
+			} else if d := datePatternSlash.FindString(line); d != "" {
+				info.PaymentDueDate = d
+			}
+		}
+	}
+	info.ClosingBalance = info.StatementBalance
+}
+
+// lastCardAmount returns the last amount on a summary line, negated when it
+// carries a "CR" suffix.
+func lastCardAmount(line string) (models.Money, bool) {
+	locs := signedAmountPattern.FindAllStringIndex(line, -1)
+	if len(locs) == 0 {
+		return models.Money{}, false
+	}
+	loc := locs[len(locs)-1]
+	amt, err := parseSignedAmount(line[loc[0]:loc[1]])
+	if err != nil {
+		return models.Money{}, false
+	}
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(line[loc[1]:])), "CR") {
+		amt = amt.Neg()
+	}
+	return amt, true
+}
+
+// findMaskedCardNumber returns the masked card number printed on the
+// statement, or "" if there is none.
+func findMaskedCardNumber(text string) string {
+	return maskedCardPattern.FindString(text)
+}
+
+// isCreditCardSummaryLine detects statement summary and total lines, which
+// are never transactions even when dated.
+func isCreditCardSummaryLine(line string) bool {
+	lower := strings.ToLower(line)
+	summaryKeywords := []string{
+		"previous balance", "new balance", "statement balance",
+		"minimum payment", "payment due", "credit limit", "available credit",
+		"interest rate", "estimated interest",
+	}
+	for _, kw := range summaryKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	// Totals rows; matched as a prefix so merchants like "TOTAL FITNESS" survive
+	return strings.HasPrefix(lower, "total ")
+}
@@ -0,0 +1,195 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// Banks print dates in many shapes ("15/01/2024", "06 Feb 24",
+// "01 SEP 2025", "Jan 02") and Barclays business and credit card statements
+// print no year at all ("4 Dec"). resolveDates turns every transaction's raw
+// date into a models.Date, taking the year for short dates from the
+// statement period so that a December-January statement puts "28 Dec" in
+// the earlier year.
+
+// fullDateLayouts are the layouts of dates that carry a year. Text dates are
+// normalised (title case, no commas or hyphens) before matching.
+var fullDateLayouts = []string{
+	"2/1/2006", "2/1/06",
+	"2 Jan 2006", "2 January 2006", "2 Jan 06", "2 January 06",
+	"Jan 2 2006", "January 2 2006",
+	"2006-01-02",
+}
+
+// shortDateLayouts are the layouts of dates without a year.
+var shortDateLayouts = []string{
+	"2 Jan", "2 January", "Jan 2", "January 2", "2/1",
+}
+
+// shortDateGrace is how far outside the statement period a short date may
+// fall (posting lag on card statements).
+const shortDateGrace = 31 * 24 * time.Hour
+
+// dateWindow is the span short dates are placed in.
+type dateWindow struct {
+	start, end time.Time
+}
+
+// resolveDates fills ISODate and ISOPostingDate for every transaction.
+// layouts are tried before the built-in ones (template date formats).
+// PeriodStart and PeriodEnd are set from the printed statement period.
+func resolveDates(text string, info *models.StatementInfo, layouts ...string) {
+	w := statementWindow(text, info, layouts)
+	if start, end, ok := statementPeriod(info.StatementPeriod, layouts); ok {
+		info.PeriodStart = models.NewDate(start.Year(), start.Month(), start.Day())
+		info.PeriodEnd = models.NewDate(end.Year(), end.Month(), end.Day())
+	}
+
+	for i := range info.Transactions {
+		txn := &info.Transactions[i]
+		txn.ISODate = resolveDate(txn.Date, w, layouts)
+		txn.ISOPostingDate = resolveDate(txn.PostingDate, w, layouts)
+	}
+}
+
+// statementWindow returns the statement period. Without a printed period,
+// the window is the year up to the latest full transaction date, else up to
+// the latest full date anywhere in the text (an "Issued on" date).
+func statementWindow(text string, info *models.StatementInfo, layouts []string) dateWindow {
+	if start, end, ok := statementPeriod(info.StatementPeriod, layouts); ok {
+		return dateWindow{start, end}
+	}
+
+	end := latestFullDate(text, info, layouts)
+	if end.IsZero() {
+		return dateWindow{}
+	}
+	return dateWindow{end.AddDate(-1, 0, 0), end}
+}
+
+// statementPeriod parses a "<start> to <end>" statement period.
+func statementPeriod(period string, layouts []string) (start, end time.Time, ok bool) {
+	parts := strings.Split(period, " to ")
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	start, okStart := parseFullDate(parts[0], layouts)
+	end, okEnd := parseFullDate(parts[1], layouts)
+	if !okStart || !okEnd || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// latestFullDate returns the latest full transaction date, else the latest
+// full date in the text, or the zero time.
+func latestFullDate(text string, info *models.StatementInfo, layouts []string) time.Time {
+	var latest time.Time
+	for _, txn := range info.Transactions {
+		if t, ok := parseFullDate(txn.Date, layouts); ok && t.After(latest) {
+			latest = t
+		}
+	}
+	if !latest.IsZero() {
+		return latest
+	}
+
+	// Line by line: the text date pattern would otherwise join "18 Dec" to
+	// an amount on the next line
+	for _, line := range strings.Split(text, "\n") {
+		for _, re := range []*regexp.Regexp{datePatternSlash, datePatternText, datePatternDash} {
+			for _, s := range re.FindAllString(line, -1) {
+				if t, ok := parseFullDate(s, layouts); ok && t.After(latest) {
+					latest = t
+				}
+			}
+		}
+	}
+	return latest
+}
+
+// resolveDate parses a raw statement date. Dates without a year take the
+// latest year that puts them inside the window, else within shortDateGrace
+// of it, else the year of the window's end.
+func resolveDate(raw string, w dateWindow, layouts []string) models.Date {
+	if raw == "" {
+		return models.Date{}
+	}
+	if t, ok := parseFullDate(raw, layouts); ok {
+		return models.NewDate(t.Year(), t.Month(), t.Day())
+	}
+	if w.end.IsZero() {
+		return models.Date{}
+	}
+
+	s := normaliseDateText(raw)
+	for _, layout := range append(append([]string(nil), layouts...), shortDateLayouts...) {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			t, err = time.Parse(layout, raw)
+		}
+		if err != nil {
+			continue
+		}
+		return models.NewDate(w.yearFor(t.Month(), t.Day()), t.Month(), t.Day())
+	}
+	return models.Date{}
+}
+
+// yearFor picks the year for a month and day without one.
+func (w dateWindow) yearFor(month time.Month, day int) int {
+	end := w.end.Year()
+	for _, grace := range []time.Duration{0, shortDateGrace} {
+		for year := end + 1; year >= end-1; year-- {
+			d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+			if !d.Before(w.start.Add(-grace)) && !d.After(w.end.Add(grace)) {
+				return year
+			}
+		}
+	}
+	return end
+}
+
+// parseFullDate parses a date that carries a year. Template layouts
+// without a year parse to year 0 and are left to the short date path.
+func parseFullDate(raw string, layouts []string) (time.Time, bool) {
+	raw = strings.TrimSpace(raw)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, raw); err == nil && t.Year() != 0 {
+			return t, true
+		}
+	}
+	s := normaliseDateText(raw)
+	for _, layout := range fullDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normaliseDateText rewrites a text date into the form the layouts expect:
+// "01 SEP 2025" and "15-Jan-2024" become "01 Sep 2025" and "15 Jan 2024",
+// "Sept" becomes "Sep" and commas are dropped.
+func normaliseDateText(s string) string {
+	s = strings.NewReplacer(",", " ", ".", " ").Replace(s)
+	if strings.IndexFunc(s, unicode.IsLetter) >= 0 {
+		s = strings.ReplaceAll(s, "-", " ")
+	}
+	fields := strings.Fields(s)
+	for i, f := range fields {
+		if f == "" || !unicode.IsLetter(rune(f[0])) {
+			continue
+		}
+		f = strings.ToUpper(f[:1]) + strings.ToLower(f[1:])
+		if f == "Sept" {
+			f = "Sep"
+		}
+		fields[i] = f
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestResolveDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		period  string
+		layouts []string
+		dates   []string
+		want    []string
+	}{
+		{
+			name:  "full dates",
+			dates: []string{"15/01/2024", "06 Feb 24", "01 SEP 2025", "15-Jan-2024", "2 January 2024", "Jan 02 2024"},
+			want:  []string{"2024-01-15", "2024-02-06", "2025-09-01", "2024-01-15", "2024-01-02", "2024-01-02"},
+		},
+		{
+			name:   "short dates roll over December to January",
+			period: "15 Dec 2025 to 14 Jan 2026",
+			dates:  []string{"28 Dec", "31 Dec", "2 Jan", "Jan 14"},
+			want:   []string{"2025-12-28", "2025-12-31", "2026-01-02", "2026-01-14"},
+		},
+		{
+			name:  "issued-on date anchors statements without a period",
+			text:  "Barclays\nIssued on 05 January 2026\n4 Dec Start Balance 9,856.68",
+			dates: []string{"4 Dec", "31 Dec", "5 Jan"},
+			want:  []string{"2025-12-04", "2025-12-31", "2026-01-05"},
+		},
+		{
+			name:  "full transaction dates anchor short ones",
+			dates: []string{"29/12/2023", "2 Jan", "28 Dec"},
+			want:  []string{"2023-12-29", "2023-01-02", "2023-12-28"},
+		},
+		{
+			name:  "amount on the next line is not a year",
+			text:  "18 Dec\n29 Payment 10.00\nIssued on 05 January 2026",
+			dates: []string{"18 Dec"},
+			want:  []string{"2025-12-18"},
+		},
+		{
+			name:    "template layout without year",
+			period:  "01/02/2024 to 29/02/2024",
+			layouts: []string{"2 Jan"},
+			dates:   []string{"3 Feb"},
+			want:    []string{"2024-02-03"},
+		},
+		{
+			name:  "no year anywhere",
+			text:  "Date Description Paid In Withdrawn Balance",
+			dates: []string{"3 Feb", ""},
+			want:  []string{"", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &models.StatementInfo{StatementPeriod: tt.period}
+			for _, d := range tt.dates {
+				info.Transactions = append(info.Transactions, models.Transaction{Date: d})
+			}
+			resolveDates(tt.text, info, tt.layouts...)
+			for i, want := range tt.want {
+				if got := info.Transactions[i].ISODate.String(); got != want {
+					t.Errorf("txn[%d] %q: got %q, want %q", i, tt.dates[i], got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveDates_PostingDate(t *testing.T) {
+	info := &models.StatementInfo{
+		StatementPeriod: "01/12/2023 to 31/12/2023",
+		Transactions: []models.Transaction{
+			{Date: "30 Dec", PostingDate: "02 Jan"},
+		},
+	}
+	resolveDates("", info)
+
+	if info.PeriodStart.String() != "2023-12-01" || info.PeriodEnd.String() != "2023-12-31" {
+		t.Errorf("period: got %s to %s, want 2023-12-01 to 2023-12-31", info.PeriodStart, info.PeriodEnd)
+	}
+
+	txn := info.Transactions[0]
+	if txn.ISODate.String() != "2023-12-30" {
+		t.Errorf("ISODate: got %q, want 2023-12-30", txn.ISODate)
+	}
+	// Posted just after the period end: same statement, next year
+	if txn.ISOPostingDate.String() != "2024-01-02" {
+		t.Errorf("ISOPostingDate: got %q, want 2024-01-02", txn.ISOPostingDate)
+	}
+}
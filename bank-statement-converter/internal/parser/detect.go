@@ -0,0 +1,334 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+	"github.com/insightdelivered/bank-statement-converter/internal/validator"
+)
+
+// Bank detection scores every known bank against the statement text instead
+// of returning the first keyword hit, so a payee name ("Transfer to
+// Barclays") cannot outweigh the issuing bank's own header and footer.
+//
+// Evidence weights:
+//
+//	keyword in the first lines of page 1 (logo, header)  4
+//	keyword elsewhere outside table rows (footer, address) 2
+//	keyword only inside transaction rows (payee)          0.5
+//	sort code in the bank's range                         3
+//	bank's table header signature                         2
+//	dry run: balances reconcile                           3
+//	dry run: transactions parsed, nothing to reconcile    1
+//	dry run: no transactions                             -2
+//
+// A score of 10 or more is full confidence.
+const (
+	weightPageOneHeader  = 4.0
+	weightBodyText       = 2.0
+	weightRowMention     = 0.5
+	weightSortCode       = 3.0
+	weightTableHeader    = 2.0
+	weightReconciles     = 3.0
+	weightParses         = 1.0
+	weightNoTransactions = -2.0
+
+	fullConfidenceScore = 10.0
+	// minDetectScore rejects statements where the only evidence is a payee
+	// mention.
+	minDetectScore = 1.0
+	// pageOneHeaderLines is how many non-table lines at the top of page 1
+	// count as the statement header.
+	pageOneHeaderLines = 15
+	// dryRunCandidates bounds how many leading candidates are dry-run.
+	dryRunCandidates = 3
+)
+
+// bankSignature is the evidence that identifies one bank's statements.
+type bankSignature struct {
+	bank models.BankType
+	// keywords are logo, legal name and domain text.
+	keywords []string
+	// sortCodes are sort code prefixes ("40", "60-83-71"); the longest
+	// matching prefix across all banks wins.
+	sortCodes []string
+	// headers are table column header text unique to the bank's layout.
+	headers []string
+}
+
+// builtinSignatures lists the built-in banks. Ties are broken by this order.
+var builtinSignatures = []bankSignature{
+	{
+		bank:     models.BankAmex,
+		keywords: []string{"American Express", "americanexpress.co.uk"},
+		headers:  []string{"Process Date", "Transaction Details Amount"},
+	},
+	{
+		bank:     models.BankBarclaycard,
+		keywords: []string{"Barclaycard", "barclaycard.co.uk"},
+		headers:  []string{"Posting date"},
+	},
+	{
+		bank:      models.BankMetro,
+		keywords:  []string{"Metro Bank", "metrobankonline"},
+		sortCodes: []string{"23-05"},
+		headers:   []string{"Description Paid out Paid in", "Transaction Money out (£) Money in (£)"},
+	},
+	{
+		bank:      models.BankHSBC,
+		keywords:  []string{"HSBC", "hsbc.co.uk", "HSBC UK Bank"},
+		sortCodes: []string{"40"},
+		headers:   []string{"Payment type and details", "Details Paid out Paid in"},
+	},
+	{
+		bank:      models.BankBarclays,
+		keywords:  []string{"Barclays", "barclays.co.uk"},
+		sortCodes: []string{"20"},
+		headers:   []string{"Money out £", "Money out → Money in"},
+	},
+	{
+		bank:      models.BankLloyds,
+		keywords:  []string{"Lloyds Bank", "lloydsbank.com"},
+		sortCodes: []string{"30", "77"},
+		headers:   []string{"Type Money In (£)"},
+	},
+	{
+		bank:      models.BankRBS,
+		keywords:  []string{"Royal Bank of Scotland", "rbs.co.uk", "rbsdigital"},
+		sortCodes: []string{"83", "16"},
+		headers:   []string{"Paid In(£) Withdrawn(£)"},
+	},
+	{
+		bank:      models.BankNatWest,
+		keywords:  []string{"NatWest", "National Westminster", "natwest.com"},
+		sortCodes: []string{"60", "50", "51", "52", "53", "54", "55", "56"},
+		headers:   []string{"Paid In(£) Withdrawn(£)"},
+	},
+	{
+		bank:      models.BankSantander,
+		keywords:  []string{"Santander", "santander.co.uk"},
+		sortCodes: []string{"09", "72"},
+		headers:   []string{"Description Money in Money out"},
+	},
+	{
+		bank:      models.BankNationwide,
+		keywords:  []string{"Nationwide Building Society", "NATIONWIDE", "nationwide.co.uk"},
+		sortCodes: []string{"07"},
+		headers:   []string{"£Out £In"},
+	},
+	{
+		bank:      models.BankMonzo,
+		keywords:  []string{"Monzo", "monzo.com"},
+		sortCodes: []string{"04-00-04"},
+		headers:   []string{"(GBP) Amount"},
+	},
+	{
+		bank:      models.BankStarling,
+		keywords:  []string{"Starling Bank", "starlingbank.com"},
+		sortCodes: []string{"60-83-71"},
+		headers:   []string{"TYPE TRANSACTION IN OUT"},
+	},
+	{
+		bank:      models.BankRevolut,
+		keywords:  []string{"Revolut", "revolut.com"},
+		sortCodes: []string{"04-00-75"},
+		headers:   []string{"Product Opening balance Money out Money in"},
+	},
+}
+
+// signatures returns the built-in signatures followed by those of
+// registered templates.
+func signatures() []bankSignature {
+	sigs := append([]bankSignature(nil), builtinSignatures...)
+	for _, t := range registeredTemplates() {
+		sigs = append(sigs, bankSignature{
+			bank:     models.BankType(t.Bank),
+			keywords: t.Detect,
+			headers:  t.HeaderMarkers,
+		})
+	}
+	return sigs
+}
+
+// statementText splits statement text into the regions that carry
+// different weight as evidence.
+type statementText struct {
+	header []string // first non-table lines of page 1
+	body   []string // other non-table lines
+	rows   []string // lines with amounts (transaction rows)
+	all    string
+}
+
+func splitStatementText(pages []string) statementText {
+	var st statementText
+	st.all = strings.Join(pages, "\n")
+	for i, page := range pages {
+		for _, raw := range strings.Split(page, "\n") {
+			line := normalizeLine(raw)
+			switch {
+			case line == "":
+			case amountPattern.MatchString(line):
+				st.rows = append(st.rows, line)
+			case i == 0 && len(st.header) < pageOneHeaderLines:
+				st.header = append(st.header, line)
+			default:
+				st.body = append(st.body, line)
+			}
+		}
+	}
+	return st
+}
+
+// firstKeywordIn returns the first keyword found in any of lines, or "".
+func firstKeywordIn(lines []string, keywords []string) string {
+	for _, line := range lines {
+		for _, kw := range keywords {
+			if containsIgnoreCase(line, kw) {
+				return kw
+			}
+		}
+	}
+	return ""
+}
+
+// RankBanks scores every known bank against the statement text and returns
+// the banks with any evidence, best first. With dryRun, the leading
+// candidates' parsers are run against the statement and banks whose
+// transactions reconcile with their running balances are preferred.
+func RankBanks(pages []string, dryRun bool) []models.BankCandidate {
+	st := splitStatementText(pages)
+	sigs := signatures()
+
+	sortCode := findSortCode(st.all)
+	longestPrefix := 0
+	if sortCode != "" {
+		for _, sig := range sigs {
+			for _, prefix := range sig.sortCodes {
+				if strings.HasPrefix(sortCode, prefix) && len(prefix) > longestPrefix {
+					longestPrefix = len(prefix)
+				}
+			}
+		}
+	}
+
+	nonRows := append(append([]string(nil), st.header...), st.body...)
+
+	var candidates []models.BankCandidate
+	for _, sig := range sigs {
+		c := models.BankCandidate{Bank: sig.bank}
+
+		headerKw := firstKeywordIn(st.header, sig.keywords)
+		bodyKw := firstKeywordIn(st.body, sig.keywords)
+		if headerKw != "" {
+			c.Score += weightPageOneHeader
+			c.Reasons = append(c.Reasons, fmt.Sprintf("page 1 header mentions %q", headerKw))
+		}
+		if bodyKw != "" {
+			c.Score += weightBodyText
+			c.Reasons = append(c.Reasons, fmt.Sprintf("statement text mentions %q", bodyKw))
+		}
+		if headerKw == "" && bodyKw == "" {
+			if rowKw := firstKeywordIn(st.rows, sig.keywords); rowKw != "" {
+				c.Score += weightRowMention
+				c.Reasons = append(c.Reasons, fmt.Sprintf("only transaction rows mention %q", rowKw))
+			}
+		}
+
+		for _, prefix := range sig.sortCodes {
+			if len(prefix) == longestPrefix && strings.HasPrefix(sortCode, prefix) {
+				c.Score += weightSortCode
+				c.Reasons = append(c.Reasons, fmt.Sprintf("sort code %s is in the bank's range", sortCode))
+				break
+			}
+		}
+
+		if hdr := firstKeywordIn(nonRows, sig.headers); hdr != "" {
+			c.Score += weightTableHeader
+			c.Reasons = append(c.Reasons, fmt.Sprintf("table header matches %q", hdr))
+		}
+
+		if c.Score > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sortCandidates(candidates)
+
+	if dryRun {
+		for i := range candidates {
+			if i >= dryRunCandidates || candidates[i].Score < minDetectScore {
+				break
+			}
+			dryRunCandidate(&candidates[i], pages)
+		}
+		sortCandidates(candidates)
+	}
+
+	for i := range candidates {
+		c := &candidates[i]
+		c.Confidence = math.Round(math.Min(math.Max(c.Score, 0)/fullConfidenceScore, 1)*100) / 100
+		if p, err := New(c.Bank); err == nil {
+			c.Name = p.BankName()
+		}
+	}
+
+	return candidates
+}
+
+// DetectBank returns the best-ranked bank together with the full ranking.
+// It fails when no bank has more than incidental evidence.
+func DetectBank(pages []string, dryRun bool) (models.BankType, []models.BankCandidate, error) {
+	candidates := RankBanks(pages, dryRun)
+	if len(candidates) == 0 || candidates[0].Score < minDetectScore {
+		return "", candidates, fmt.Errorf("could not auto-detect bank from statement content; please specify --bank flag")
+	}
+	return candidates[0].Bank, candidates, nil
+}
+
+// sortCandidates orders candidates by score, keeping signature order on ties.
+func sortCandidates(candidates []models.BankCandidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+}
+
+// dryRunCandidate parses the statement with the candidate's parser and
+// adjusts its score by whether the result looks right.
+func dryRunCandidate(c *models.BankCandidate, pages []string) {
+	c.DryRun = true
+	p, err := New(c.Bank)
+	if err != nil {
+		return
+	}
+	info, err := p.Parse(pages)
+	if err != nil {
+		c.Score += weightNoTransactions
+		c.Reasons = append(c.Reasons, fmt.Sprintf("dry run failed: %v", err))
+		return
+	}
+
+	for _, txn := range info.Transactions {
+		if txn.Type != "BALANCE" {
+			c.Transactions++
+		}
+	}
+
+	r := validator.Validate(info)
+	switch checked, ok := r.Checked, r.Checked-len(r.Mismatches); {
+	case c.Transactions == 0:
+		c.Score += weightNoTransactions
+		c.Reasons = append(c.Reasons, "dry run found no transactions")
+	case checked >= 2 && ok*10 >= checked*9:
+		c.Reconciled = true
+		c.Score += weightReconciles
+		c.Reasons = append(c.Reasons, fmt.Sprintf("dry run: %d of %d balances reconcile", ok, checked))
+	case checked == 0:
+		c.Score += weightParses
+		c.Reasons = append(c.Reasons, fmt.Sprintf("dry run: %d transactions, no balances to reconcile", c.Transactions))
+	default:
+		c.Reasons = append(c.Reasons, fmt.Sprintf("dry run: only %d of %d balances reconcile", ok, checked))
+	}
+}
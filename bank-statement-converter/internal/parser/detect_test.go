@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestRankBanks_PayeeDoesNotOutweighIssuer(t *testing.T) {
+	// Metro used to be checked first, so a Metro Bank payee on an HSBC
+	// statement was enough to mis-detect it.
+	pages := []string{
+		`HSBC UK Bank plc
+Your Statement
+Sort Code 40-12-34 Account Number 12345678
+Date Payment type and details Paid out Paid in Balance
+02 Jan 24 BP METRO BANK TRANSFER 500.00 1,000.00
+03 Jan 24 DD BARCLAYS PARTNER FIN 25.00 975.00`,
+	}
+
+	candidates := RankBanks(pages, false)
+	if len(candidates) < 3 {
+		t.Fatalf("candidates: got %d, want at least 3: %+v", len(candidates), candidates)
+	}
+	if candidates[0].Bank != models.BankHSBC {
+		t.Fatalf("top candidate: got %q, want %q", candidates[0].Bank, models.BankHSBC)
+	}
+	// header 4 + sort code 3 + table header 2
+	if candidates[0].Score != 9 || candidates[0].Confidence != 0.9 {
+		t.Errorf("HSBC score: got %.1f (confidence %.2f), want 9 (0.90)", candidates[0].Score, candidates[0].Confidence)
+	}
+	if candidates[0].Name != "HSBC" {
+		t.Errorf("HSBC name: got %q", candidates[0].Name)
+	}
+	for _, c := range candidates[1:] {
+		if c.Score != weightRowMention {
+			t.Errorf("%s: got score %.1f, want %.1f for a payee mention", c.Bank, c.Score, weightRowMention)
+		}
+		if len(c.Reasons) != 1 || !strings.Contains(c.Reasons[0], "transaction rows") {
+			t.Errorf("%s reasons: got %v", c.Bank, c.Reasons)
+		}
+	}
+}
+
+func TestRankBanks_SortCodeRange(t *testing.T) {
+	// Starling's 60-83-71 is a longer, more specific prefix than NatWest's 60
+	pages := []string{
+		`Personal Current Account
+Sort code 60-83-71 Account number 12345678
+Starling Bank Limited is registered in England and Wales`,
+	}
+
+	candidates := RankBanks(pages, false)
+	if len(candidates) == 0 || candidates[0].Bank != models.BankStarling {
+		t.Fatalf("top candidate: got %+v, want starling", candidates)
+	}
+	for _, c := range candidates {
+		if c.Bank == models.BankNatWest {
+			t.Errorf("NatWest should not score on Starling's sort code: %+v", c)
+		}
+	}
+}
+
+func TestDetectBank_OnlyPayeeMentions(t *testing.T) {
+	pages := []string{"Some Credit Union\n02/01/2024 TRANSFER TO MONZO 50.00 950.00"}
+
+	bank, candidates, err := DetectBank(pages, false)
+	if err == nil {
+		t.Fatalf("expected error, got bank %q", bank)
+	}
+	if len(candidates) != 1 || candidates[0].Bank != models.BankMonzo {
+		t.Errorf("ranking should still be returned: got %+v", candidates)
+	}
+}
+
+func TestDetectBank_DryRunPrefersReconcilingParser(t *testing.T) {
+	// Both banks are only named in the body text; Santander wins the tie on
+	// order, but only the Nationwide parser can read the table.
+	pages := []string{
+		`Statement
+January 2024
+02 Jan Balance from statement 12 dated 31/12/2023 1,000.00
+03 Jan Visa purchase TESCO STORES 25.99 974.01
+Contactless Payment COSTA 3.50 970.51
+04 Jan Bank credit EMPLOYER 100.00 1,070.51
+Payments to Santander are processed by Nationwide Building Society`,
+	}
+
+	bank, _, err := DetectBank(pages, false)
+	if err != nil || bank != models.BankSantander {
+		t.Fatalf("without dry run: got %q, %v; want santander", bank, err)
+	}
+
+	bank, candidates, err := DetectBank(pages, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bank != models.BankNationwide {
+		t.Fatalf("with dry run: got %q, want %q; ranking: %+v", bank, models.BankNationwide, candidates)
+	}
+	top := candidates[0]
+	if !top.DryRun || !top.Reconciled || top.Transactions != 3 {
+		t.Errorf("top candidate dry run: got %+v", top)
+	}
+	for _, c := range candidates {
+		if c.Bank == models.BankSantander && (c.Transactions != 0 || c.Reconciled) {
+			t.Errorf("santander dry run: got %+v", c)
+		}
+	}
+}
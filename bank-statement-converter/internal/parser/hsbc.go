@@ -73,6 +73,9 @@ func (p *HSBCParser) Parse(pages []string) (*models.StatementInfo, error) {
 	// Post-process: determine debit/credit by comparing balance changes
 	p.inferDebitCreditFromBalances(info.Transactions)
 
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
 	return info, nil
 }
 
@@ -294,7 +297,7 @@ func (p *HSBCParser) tryTabSeparated(line string) (models.Transaction, bool) {
 	}
 
 	// Scan from the right to find amount cells
-	var amounts []float64
+	var amounts []models.Money
 	rightBoundary := len(parts)
 	for i := len(parts) - 1; i >= 1; i-- {
 		cell := strings.TrimSpace(parts[i])
@@ -303,7 +306,7 @@ func (p *HSBCParser) tryTabSeparated(line string) (models.Transaction, bool) {
 		}
 		if m := amountCellPattern.FindStringSubmatch(cell); m != nil {
 			amt, _ := parseAmount(m[1])
-			amounts = append([]float64{amt}, amounts...) // prepend to keep order
+			amounts = append([]models.Money{amt}, amounts...) // prepend to keep order
 			rightBoundary = i
 		} else {
 			break // stop at first non-amount cell
@@ -341,7 +344,7 @@ func (p *HSBCParser) tryTabSeparated(line string) (models.Transaction, bool) {
 	case 1:
 		// Just a balance (e.g., "BALANCE BROUGHT FORWARD")
 		txn.Balance = amounts[0]
-		txn.Amount = 0
+		txn.Amount = models.Money{}
 		if isDebitDescription(description) {
 			txn.Type = "DEBIT"
 		} else {
@@ -359,10 +362,10 @@ func (p *HSBCParser) tryTabSeparated(line string) (models.Transaction, bool) {
 	case 3:
 		// paidOut + paidIn + balance
 		txn.Balance = amounts[2]
-		if amounts[0] > 0 && amounts[1] == 0 {
+		if amounts[0].IsPositive() && amounts[1].IsZero() {
 			txn.Amount = amounts[0]
 			txn.Type = "DEBIT"
-		} else if amounts[1] > 0 {
+		} else if amounts[1].IsPositive() {
 			txn.Amount = amounts[1]
 			txn.Type = "CREDIT"
 		} else {
@@ -419,7 +422,7 @@ func (p *HSBCParser) tryGenericDateLine(line string) (models.Transaction, bool)
 
 	// Extract all amounts
 	amountMatches := trailingAmountsPattern.FindAllStringSubmatch(rest, -1)
-	var amounts []float64
+	var amounts []models.Money
 	for _, m := range amountMatches {
 		amt, _ := parseAmount(m[1])
 		amounts = append(amounts, amt)
@@ -448,10 +451,10 @@ func (p *HSBCParser) tryGenericDateLine(line string) (models.Transaction, bool)
 		}
 	case 3:
 		txn.Balance = amounts[2]
-		if amounts[0] > 0 && amounts[1] == 0 {
+		if amounts[0].IsPositive() && amounts[1].IsZero() {
 			txn.Amount = amounts[0]
 			txn.Type = "DEBIT"
-		} else if amounts[1] > 0 {
+		} else if amounts[1].IsPositive() {
 			txn.Amount = amounts[1]
 			txn.Type = "CREDIT"
 		} else {
@@ -510,31 +513,24 @@ func (p *HSBCParser) inferDebitCreditFromBalances(txns []models.Transaction) {
 		curr := &txns[i]
 
 		// Only infer if both have a balance and current has an amount
-		if prev.Balance == 0 || curr.Balance == 0 || curr.Amount == 0 {
+		if prev.Balance.IsZero() || curr.Balance.IsZero() || curr.Amount.IsZero() {
 			continue
 		}
 
-		diff := curr.Balance - prev.Balance
-		if diff < 0 {
+		diff := curr.Balance.Sub(prev.Balance)
+		if diff.IsNegative() {
 			// Balance went down — this is a debit (money out)
 			curr.Type = "DEBIT"
 			// If no amount was parsed, use the balance difference
-			if curr.Amount == 0 {
-				curr.Amount = abs(diff)
+			if curr.Amount.IsZero() {
+				curr.Amount = diff.Abs()
 			}
-		} else if diff > 0 {
+		} else if diff.IsPositive() {
 			// Balance went up — this is a credit (money in)
 			curr.Type = "CREDIT"
-			if curr.Amount == 0 {
+			if curr.Amount.IsZero() {
 				curr.Amount = diff
 			}
 		}
 	}
 }
-
-func abs(x float64) float64 {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
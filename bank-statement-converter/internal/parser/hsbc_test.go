@@ -43,7 +43,7 @@ Date Payment type and details Paid out Paid in Balance
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] %s | %s | %s | %.2f | %.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 }
 
@@ -76,7 +76,7 @@ func TestHSBCParser_TabSeparated(t *testing.T) {
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] %s | %s | %s | %.2f | %.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	// Verify balance inference: TESCO should be DEBIT (balance went down)
@@ -85,8 +85,8 @@ func TestHSBCParser_TabSeparated(t *testing.T) {
 			if txn.Type != "DEBIT" {
 				t.Errorf("TESCO: expected DEBIT, got %s", txn.Type)
 			}
-			if txn.Amount != 25.99 {
-				t.Errorf("TESCO: expected amount 25.99, got %.2f", txn.Amount)
+			if txn.Amount.Float64() != 25.99 {
+				t.Errorf("TESCO: expected amount 25.99, got %.2f", txn.Amount.Float64())
 			}
 		}
 		if txn.Description == "SALARY FROM EMPLOYER LTD" {
@@ -116,7 +116,7 @@ func TestHSBCParser_TabSplitDescription(t *testing.T) {
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] %s | %q | %s | %.2f | %.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	if len(info.Transactions) < 2 {
@@ -147,7 +147,7 @@ func TestHSBCParser_RealHSBCFormat(t *testing.T) {
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] %s | %q | %s | %.2f | %.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	if len(info.Transactions) < 3 {
@@ -166,8 +166,8 @@ func TestHSBCParser_RealHSBCFormat(t *testing.T) {
 	for _, txn := range info.Transactions {
 		if strings.Contains(txn.Description, "INTEREST") {
 			found = true
-			if txn.Amount != 6.07 {
-				t.Errorf("interest amount: got %.2f, want 6.07", txn.Amount)
+			if txn.Amount.Float64() != 6.07 {
+				t.Errorf("interest amount: got %.2f, want 6.07", txn.Amount.Float64())
 			}
 			if txn.Type != "CREDIT" {
 				t.Errorf("interest type: got %s, want CREDIT", txn.Type)
@@ -202,7 +202,7 @@ func TestHSBCParser_SplitLineJoin(t *testing.T) {
 	t.Logf("parsed %d transactions", len(info.Transactions))
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] %s | %q | %s | %.2f | %.2f | method=%s",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance, txn.ParseMethod)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64(), txn.ParseMethod)
 	}
 
 	if len(info.Transactions) != 3 {
@@ -214,11 +214,11 @@ func TestHSBCParser_SplitLineJoin(t *testing.T) {
 	if !strings.Contains(interest.Description, "INTEREST") {
 		t.Errorf("expected interest transaction at index 1, got %q", interest.Description)
 	}
-	if interest.Amount != 6.07 {
-		t.Errorf("interest amount: got %.2f, want 6.07", interest.Amount)
+	if interest.Amount.Float64() != 6.07 {
+		t.Errorf("interest amount: got %.2f, want 6.07", interest.Amount.Float64())
 	}
-	if interest.Balance != 5113.94 {
-		t.Errorf("interest balance: got %.2f, want 5113.94", interest.Balance)
+	if interest.Balance.Float64() != 5113.94 {
+		t.Errorf("interest balance: got %.2f, want 5113.94", interest.Balance.Float64())
 	}
 	if interest.ParseMethod != "tab-separated-joined" {
 		t.Errorf("expected parse method 'tab-separated-joined', got %q", interest.ParseMethod)
@@ -0,0 +1,223 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// LloydsParser handles Lloyds Bank statement PDFs.
+//
+// Lloyds statements (personal and business) have this layout:
+//
+//	Date | Description | Type | Money In (£) | Money Out (£) | Balance (£)
+//
+// Date format: DD Mon YY (e.g., 15 Jan 24), DD Mon YYYY or DD/MM/YYYY
+// Example line: "15 Jan 24 TESCO STORES 3297 DEB 25.99 1,234.56"
+//
+// The Type column holds a short code that says how the money moved
+// (DEB = debit card, DD = direct debit, FPI = faster payment in, etc.), which
+// is a more reliable direction signal than the description text.
+type LloydsParser struct{}
+
+func (p *LloydsParser) BankName() string {
+	return "Lloyds Bank"
+}
+
+// lloydsTypeCodes maps Lloyds transaction type codes to a direction.
+// An empty direction means the code is used for both money in and money out
+// (e.g., TFR) and the running balance must decide.
+var lloydsTypeCodes = map[string]string{
+	"DEB": "DEBIT",  // debit card payment
+	"DD":  "DEBIT",  // direct debit
+	"SO":  "DEBIT",  // standing order
+	"CHQ": "DEBIT",  // cheque paid out
+	"FPO": "DEBIT",  // faster payment out
+	"BP":  "DEBIT",  // bill payment
+	"CPT": "DEBIT",  // cashpoint withdrawal
+	"FEE": "DEBIT",  // account fee
+	"PAY": "DEBIT",  // payment
+	"FPI": "CREDIT", // faster payment in
+	"BGC": "CREDIT", // bank giro credit
+	"DEP": "CREDIT", // deposit
+	"CSH": "CREDIT", // cash paid in
+	"TFR": "",       // transfer (either direction)
+	"COR": "",       // correction (either direction)
+}
+
+const lloydsDateGroup = `(\d{1,2}\s+(?i:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-zA-Z]*\s+\d{2,4}|\d{1,2}/\d{1,2}/\d{2,4})`
+
+const lloydsTypeGroup = `(DEB|DD|SO|CHQ|FPO|BP|CPT|FEE|PAY|FPI|BGC|DEP|CSH|TFR|COR)`
+
+// Lloyds transaction line pattern:
+// DATE  DESCRIPTION  TYPE  [MONEY_IN]  [MONEY_OUT]  [BALANCE]
+// Empty columns are dropped by text extraction, so 1-3 amounts follow the code.
+var lloydsTxnPattern = regexp.MustCompile(
+	`^` + lloydsDateGroup + `\s+(.+?)\s+` + lloydsTypeGroup + `\.?` +
+		`((?:\s+£?[\d,]+\.\d{2}){1,3})\s*$`,
+)
+
+// Fallback for lines where the Type column was lost or merged into the
+// description: DATE  DESCRIPTION  AMOUNT  [BALANCE]
+var lloydsTxnNoType = regexp.MustCompile(
+	`^` + lloydsDateGroup + `\s+(.+?)((?:\s+£?[\d,]+\.\d{2}){1,2})\s*$`,
+)
+
+func (p *LloydsParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankLloyds,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	var lastBalance models.Money
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		txns, openBal, newBalance := p.parseLines(lines, lastBalance)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+		if !newBalance.IsZero() {
+			lastBalance = newBalance
+		}
+	}
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// parseLines parses one page of a Lloyds statement. It returns the
+// transactions found, the opening balance (if the page has one) and the last
+// running balance so the next page can continue balance-based classification.
+func (p *LloydsParser) parseLines(lines []string, initialBalance models.Money) ([]models.Transaction, models.Money, models.Money) {
+	var transactions []models.Transaction
+	var openingBalance models.Money
+	inTransactionSection := false
+	lastBalance := initialBalance
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		// Opening balance rows ("STATEMENT OPENING BALANCE", "Balance brought
+		// forward") carry the starting balance but are not transactions.
+		if bal, ok := extractOpeningBalance(line); ok {
+			if openingBalance.IsZero() {
+				openingBalance = bal
+			}
+			lastBalance = bal
+			inTransactionSection = true
+			continue
+		}
+
+		if containsLloydsHeader(line) {
+			inTransactionSection = true
+			continue
+		}
+
+		// Closing balance rows repeat the final balance and are not transactions
+		if isLloydsFooter(line) || isTerminalTransaction(line) {
+			continue
+		}
+
+		hasDate := startsWithDate(line)
+		if !inTransactionSection && !hasDate {
+			continue
+		}
+		if hasDate {
+			inTransactionSection = true
+		}
+
+		if m := lloydsTxnPattern.FindStringSubmatch(line); m != nil {
+			txn := p.buildTxn(m[1], m[2], m[3], m[4], lastBalance)
+			if !txn.Balance.IsZero() {
+				lastBalance = txn.Balance
+			}
+			transactions = append(transactions, txn)
+			continue
+		}
+
+		if m := lloydsTxnNoType.FindStringSubmatch(line); m != nil {
+			txn := p.buildTxn(m[1], m[2], "", m[3], lastBalance)
+			if !txn.Balance.IsZero() {
+				lastBalance = txn.Balance
+			}
+			transactions = append(transactions, txn)
+			continue
+		}
+
+		// Multi-line description continuation
+		if len(transactions) > 0 && !hasDate && !isSummaryLine(line) {
+			last := &transactions[len(transactions)-1]
+			last.Description += " " + line
+		}
+	}
+
+	return transactions, openingBalance, lastBalance
+}
+
+// buildTxn builds a Transaction from the matched columns. amountsText holds
+// the 1-3 trailing amounts in column order (Money In, Money Out, Balance).
+func (p *LloydsParser) buildTxn(date, desc, code, amountsText string, lastBalance models.Money) models.Transaction {
+	txn := models.Transaction{
+		Date:        date,
+		Description: cleanDescription(desc),
+	}
+
+	assignInOutAmounts(&txn, parseAmounts(amountsText))
+
+	if txn.Type == "" {
+		txn.Type = lloydsTypeCodes[code]
+	}
+	if txn.Type == "" {
+		if !txn.Balance.IsZero() {
+			txn.Type = classifyByBalance(txn.Amount, txn.Balance, lastBalance, txn.Description)
+		} else if isCreditDescription(txn.Description) {
+			txn.Type = "CREDIT"
+		} else {
+			txn.Type = "DEBIT"
+		}
+	}
+
+	if code != "" {
+		txn.ParseMethod = "lloyds-" + strings.ToLower(code)
+	}
+
+	return txn
+}
+
+func containsLloydsHeader(line string) bool {
+	lower := strings.ToLower(line)
+	return strings.Contains(lower, "date") && strings.Contains(lower, "type") &&
+		(strings.Contains(lower, "money in") || strings.Contains(lower, "money out") ||
+			strings.Contains(lower, "balance")) ||
+		containsTransactionHeader(line)
+}
+
+// isLloydsFooter detects footer/boilerplate lines in Lloyds statements.
+func isLloydsFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"lloyds bank plc", "registered office", "registered in england",
+		"financial conduct authority", "prudential regulation",
+		"authorised by", "gresham street", "lloydsbank.com",
+		"transaction types", "please keep this statement",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLloydsParser_Parse(t *testing.T) {
+	p := &LloydsParser{}
+
+	pages := []string{
+		`Lloyds Bank
+Your Account
+Account name: Mr John Smith
+Sort code 30-94-57 Account number 12345678
+Statement period 01 January 2024 to 31 January 2024
+
+Date Description Type Money In (£) Money Out (£) Balance (£)
+01 Jan 24 STATEMENT OPENING BALANCE 1,000.00
+15 Jan 24 TESCO STORES 3297 DEB 25.99 974.01
+16 Jan 24 SKY DIGITAL DD 45.00 929.01
+17 Jan 24 EMPLOYER LTD SALARY BGC 2,500.00 3,429.01
+18 Jan 24 J DOE FPI 100.00 3,529.01`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.AccountNumber != "12345678" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "12345678")
+	}
+	if info.SortCode != "30-94-57" {
+		t.Errorf("sort code: got %q, want %q", info.SortCode, "30-94-57")
+	}
+	if info.OpeningBalance.Float64() != 1000.00 {
+		t.Errorf("opening balance: got %f, want %f", info.OpeningBalance.Float64(), 1000.00)
+	}
+	if info.StatementPeriod != "01 January 2024 to 31 January 2024" {
+		t.Errorf("statement period: got %q", info.StatementPeriod)
+	}
+
+	if len(info.Transactions) != 4 {
+		t.Fatalf("transactions: got %d, want 4; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx     int
+		date    string
+		desc    string
+		amount  float64
+		typ     string
+		balance float64
+	}{
+		{0, "15 Jan 24", "TESCO STORES 3297", 25.99, "DEBIT", 974.01},
+		{1, "16 Jan 24", "SKY DIGITAL", 45.00, "DEBIT", 929.01},
+		{2, "17 Jan 24", "EMPLOYER LTD SALARY", 2500.00, "CREDIT", 3429.01},
+		{3, "18 Jan 24", "J DOE", 100.00, "CREDIT", 3529.01},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Date != tt.date {
+			t.Errorf("txn[%d].Date: got %q, want %q", tt.idx, txn.Date, tt.date)
+		}
+		if txn.Description != tt.desc {
+			t.Errorf("txn[%d].Description: got %q, want %q", tt.idx, txn.Description, tt.desc)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %f, want %f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+}
+
+func TestLloydsParser_TypeCodes(t *testing.T) {
+	p := &LloydsParser{}
+
+	// Each type code on its own, with a balance progression that agrees.
+	// SO and CHQ are money out, TFR is resolved by balance.
+	pages := []string{
+		`Date Description Type Money In (£) Money Out (£) Balance (£)
+Balance brought forward 2,000.00
+02 Feb 24 LANDLORD RENT SO 750.00 1,250.00
+03 Feb 24 CHEQUE 000123 CHQ 120.00 1,130.00
+04 Feb 24 FROM SAVINGS TFR 500.00 1,630.00
+05 Feb 24 TO SAVINGS TFR 30.00 1,600.00
+06 Feb 24 REFUND AMAZON DEB 12.00 1,612.00`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(info.Transactions) != 5 {
+		t.Fatalf("transactions: got %d, want 5; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx    int
+		amount float64
+		typ    string
+	}{
+		{0, 750.00, "DEBIT"},
+		{1, 120.00, "DEBIT"},
+		{2, 500.00, "CREDIT"},
+		{3, 30.00, "DEBIT"},
+		// Type code wins over description heuristics
+		{4, 12.00, "DEBIT"},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+	}
+}
+
+func TestLloydsParser_ThreeAmountColumns(t *testing.T) {
+	p := &LloydsParser{}
+
+	// Some extractors render the empty money column as 0.00
+	pages := []string{
+		`Date Description Type Money In (£) Money Out (£) Balance (£)
+10 Mar 24 CLIENT PAYMENT TFR 300.00 0.00 1,300.00
+11 Mar 24 SUPPLIER TFR 0.00 80.00 1,220.00`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(info.Transactions) != 2 {
+		t.Fatalf("transactions: got %d, want 2", len(info.Transactions))
+	}
+
+	if txn := info.Transactions[0]; txn.Type != "CREDIT" || txn.Amount.Float64() != 300.00 || txn.Balance.Float64() != 1300.00 {
+		t.Errorf("txn[0]: got %s %.2f %.2f, want CREDIT 300.00 1300.00", txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
+	}
+	if txn := info.Transactions[1]; txn.Type != "DEBIT" || txn.Amount.Float64() != 80.00 || txn.Balance.Float64() != 1220.00 {
+		t.Errorf("txn[1]: got %s %.2f %.2f, want DEBIT 80.00 1220.00", txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
+	}
+}
+
+func TestLloydsParser_MultiLineAndMultiPage(t *testing.T) {
+	p := &LloydsParser{}
+
+	pages := []string{
+		`Lloyds Bank
+Date Description Type Money In (£) Money Out (£) Balance (£)
+01 Apr 2024 STATEMENT OPENING BALANCE 500.00
+02 Apr 2024 AMAZON MARKETPLACE DEB 40.00 460.00
+AMZN.CO.UK/BILL
+Page 1 of 2`,
+		`Date Description Type Money In (£) Money Out (£) Balance (£)
+Balance brought forward 460.00
+03 Apr 2024 HMRC VAT REFUND BGC 60.00 520.00
+30 Apr 2024 STATEMENT CLOSING BALANCE 520.00
+Lloyds Bank plc Registered Office: 25 Gresham Street, London EC2V 7HN`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.OpeningBalance.Float64() != 500.00 {
+		t.Errorf("opening balance: got %f, want %f (page 2 brought-forward must not override)", info.OpeningBalance.Float64(), 500.00)
+	}
+
+	if len(info.Transactions) != 2 {
+		t.Fatalf("transactions: got %d, want 2; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	if !strings.Contains(info.Transactions[0].Description, "AMZN.CO.UK/BILL") {
+		t.Errorf("txn[0] should include continuation line: got %q", info.Transactions[0].Description)
+	}
+	if strings.Contains(info.Transactions[0].Description, "Page") {
+		t.Errorf("txn[0] should not include page footer: got %q", info.Transactions[0].Description)
+	}
+	if info.Transactions[1].Type != "CREDIT" || info.Transactions[1].Amount.Float64() != 60.00 {
+		t.Errorf("txn[1]: got %s %.2f, want CREDIT 60.00", info.Transactions[1].Type, info.Transactions[1].Amount.Float64())
+	}
+}
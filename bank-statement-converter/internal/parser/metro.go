@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"math"
 	"regexp"
 	"strings"
 
@@ -59,7 +58,7 @@ func (p *MetroBankParser) Parse(pages []string) (*models.StatementInfo, error) {
 	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms "})
 	info.StatementPeriod = extractPeriod(allText)
 
-	var lastBalance float64
+	var lastBalance models.Money
 	for _, page := range pages {
 		lines := strings.Split(page, "\n")
 		txns, newBalance := p.parseLines(lines, lastBalance)
@@ -72,15 +71,18 @@ func (p *MetroBankParser) Parse(pages []string) (*models.StatementInfo, error) {
 			txns, newBalance = p.parseLinesColumns(lines, lastBalance)
 		}
 		info.Transactions = append(info.Transactions, txns...)
-		if newBalance != 0 {
+		if !newBalance.IsZero() {
 			lastBalance = newBalance
 		}
 	}
 
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
 	return info, nil
 }
 
-func (p *MetroBankParser) parseLines(lines []string, initialBalance float64) ([]models.Transaction, float64) {
+func (p *MetroBankParser) parseLines(lines []string, initialBalance models.Money) ([]models.Transaction, models.Money) {
 	var transactions []models.Transaction
 	inTransactionSection := false
 	lastBalance := initialBalance
@@ -121,7 +123,7 @@ func (p *MetroBankParser) parseLines(lines []string, initialBalance float64) ([]
 		// Try full pattern first (slash dates: DD/MM/YYYY)
 		if m := metroTxnPattern.FindStringSubmatch(matchLine); m != nil {
 			txn := p.buildFullTxn(m, lastBalance)
-			if txn.Balance != 0 {
+			if !txn.Balance.IsZero() {
 				lastBalance = txn.Balance
 			}
 			transactions = append(transactions, txn)
@@ -131,7 +133,7 @@ func (p *MetroBankParser) parseLines(lines []string, initialBalance float64) ([]
 		// Try full pattern (text dates: DD Mon YYYY)
 		if m := metroTxnPatternText.FindStringSubmatch(matchLine); m != nil {
 			txn := p.buildFullTxn(m, lastBalance)
-			if txn.Balance != 0 {
+			if !txn.Balance.IsZero() {
 				lastBalance = txn.Balance
 			}
 			transactions = append(transactions, txn)
@@ -189,17 +191,17 @@ func (p *MetroBankParser) parseLines(lines []string, initialBalance float64) ([]
 //  1. "desc" — collect date+description groups
 //  2. "money_out" — collect bare amounts (one per line)
 //  3. "money_in_bal" — collect 1-2 amounts per line (money-in+balance or balance-only)
-func (p *MetroBankParser) parseLinesColumns(lines []string, initialBalance float64) ([]models.Transaction, float64) {
+func (p *MetroBankParser) parseLinesColumns(lines []string, initialBalance models.Money) ([]models.Transaction, models.Money) {
 	type descEntry struct {
 		date string
 		desc string
 	}
 
 	var descs []descEntry
-	var moneyOut []float64
+	var moneyOut []models.Money
 	type balEntry struct {
-		moneyIn float64
-		balance float64
+		moneyIn models.Money
+		balance models.Money
 	}
 	var balEntries []balEntry
 
@@ -301,14 +303,14 @@ func (p *MetroBankParser) parseLinesColumns(lines []string, initialBalance float
 		case "money_out":
 			// Each line should be a bare amount
 			amt, err := parseAmount(line)
-			if err == nil && amt > 0 {
+			if err == nil && amt.IsPositive() {
 				moneyOut = append(moneyOut, amt)
 			} else {
 				// OCR corruption or non-amount line — add 0 placeholder
 				// so indexing stays aligned
 				if !isSummaryLine(line) && !isMetroFooter(line) &&
 					!strings.Contains(lower, "money") {
-					moneyOut = append(moneyOut, 0)
+					moneyOut = append(moneyOut, models.Money{})
 				}
 			}
 
@@ -323,12 +325,12 @@ func (p *MetroBankParser) parseLinesColumns(lines []string, initialBalance float
 				balEntries = append(balEntries, balEntry{moneyIn: moneyIn, balance: bal})
 			} else if len(amounts) == 1 {
 				bal, _ := parseAmount(amounts[0])
-				balEntries = append(balEntries, balEntry{moneyIn: 0, balance: bal})
+				balEntries = append(balEntries, balEntry{balance: bal})
 			} else {
 				// OCR corruption — placeholder
 				if !isSummaryLine(line) && !isMetroFooter(line) &&
 					!strings.Contains(lower, "money") && !strings.Contains(lower, "balance") {
-					balEntries = append(balEntries, balEntry{moneyIn: 0, balance: 0})
+					balEntries = append(balEntries, balEntry{})
 				}
 			}
 		}
@@ -353,7 +355,7 @@ func (p *MetroBankParser) parseLinesColumns(lines []string, initialBalance float
 		if i < len(balEntries) {
 			be := balEntries[i]
 			txn.Balance = be.balance
-			if be.moneyIn > 0 {
+			if be.moneyIn.IsPositive() {
 				// Credit transaction
 				txn.Amount = be.moneyIn
 				txn.Type = "CREDIT"
@@ -374,7 +376,7 @@ func (p *MetroBankParser) parseLinesColumns(lines []string, initialBalance float
 			}
 		}
 
-		if txn.Balance != 0 {
+		if !txn.Balance.IsZero() {
 			lastBalance = txn.Balance
 		}
 		transactions = append(transactions, txn)
@@ -403,7 +405,7 @@ func isMetroFooter(line string) bool {
 
 // buildFullTxn builds a Transaction from a full-pattern regex match
 // (groups: 1=date, 2=description, 3=paidOut?, 4=paidIn?, 5=balance).
-func (p *MetroBankParser) buildFullTxn(m []string, lastBalance float64) models.Transaction {
+func (p *MetroBankParser) buildFullTxn(m []string, lastBalance models.Money) models.Transaction {
 	txn := models.Transaction{
 		Date:        m[1],
 		Description: strings.TrimSpace(m[2]),
@@ -461,22 +463,13 @@ func (p *MetroBankParser) buildSimpleTxn(m []string) models.Transaction {
 // classifyByBalance determines whether a transaction is DEBIT or CREDIT
 // by comparing the amount and current balance against the previous balance.
 // Falls back to description-based heuristic when balance info is unavailable.
-func classifyByBalance(amt, bal, prevBal float64, desc string) string {
-	if prevBal != 0 {
-		debitDiff := math.Abs((prevBal - amt) - bal)
-		creditDiff := math.Abs((prevBal + amt) - bal)
-
-		if debitDiff < 0.015 && creditDiff >= 0.015 {
+func classifyByBalance(amt, bal, prevBal models.Money, desc string) string {
+	if !prevBal.IsZero() {
+		// Both match only for a zero amount; treat that as a debit
+		if prevBal.Sub(amt).Equal(bal) {
 			return "DEBIT"
 		}
-		if creditDiff < 0.015 && debitDiff >= 0.015 {
-			return "CREDIT"
-		}
-		// Both are close (unlikely) or neither matches — use the closer one
-		if debitDiff < 0.015 && creditDiff < 0.015 {
-			if debitDiff <= creditDiff {
-				return "DEBIT"
-			}
+		if prevBal.Add(amt).Equal(bal) {
 			return "CREDIT"
 		}
 	}
@@ -495,22 +488,22 @@ func classifyByBalance(amt, bal, prevBal float64, desc string) string {
 
 // extractOpeningBalance looks for opening/brought-forward balance lines
 // and returns the balance amount. Returns (0, false) if not found.
-func extractOpeningBalance(line string) (float64, bool) {
+func extractOpeningBalance(line string) (models.Money, bool) {
 	lower := strings.ToLower(line)
 	if !strings.Contains(lower, "opening balance") &&
 		!strings.Contains(lower, "balance brought forward") &&
 		!strings.Contains(lower, "brought forward") {
-		return 0, false
+		return models.Money{}, false
 	}
 
-	// Find the last amount on the line
-	amounts := metroAmountPattern.FindAllString(line, -1)
+	// Find the last amount on the line, keeping the sign of an overdrawn balance
+	amounts := signedAmountPattern.FindAllString(line, -1)
 	if len(amounts) == 0 {
-		return 0, false
+		return models.Money{}, false
 	}
-	bal, err := parseAmount(amounts[len(amounts)-1])
+	bal, err := parseSignedAmount(amounts[len(amounts)-1])
 	if err != nil {
-		return 0, false
+		return models.Money{}, false
 	}
 	return bal, true
 }
@@ -3,6 +3,8 @@ package parser
 import (
 	"strings"
 	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
 )
 
 func TestMetroBankParser_Parse(t *testing.T) {
@@ -45,8 +47,8 @@ Date Description Paid out Paid in Balance
 	if txn.Date != "15/01/2024" {
 		t.Errorf("txn[0].Date: got %q, want %q", txn.Date, "15/01/2024")
 	}
-	if txn.Amount != 25.99 {
-		t.Errorf("txn[0].Amount: got %f, want %f", txn.Amount, 25.99)
+	if txn.Amount.Float64() != 25.99 {
+		t.Errorf("txn[0].Amount: got %f, want %f", txn.Amount.Float64(), 25.99)
 	}
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[0].Type: got %q, want %q", txn.Type, "DEBIT")
@@ -54,8 +56,8 @@ Date Description Paid out Paid in Balance
 
 	// Check second transaction (debit)
 	txn = info.Transactions[1]
-	if txn.Amount != 45.00 {
-		t.Errorf("txn[1].Amount: got %f, want %f", txn.Amount, 45.00)
+	if txn.Amount.Float64() != 45.00 {
+		t.Errorf("txn[1].Amount: got %f, want %f", txn.Amount.Float64(), 45.00)
 	}
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[1].Type: got %q, want %q", txn.Type, "DEBIT")
@@ -66,8 +68,8 @@ Date Description Paid out Paid in Balance
 	if txn.Date != "17/01/2024" {
 		t.Errorf("txn[2].Date: got %q, want %q", txn.Date, "17/01/2024")
 	}
-	if txn.Amount != 2500.00 {
-		t.Errorf("txn[2].Amount: got %f, want %f", txn.Amount, 2500.00)
+	if txn.Amount.Float64() != 2500.00 {
+		t.Errorf("txn[2].Amount: got %f, want %f", txn.Amount.Float64(), 2500.00)
 	}
 	if txn.Type != "CREDIT" {
 		t.Errorf("txn[2].Type: got %q, want %q (Money In incorrectly classified)", txn.Type, "CREDIT")
@@ -75,8 +77,8 @@ Date Description Paid out Paid in Balance
 
 	// Check fourth transaction (debit after credit)
 	txn = info.Transactions[3]
-	if txn.Amount != 15.49 {
-		t.Errorf("txn[3].Amount: got %f, want %f", txn.Amount, 15.49)
+	if txn.Amount.Float64() != 15.49 {
+		t.Errorf("txn[3].Amount: got %f, want %f", txn.Amount.Float64(), 15.49)
 	}
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[3].Type: got %q, want %q", txn.Type, "DEBIT")
@@ -144,14 +146,14 @@ Opening balance 1,000.00
 
 	for _, tt := range tests {
 		txn := info.Transactions[tt.idx]
-		if txn.Amount != tt.amount {
-			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount, tt.amount)
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount.Float64(), tt.amount)
 		}
 		if txn.Type != tt.typ {
 			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
 		}
-		if txn.Balance != tt.balance {
-			t.Errorf("txn[%d].Balance: got %f, want %f", tt.idx, txn.Balance, tt.balance)
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %f, want %f", tt.idx, txn.Balance.Float64(), tt.balance)
 		}
 	}
 }
@@ -179,8 +181,8 @@ Opening balance 5,000.00
 	if txn.Type != "CREDIT" {
 		t.Errorf("txn[0].Type: got %q, want %q", txn.Type, "CREDIT")
 	}
-	if txn.Amount != 2500.00 {
-		t.Errorf("txn[0].Amount: got %f, want %f", txn.Amount, 2500.00)
+	if txn.Amount.Float64() != 2500.00 {
+		t.Errorf("txn[0].Amount: got %f, want %f", txn.Amount.Float64(), 2500.00)
 	}
 }
 
@@ -221,8 +223,8 @@ Balance brought forward 2,000.00
 
 	for _, tt := range tests {
 		txn := info.Transactions[tt.idx]
-		if txn.Amount != tt.amount {
-			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount, tt.amount)
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount.Float64(), tt.amount)
 		}
 		if txn.Type != tt.typ {
 			t.Errorf("txn[%d].Type: got %q, want %q (Money In/Out classification)", tt.idx, txn.Type, tt.typ)
@@ -309,32 +311,32 @@ NA
 
 	// Verify first transaction: Inward Payment (credit)
 	txn := info.Transactions[0]
-	if txn.Amount != 12495.00 {
-		t.Errorf("txn[0].Amount: got %f, want %f", txn.Amount, 12495.00)
+	if txn.Amount.Float64() != 12495.00 {
+		t.Errorf("txn[0].Amount: got %f, want %f", txn.Amount.Float64(), 12495.00)
 	}
 	if txn.Type != "CREDIT" {
 		t.Errorf("txn[0].Type: got %q, want %q", txn.Type, "CREDIT")
 	}
-	if txn.Balance != 19720.15 {
-		t.Errorf("txn[0].Balance: got %f, want %f", txn.Balance, 19720.15)
+	if txn.Balance.Float64() != 19720.15 {
+		t.Errorf("txn[0].Balance: got %f, want %f", txn.Balance.Float64(), 19720.15)
 	}
 
 	// Verify second transaction: Outward Faster Payment (debit)
 	txn = info.Transactions[1]
-	if txn.Amount != 1.00 {
-		t.Errorf("txn[1].Amount: got %f, want %f", txn.Amount, 1.00)
+	if txn.Amount.Float64() != 1.00 {
+		t.Errorf("txn[1].Amount: got %f, want %f", txn.Amount.Float64(), 1.00)
 	}
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[1].Type: got %q, want %q", txn.Type, "DEBIT")
 	}
-	if txn.Balance != 19719.15 {
-		t.Errorf("txn[1].Balance: got %f, want %f", txn.Balance, 19719.15)
+	if txn.Balance.Float64() != 19719.15 {
+		t.Errorf("txn[1].Balance: got %f, want %f", txn.Balance.Float64(), 19719.15)
 	}
 
 	// Verify page 2 first transaction: Inward Payment (credit)
 	txn = info.Transactions[5]
-	if txn.Amount != 15995.00 {
-		t.Errorf("txn[5].Amount: got %f, want %f", txn.Amount, 15995.00)
+	if txn.Amount.Float64() != 15995.00 {
+		t.Errorf("txn[5].Amount: got %f, want %f", txn.Amount.Float64(), 15995.00)
 	}
 	if txn.Type != "CREDIT" {
 		t.Errorf("txn[5].Type: got %q, want %q", txn.Type, "CREDIT")
@@ -342,8 +344,8 @@ NA
 
 	// Verify charges appear as transactions (Internet Banking Chgs)
 	txn = info.Transactions[7]
-	if txn.Amount != 5.00 {
-		t.Errorf("txn[7].Amount: got %f, want %f", txn.Amount, 5.00)
+	if txn.Amount.Float64() != 5.00 {
+		t.Errorf("txn[7].Amount: got %f, want %f", txn.Amount.Float64(), 5.00)
 	}
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[7].Type: got %q, want %q", txn.Type, "DEBIT")
@@ -388,14 +390,14 @@ Balance brought forward 1,000.00
 		if txn.Date != tt.date {
 			t.Errorf("txn[%d].Date: got %q, want %q", tt.idx, txn.Date, tt.date)
 		}
-		if txn.Amount != tt.amount {
-			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount, tt.amount)
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %f, want %f", tt.idx, txn.Amount.Float64(), tt.amount)
 		}
 		if txn.Type != tt.typ {
 			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
 		}
-		if txn.Balance != tt.balance {
-			t.Errorf("txn[%d].Balance: got %f, want %f", tt.idx, txn.Balance, tt.balance)
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %f, want %f", tt.idx, txn.Balance.Float64(), tt.balance)
 		}
 	}
 }
@@ -451,7 +453,7 @@ Money in (£) Balance (£)
 	// Log all transactions for debugging
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	tests := []struct {
@@ -475,11 +477,11 @@ Money in (£) Balance (£)
 		if txn.Type != tt.typ {
 			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
 		}
-		if txn.Amount != tt.amount {
-			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount, tt.amount)
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
 		}
-		if txn.Balance != tt.balance {
-			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance, tt.balance)
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
 		}
 	}
 
@@ -537,7 +539,7 @@ Money in (£) Balance (£)
 	// Log all transactions
 	for i, txn := range info.Transactions {
 		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
-			i, txn.Date, txn.Description, txn.Type, txn.Amount, txn.Balance)
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
 	}
 
 	// Page 1: 2 inline transactions + Page 2: 2 column-separated transactions
@@ -550,8 +552,8 @@ Money in (£) Balance (£)
 	if txn.Type != "CREDIT" {
 		t.Errorf("txn[0].Type: got %q, want CREDIT", txn.Type)
 	}
-	if txn.Amount != 12495.00 {
-		t.Errorf("txn[0].Amount: got %.2f, want 12495.00", txn.Amount)
+	if txn.Amount.Float64() != 12495.00 {
+		t.Errorf("txn[0].Amount: got %.2f, want 12495.00", txn.Amount.Float64())
 	}
 
 	// Page 1 txn 1: Outward Faster Payment (debit, inline)
@@ -559,8 +561,8 @@ Money in (£) Balance (£)
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[1].Type: got %q, want DEBIT", txn.Type)
 	}
-	if txn.Amount != 1.00 {
-		t.Errorf("txn[1].Amount: got %.2f, want 1.00", txn.Amount)
+	if txn.Amount.Float64() != 1.00 {
+		t.Errorf("txn[1].Amount: got %.2f, want 1.00", txn.Amount.Float64())
 	}
 
 	// Page 2 txn 0: Inward Payment (credit, column-separated)
@@ -568,8 +570,8 @@ Money in (£) Balance (£)
 	if txn.Type != "CREDIT" {
 		t.Errorf("txn[2].Type: got %q, want CREDIT", txn.Type)
 	}
-	if txn.Amount != 15995.00 {
-		t.Errorf("txn[2].Amount: got %.2f, want 15995.00", txn.Amount)
+	if txn.Amount.Float64() != 15995.00 {
+		t.Errorf("txn[2].Amount: got %.2f, want 15995.00", txn.Amount.Float64())
 	}
 
 	// Page 2 txn 1: Outward Faster Payment (debit, column-separated)
@@ -577,8 +579,8 @@ Money in (£) Balance (£)
 	if txn.Type != "DEBIT" {
 		t.Errorf("txn[3].Type: got %q, want DEBIT", txn.Type)
 	}
-	if txn.Amount != 744.00 {
-		t.Errorf("txn[3].Amount: got %.2f, want 744.00", txn.Amount)
+	if txn.Amount.Float64() != 744.00 {
+		t.Errorf("txn[3].Amount: got %.2f, want 744.00", txn.Amount.Float64())
 	}
 }
 
@@ -600,7 +602,7 @@ func TestClassifyByBalance(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := classifyByBalance(tt.amt, tt.bal, tt.prevBal, tt.desc)
+			got := classifyByBalance(models.MoneyFromFloat(tt.amt), models.MoneyFromFloat(tt.bal), models.MoneyFromFloat(tt.prevBal), tt.desc)
 			if got != tt.want {
 				t.Errorf("classifyByBalance(%f, %f, %f, %q) = %q, want %q",
 					tt.amt, tt.bal, tt.prevBal, tt.desc, got, tt.want)
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// MonzoParser handles Monzo current account statement PDFs.
+//
+// Monzo statements have this layout:
+//
+//	Date | Description | (GBP) Amount | (GBP) Balance
+//
+// Date format: DD/MM/YYYY. Amounts are a single signed column: money out
+// is negative, money in positive. Descriptions may wrap onto following
+// lines and often contain emoji from pot and merchant names.
+// Example line: "02/01/2024 TESCO STORES 6543 LONDON GBR -25.99 974.01"
+type MonzoParser struct{}
+
+func (p *MonzoParser) BankName() string {
+	return "Monzo"
+}
+
+const signedAmountGroup = `([-+\x{2212}]?[£€$]?[-\x{2212}]?[\d,]+\.\d{2})`
+
+// Monzo transaction line pattern:
+// DATE  DESCRIPTION  SIGNED_AMOUNT  [BALANCE]
+var monzoTxnPattern = regexp.MustCompile(
+	`^(\d{1,2}/\d{1,2}/\d{2,4})\s+(.+?)\s+` + signedAmountGroup + `(?:\s+` + signedAmountGroup + `)?\s*$`,
+)
+
+func (p *MonzoParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankMonzo,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		txns, openBal, closeBal := p.parseLines(lines)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		if !closeBal.IsZero() {
+			info.ClosingBalance = closeBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+	}
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// parseLines parses one page of a Monzo statement. It returns the
+// transactions found and the opening and closing balances printed on the
+// page (0 if absent).
+func (p *MonzoParser) parseLines(lines []string) ([]models.Transaction, models.Money, models.Money) {
+	var transactions []models.Transaction
+	var openingBalance, closingBalance models.Money
+	inTransactionSection := false
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if bal, ok := extractOpeningBalance(line); ok {
+			if openingBalance.IsZero() {
+				openingBalance = bal
+			}
+			continue
+		}
+		if bal, ok := extractClosingBalance(line); ok {
+			closingBalance = bal
+			continue
+		}
+
+		if containsTransactionHeader(line) {
+			inTransactionSection = true
+			continue
+		}
+
+		if isMonzoFooter(line) {
+			continue
+		}
+
+		hasDate := startsWithDate(line)
+		if !inTransactionSection && !hasDate {
+			continue
+		}
+		if hasDate {
+			inTransactionSection = true
+		}
+
+		if m := monzoTxnPattern.FindStringSubmatch(line); m != nil {
+			signed, err := parseSignedAmount(m[3])
+			if err != nil {
+				continue
+			}
+			txn := models.Transaction{
+				Date:        m[1],
+				Description: stripEmoji(m[2]),
+			}
+			applySignedAmount(&txn, signed)
+			if m[4] != "" {
+				if bal, err := parseSignedAmount(m[4]); err == nil {
+					txn.Balance = bal
+				}
+			}
+			transactions = append(transactions, txn)
+			continue
+		}
+
+		// Multi-line description continuation
+		if len(transactions) > 0 && !hasDate && !isSummaryLine(line) {
+			if cont := stripEmoji(line); cont != "" {
+				last := &transactions[len(transactions)-1]
+				last.Description += " " + cont
+			}
+		}
+	}
+
+	return transactions, openingBalance, closingBalance
+}
+
+// isMonzoFooter detects footer/boilerplate lines in Monzo statements.
+func isMonzoFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"monzo bank limited", "registered office", "registered in england",
+		"financial conduct authority", "prudential regulation",
+		"authorised by", "monzo.com", "broadwalk house",
+		"financial services compensation scheme",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
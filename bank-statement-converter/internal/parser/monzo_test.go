@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestMonzoParser_Parse(t *testing.T) {
+	p := &MonzoParser{}
+
+	pages := []string{
+		`Monzo
+Personal Account statement
+Account name: JOHN SMITH
+Sort code 04-00-04 Account number 12345678
+Opening balance 1,000.00
+Date Description (GBP) Amount (GBP) Balance
+02/01/2024 TESCO STORES 6543 LONDON GBR -25.99 974.01
+03/01/2024 🏠 Rent pot -500.00 474.01
+Transfer to pot
+05/01/2024 EMPLOYER LTD SALARY 2,500.00 2,974.01
+06/01/2024 Refund AMAZON +12.49 2,986.50
+07/01/2024 OVERDRAFT TEST −3,000.00 −13.50
+Closing balance -13.50
+Monzo Bank Limited, Broadwalk House, 5 Appold St, London EC2A 2AG`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankMonzo {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankMonzo)
+	}
+	if info.AccountNumber != "12345678" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "12345678")
+	}
+	if info.SortCode != "04-00-04" {
+		t.Errorf("sort code: got %q, want %q", info.SortCode, "04-00-04")
+	}
+	if info.OpeningBalance.Float64() != 1000.00 {
+		t.Errorf("opening balance: got %.2f, want 1000.00", info.OpeningBalance.Float64())
+	}
+	if info.ClosingBalance.Float64() != -13.50 {
+		t.Errorf("closing balance: got %.2f, want -13.50", info.ClosingBalance.Float64())
+	}
+
+	if len(info.Transactions) != 5 {
+		t.Fatalf("transactions: got %d, want 5; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx     int
+		desc    string
+		typ     string
+		amount  float64
+		balance float64
+	}{
+		{0, "TESCO STORES 6543 LONDON GBR", "DEBIT", 25.99, 974.01},
+		{1, "Rent pot Transfer to pot", "DEBIT", 500.00, 474.01},
+		{2, "EMPLOYER LTD SALARY", "CREDIT", 2500.00, 2974.01},
+		{3, "Refund AMAZON", "CREDIT", 12.49, 2986.50},
+		// Unicode minus, balance goes overdrawn
+		{4, "OVERDRAFT TEST", "DEBIT", 3000.00, -13.50},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Description != tt.desc {
+			t.Errorf("txn[%d].Description: got %q, want %q", tt.idx, txn.Description, tt.desc)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+}
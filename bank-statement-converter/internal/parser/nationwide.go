@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// NationwideParser handles Nationwide Building Society current account PDFs.
+//
+// Nationwide statements have this layout:
+//
+//	Date | Description | £Out | £In | £Balance
+//
+// Transactions are grouped into per-month blocks headed by the month and
+// year ("January 2024"); within a block the date is printed as "DD Mon" and
+// only on the first transaction of each day:
+//
+//	January 2024
+//	02 Jan Balance from statement 12 dated 31/12/2023 1,000.00
+//	03 Jan Visa purchase TESCO STORES 25.99 974.01
+//	Contactless Payment COSTA 3.50 970.51
+type NationwideParser struct{}
+
+func (p *NationwideParser) BankName() string {
+	return "Nationwide"
+}
+
+// nationwideMonthHeader matches the month block headings ("January 2024").
+var nationwideMonthHeader = regexp.MustCompile(
+	`(?i)^(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-z]*\s+(\d{4})$`,
+)
+
+func (p *NationwideParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankNationwide,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	// The month block year carries over page breaks
+	year := ""
+	for _, page := range pages {
+		var lines []string
+		lines, year = addNationwideBlockYear(strings.Split(page, "\n"), year)
+		txns, openBal := parseSharedDateLines(lines, nationwideSharedDateLayout)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+	}
+	info.ClosingBalance = lastClosingBalance(info.Transactions, nationwideSharedDateLayout)
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// addNationwideBlockYear rewrites "DD Mon" row dates as "DD Mon YYYY" using
+// the year from the enclosing month block heading, so each transaction keeps
+// a complete date. It returns the rewritten lines and the year in effect at
+// the end of the page.
+func addNationwideBlockYear(lines []string, year string) ([]string, string) {
+	out := make([]string, 0, len(lines))
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if m := nationwideMonthHeader.FindStringSubmatch(line); m != nil {
+			year = m[1]
+			out = append(out, line)
+			continue
+		}
+		if year != "" && extractDate(line) == "" {
+			if sd := extractShortDate(line); sd != "" {
+				line = sd + " " + year + line[len(sd):]
+			}
+		}
+		out = append(out, line)
+	}
+	return out, year
+}
+
+var nationwideSharedDateLayout = sharedDateLayout{
+	isHeader: func(line string) bool {
+		lower := strings.ToLower(line)
+		return strings.Contains(lower, "date") &&
+			(strings.Contains(lower, "£out") || strings.Contains(lower, "£in")) ||
+			containsTransactionHeader(line)
+	},
+	// Dated rows are table rows even when they read like a summary
+	// ("29 Feb Closing balance"), so only undated summary lines are skipped.
+	isSkip: func(line string) bool {
+		return nationwideMonthHeader.MatchString(line) || isNationwideFooter(line) ||
+			(isSummaryLine(line) && !startsWithDate(line))
+	},
+	isBalance: func(desc string) bool {
+		lower := strings.ToLower(desc)
+		return strings.Contains(lower, "balance from statement") ||
+			strings.Contains(lower, "brought forward") ||
+			strings.Contains(lower, "carried forward") ||
+			strings.Contains(lower, "closing balance")
+	},
+	isOpening: func(desc string) bool {
+		lower := strings.ToLower(desc)
+		return strings.Contains(lower, "balance from statement") || strings.Contains(lower, "brought forward")
+	},
+	isClosing: func(desc string) bool {
+		lower := strings.ToLower(desc)
+		return strings.Contains(lower, "carried forward") || strings.Contains(lower, "closing balance")
+	},
+	classify: func(desc string, amount, balance, prevBalance models.Money) string {
+		return classifyWithPrefixes(desc, amount, balance, prevBalance, nationwideDebitTypes, nationwideCreditTypes)
+	},
+}
+
+// nationwideDebitTypes and nationwideCreditTypes are the transaction type
+// prefixes Nationwide prints at the start of each description.
+var nationwideDebitTypes = []string{
+	"visa purchase", "contactless payment", "direct debit", "standing order",
+	"payment to", "transfer to", "atm withdrawal", "cash withdrawal",
+	"bill payment", "debit card", "charge",
+}
+
+var nationwideCreditTypes = []string{
+	"payment from", "transfer from", "bank credit", "interest",
+	"cash deposit", "cheque deposit", "refund", "salary",
+}
+
+// isNationwideFooter detects footer/boilerplate lines in Nationwide statements.
+func isNationwideFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"nationwide building society is authorised", "nationwide house",
+		"pipers way", "financial conduct authority", "prudential regulation",
+		"authorised by", "nationwide.co.uk",
+		"financial services compensation scheme",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestNationwideParser_MultiPageFixture(t *testing.T) {
+	p := &NationwideParser{}
+
+	info, err := p.Parse(loadFixturePages(t, "nationwide_multipage.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.AccountNumber != "87654321" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "87654321")
+	}
+	if info.SortCode != "07-04-36" {
+		t.Errorf("sort code: got %q, want %q", info.SortCode, "07-04-36")
+	}
+	if info.OpeningBalance.Float64() != 1000.00 {
+		t.Errorf("opening balance: got %.2f, want 1000.00", info.OpeningBalance.Float64())
+	}
+	if info.ClosingBalance.Float64() != 3025.51 {
+		t.Errorf("closing balance: got %.2f, want 3025.51 (last page wins)", info.ClosingBalance.Float64())
+	}
+
+	for i, txn := range info.Transactions {
+		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
+	}
+
+	var txns []struct {
+		date, typ       string
+		amount, balance float64
+	}
+	for _, txn := range info.Transactions {
+		if txn.Type == "BALANCE" {
+			continue
+		}
+		txns = append(txns, struct {
+			date, typ       string
+			amount, balance float64
+		}{txn.Date, txn.Type, txn.Amount.Float64(), txn.Balance.Float64()})
+	}
+
+	want := []struct {
+		date, typ       string
+		amount, balance float64
+	}{
+		{"03 Jan 2024", "DEBIT", 25.99, 974.01},
+		// Shares the 03 Jan date printed on the row above
+		{"03 Jan 2024", "DEBIT", 3.50, 970.51},
+		{"15 Jan 2024", "CREDIT", 2000.00, 2970.51},
+		// Year carried from the February block heading on page 2
+		{"02 Feb 2024", "DEBIT", 45.00, 2925.51},
+		// Empty £Out column rendered as 0.00
+		{"02 Feb 2024", "CREDIT", 100.00, 3025.51},
+	}
+
+	if len(txns) != len(want) {
+		t.Fatalf("transactions: got %d, want %d", len(txns), len(want))
+	}
+	for i := range want {
+		if txns[i] != want[i] {
+			t.Errorf("txn[%d]: got %+v, want %+v", i, txns[i], want[i])
+		}
+	}
+}
+
+func TestAddNationwideBlockYear(t *testing.T) {
+	lines, year := addNationwideBlockYear([]string{
+		"03 Jan Visa purchase 1.00 2.00",
+		"December 2023",
+		"30 Dec Visa purchase 1.00 2.00",
+		"Contactless Payment 1.00 1.00",
+		"January 2024",
+		"02 Jan Direct debit 1.00 0.00",
+	}, "")
+
+	want := []string{
+		// No block heading seen yet — left untouched
+		"03 Jan Visa purchase 1.00 2.00",
+		"December 2023",
+		"30 Dec 2023 Visa purchase 1.00 2.00",
+		"Contactless Payment 1.00 1.00",
+		"January 2024",
+		"02 Jan 2024 Direct debit 1.00 0.00",
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if year != "2024" {
+		t.Errorf("year: got %q, want %q", year, "2024")
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// NatWestGroupParser handles statements from the NatWest Group brands
+// (NatWest and Royal Bank of Scotland), which share one statement layout:
+//
+//	Date | Description | Paid In(£) | Withdrawn(£) | Balance(£)
+//
+// Date format: DD Mon YYYY (e.g., 02 JAN 2024) or DD Mon
+// Each page opens with a "BROUGHT FORWARD" row and closes with "CARRIED
+// FORWARD". The date is printed only on the first transaction of each day:
+//
+//	02 JAN 2024 Card Transaction 25.99 1,208.57
+//	1234 02JAN24 TESCO STORES LONDON GB
+//	Direct Debit SKY DIGITAL 45.00 1,163.57
+//
+// Bank selects which brand the parsed statement is reported as.
+type NatWestGroupParser struct {
+	Bank models.BankType
+}
+
+func (p *NatWestGroupParser) BankName() string {
+	if p.Bank == models.BankRBS {
+		return "Royal Bank of Scotland"
+	}
+	return "NatWest"
+}
+
+func (p *NatWestGroupParser) Parse(pages []string) (*models.StatementInfo, error) {
+	bank := p.Bank
+	if bank == "" {
+		bank = models.BankNatWest
+	}
+	info := &models.StatementInfo{
+		Bank: bank,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		txns, openBal := parseSharedDateLines(lines, natwestSharedDateLayout)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+	}
+	info.ClosingBalance = lastClosingBalance(info.Transactions, natwestSharedDateLayout)
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+var natwestSharedDateLayout = sharedDateLayout{
+	isHeader: containsNatWestHeader,
+	isSkip: func(line string) bool {
+		return isNatWestFooter(line) || isSummaryLine(line)
+	},
+	isBalance: func(desc string) bool {
+		lower := strings.ToLower(desc)
+		return strings.Contains(lower, "brought forward") || strings.Contains(lower, "carried forward")
+	},
+	isOpening: func(desc string) bool {
+		return strings.Contains(strings.ToLower(desc), "brought forward")
+	},
+	isClosing: func(desc string) bool {
+		return strings.Contains(strings.ToLower(desc), "carried forward")
+	},
+	classify: classifyNatWest,
+}
+
+// natwestDebitTypes and natwestCreditTypes are the transaction type prefixes
+// NatWest Group prints at the start of each description.
+var natwestDebitTypes = []string{
+	"card transaction", "direct debit", "standing order", "online transaction",
+	"cash withdrawal", "charges", "bill payment", "cheque",
+}
+
+var natwestCreditTypes = []string{
+	"automated credit", "deposit", "interest", "transfer from",
+}
+
+// classifyNatWest decides debit vs credit from the running balance when one
+// is printed, then from the NatWest transaction type prefix.
+func classifyNatWest(desc string, amount, balance, prevBalance models.Money) string {
+	return classifyWithPrefixes(desc, amount, balance, prevBalance, natwestDebitTypes, natwestCreditTypes)
+}
+
+func containsNatWestHeader(line string) bool {
+	lower := strings.ToLower(line)
+	return strings.Contains(lower, "date") &&
+		(strings.Contains(lower, "paid in") || strings.Contains(lower, "withdrawn")) ||
+		containsTransactionHeader(line)
+}
+
+// isNatWestFooter detects footer/boilerplate lines in NatWest and RBS statements.
+func isNatWestFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"national westminster bank plc", "the royal bank of scotland plc",
+		"registered office", "registered in england", "registered in scotland",
+		"financial conduct authority", "prudential regulation",
+		"authorised by", "natwest.com", "rbs.co.uk",
+		"financial services compensation scheme",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
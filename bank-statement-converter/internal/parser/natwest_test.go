@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestNatWestGroupParser_Parse(t *testing.T) {
+	p := &NatWestGroupParser{Bank: models.BankNatWest}
+
+	pages := []string{
+		`NatWest
+Statement
+Account name: MR JOHN SMITH
+Sort Code 60-00-01 Account No 12345678
+Period Covered 01 JAN 2024 to 31 JAN 2024
+
+Date Description Paid In(£) Withdrawn(£) Balance(£)
+01 JAN 2024 BROUGHT FORWARD 1,234.56
+02 JAN 2024 Card Transaction 25.99 1,208.57
+1234 02JAN24 C TESCO STORES LONDON GB
+Direct Debit SKY DIGITAL 45.00 1,163.57
+05 JAN 2024 Automated Credit EMPLOYER LTD SALARY 2,500.00 3,663.57
+OnLine Transaction J DOE RENT 750.00 2,913.57
+31 JAN 2024 CARRIED FORWARD 2,913.57
+National Westminster Bank Plc. Registered Office: 250 Bishopsgate, London EC2M 4AA`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankNatWest {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankNatWest)
+	}
+	if info.AccountNumber != "12345678" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "12345678")
+	}
+	if info.SortCode != "60-00-01" {
+		t.Errorf("sort code: got %q, want %q", info.SortCode, "60-00-01")
+	}
+	if info.OpeningBalance.Float64() != 1234.56 {
+		t.Errorf("opening balance: got %.2f, want 1234.56", info.OpeningBalance.Float64())
+	}
+	if info.StatementPeriod != "01 JAN 2024 to 31 JAN 2024" {
+		t.Errorf("statement period: got %q", info.StatementPeriod)
+	}
+
+	for i, txn := range info.Transactions {
+		t.Logf("  [%d] date=%q desc=%q type=%s amount=%.2f balance=%.2f",
+			i, txn.Date, txn.Description, txn.Type, txn.Amount.Float64(), txn.Balance.Float64())
+	}
+
+	// BROUGHT FORWARD + 4 transactions + CARRIED FORWARD
+	if len(info.Transactions) != 6 {
+		t.Fatalf("transactions: got %d, want 6", len(info.Transactions))
+	}
+
+	tests := []struct {
+		idx     int
+		date    string
+		typ     string
+		amount  float64
+		balance float64
+	}{
+		{0, "01 JAN 2024", "BALANCE", 0, 1234.56},
+		{1, "02 JAN 2024", "DEBIT", 25.99, 1208.57},
+		// Inherits the date from the previous row
+		{2, "02 JAN 2024", "DEBIT", 45.00, 1163.57},
+		{3, "05 JAN 2024", "CREDIT", 2500.00, 3663.57},
+		{4, "05 JAN 2024", "DEBIT", 750.00, 2913.57},
+		{5, "31 JAN 2024", "BALANCE", 0, 2913.57},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Date != tt.date {
+			t.Errorf("txn[%d].Date: got %q, want %q", tt.idx, txn.Date, tt.date)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+
+	if !strings.Contains(info.Transactions[1].Description, "TESCO STORES") {
+		t.Errorf("txn[1] should include continuation line: got %q", info.Transactions[1].Description)
+	}
+	if strings.Contains(info.Transactions[1].Description, "02 JAN 2024") {
+		t.Errorf("txn[1] description should not include the date: got %q", info.Transactions[1].Description)
+	}
+}
+
+func TestNatWestGroupParser_RBS(t *testing.T) {
+	p := &NatWestGroupParser{Bank: models.BankRBS}
+
+	if p.BankName() != "Royal Bank of Scotland" {
+		t.Errorf("bank name: got %q, want %q", p.BankName(), "Royal Bank of Scotland")
+	}
+
+	// Short "D Mon" dates, and a day whose balance is only printed on the
+	// last row — the first row falls back to the transaction type prefix.
+	pages := []string{
+		`The Royal Bank of Scotland
+Date Description Paid In(£) Withdrawn(£) Balance(£)
+3 Feb BROUGHT FORWARD 500.00
+4 Feb Cash Withdrawal RBS HIGH ST 20.00
+Automated Credit HMRC 100.00 580.00`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankRBS {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankRBS)
+	}
+	if info.OpeningBalance.Float64() != 500.00 {
+		t.Errorf("opening balance: got %.2f, want 500.00", info.OpeningBalance.Float64())
+	}
+	if len(info.Transactions) != 3 {
+		t.Fatalf("transactions: got %d, want 3; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+	if txn := info.Transactions[1]; txn.Type != "DEBIT" || txn.Amount.Float64() != 20.00 || txn.Date != "4 Feb" {
+		t.Errorf("txn[1]: got %s %.2f %q, want DEBIT 20.00 \"4 Feb\"", txn.Type, txn.Amount.Float64(), txn.Date)
+	}
+	if txn := info.Transactions[2]; txn.Type != "CREDIT" || txn.Amount.Float64() != 100.00 || txn.Date != "4 Feb" {
+		t.Errorf("txn[2]: got %s %.2f %q, want CREDIT 100.00 \"4 Feb\"", txn.Type, txn.Amount.Float64(), txn.Date)
+	}
+}
+
+func TestNatWestGroupParser_MultiPage(t *testing.T) {
+	p := &NatWestGroupParser{Bank: models.BankNatWest}
+
+	pages := []string{
+		`Date Description Paid In(£) Withdrawn(£) Balance(£)
+01 MAR 2024 BROUGHT FORWARD 100.00
+02 MAR 2024 Card Transaction COSTA 3.50 96.50
+02 MAR 2024 CARRIED FORWARD 96.50`,
+		`Date Description Paid In(£) Withdrawn(£) Balance(£)
+02 MAR 2024 BROUGHT FORWARD 96.50
+03 MAR 2024 Automated Credit CLIENT 200.00 296.50`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.OpeningBalance.Float64() != 100.00 {
+		t.Errorf("opening balance: got %.2f, want 100.00 (page 2 must not override)", info.OpeningBalance.Float64())
+	}
+	if !info.ClosingBalance.IsZero() {
+		t.Errorf("closing balance: got %.2f, want 0 (page 1 carried forward is not the statement close)", info.ClosingBalance.Float64())
+	}
+
+	var real []string
+	for _, txn := range info.Transactions {
+		if txn.Type != "BALANCE" {
+			real = append(real, txn.Type)
+		}
+	}
+	if strings.Join(real, ",") != "DEBIT,CREDIT" {
+		t.Errorf("transaction types: got %v, want [DEBIT CREDIT]", real)
+	}
+}
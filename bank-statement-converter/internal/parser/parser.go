@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/insightdelivered/bank-statement-converter/internal/models"
 )
@@ -23,30 +24,84 @@ func New(bankType models.BankType) (Parser, error) {
 		return &HSBCParser{}, nil
 	case models.BankBarclays:
 		return &BarclaysParser{}, nil
+	case models.BankLloyds:
+		return &LloydsParser{}, nil
+	case models.BankNatWest, models.BankRBS:
+		return &NatWestGroupParser{Bank: bankType}, nil
+	case models.BankSantander:
+		return &SantanderParser{}, nil
+	case models.BankNationwide:
+		return &NationwideParser{}, nil
+	case models.BankMonzo:
+		return &MonzoParser{}, nil
+	case models.BankStarling:
+		return &StarlingParser{}, nil
+	case models.BankRevolut:
+		return &RevolutParser{}, nil
+	case models.BankAmex:
+		return &AmexParser{}, nil
+	case models.BankBarclaycard:
+		return &BarclaycardParser{}, nil
 	default:
+		if t := findTemplate(bankType); t != nil {
+			return &TemplateParser{Template: t}, nil
+		}
 		return nil, fmt.Errorf("unsupported bank type: %q", bankType)
 	}
 }
 
-// AutoDetect tries to identify the bank from the PDF text content.
-func AutoDetect(pages []string) (models.BankType, error) {
-	combined := ""
-	for _, p := range pages {
-		combined += p + "\n"
-	}
+// builtinBanks lists the banks with hand-written parsers, in the order they
+// are presented to users.
+var builtinBanks = []models.BankType{
+	models.BankMetro, models.BankHSBC, models.BankBarclays, models.BankLloyds,
+	models.BankNatWest, models.BankRBS, models.BankSantander, models.BankNationwide,
+	models.BankMonzo, models.BankStarling, models.BankRevolut,
+	models.BankAmex, models.BankBarclaycard,
+}
 
-	// Check for bank-specific identifiers
-	if containsAny(combined, []string{"Metro Bank", "METRO BANK", "metrobankonline"}) {
-		return models.BankMetro, nil
+// bankAliases are alternative spellings accepted for built-in banks.
+var bankAliases = map[string]models.BankType{
+	"metrobank":           models.BankMetro,
+	"lloydsbank":          models.BankLloyds,
+	"royalbankofscotland": models.BankRBS,
+}
+
+// LookupBank resolves a user-supplied bank name (from --bank or the API
+// "bank" field) to a BankType, including banks loaded from templates.
+func LookupBank(name string) (models.BankType, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if bt, ok := bankAliases[name]; ok {
+		return bt, true
 	}
-	if containsAny(combined, []string{"HSBC", "hsbc.co.uk", "HSBC UK Bank"}) {
-		return models.BankHSBC, nil
+	for _, bt := range builtinBanks {
+		if string(bt) == name {
+			return bt, true
+		}
 	}
-	if containsAny(combined, []string{"Barclays", "BARCLAYS", "barclays.co.uk"}) {
-		return models.BankBarclays, nil
+	if t := findTemplate(models.BankType(name)); t != nil {
+		return models.BankType(t.Bank), true
 	}
+	return "", false
+}
+
+// SupportedBanks returns the identifiers of every supported bank: the
+// built-in parsers followed by registered templates.
+func SupportedBanks() []string {
+	var names []string
+	for _, bt := range builtinBanks {
+		names = append(names, string(bt))
+	}
+	for _, t := range registeredTemplates() {
+		names = append(names, t.Bank)
+	}
+	return names
+}
 
-	return "", fmt.Errorf("could not auto-detect bank from statement content; please specify --bank flag")
+// AutoDetect identifies the bank from the PDF text content, returning the
+// best-ranked candidate from RankBanks.
+func AutoDetect(pages []string) (models.BankType, error) {
+	bank, _, err := DetectBank(pages, false)
+	return bank, err
 }
 
 func containsAny(text string, needles []string) bool {
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/insightdelivered/bank-statement-converter/internal/models"
@@ -28,6 +31,66 @@ func TestAutoDetect(t *testing.T) {
 			pages:    []string{"Barclays Bank UK PLC\nStatement\n15/01/2024"},
 			expected: models.BankBarclays,
 		},
+		{
+			name:     "detects Lloyds Bank",
+			pages:    []string{"Lloyds Bank\nYour Account\n15 Jan 24"},
+			expected: models.BankLloyds,
+		},
+		{
+			name:     "detects NatWest",
+			pages:    []string{"NatWest\nStatement\n02 JAN 2024"},
+			expected: models.BankNatWest,
+		},
+		{
+			name:     "detects RBS even when NatWest Group is mentioned",
+			pages:    []string{"The Royal Bank of Scotland\nPart of NatWest Group\n02 JAN 2024"},
+			expected: models.BankRBS,
+		},
+		{
+			name:     "detects Santander",
+			pages:    []string{"Santander\nYour Everyday Current Account\n3rd Jan"},
+			expected: models.BankSantander,
+		},
+		{
+			name:     "detects Nationwide",
+			pages:    []string{"Nationwide Building Society\nFlexAccount\n03 Jan"},
+			expected: models.BankNationwide,
+		},
+		{
+			name:     "detects Monzo",
+			pages:    []string{"Monzo Bank Limited\nPersonal Account statement\n02/01/2024"},
+			expected: models.BankMonzo,
+		},
+		{
+			name:     "detects Starling",
+			pages:    []string{"Starling Bank Limited\nPersonal Account\n02/01/2024"},
+			expected: models.BankStarling,
+		},
+		{
+			name:     "detects Revolut",
+			pages:    []string{"GBP Statement\nRevolut Ltd\n2 Jan 2024"},
+			expected: models.BankRevolut,
+		},
+		{
+			name:     "app bank payee does not override high-street bank",
+			pages:    []string{"Santander\n3rd Jan FASTER PAYMENTS PAYMENT TO MONZO 50.00 950.00"},
+			expected: models.BankSantander,
+		},
+		{
+			name:     "detects American Express",
+			pages:    []string{"Card Member MR J SMITH\nAmerican Express Services Europe Limited"},
+			expected: models.BankAmex,
+		},
+		{
+			name:     "detects Barclaycard before Barclays",
+			pages:    []string{"Barclaycard Platinum\nBarclaycard is a trading name of Barclays Bank UK PLC"},
+			expected: models.BankBarclaycard,
+		},
+		{
+			name:     "Amex direct debit does not make a current account a card statement",
+			pages:    []string{"Barclays\n02/01/2024 Direct Debit AMERICAN EXPRESS 500.00 1,000.00"},
+			expected: models.BankBarclays,
+		},
 		{
 			name:    "unknown bank returns error",
 			pages:   []string{"Some Unknown Bank\nStatement"},
@@ -63,6 +126,16 @@ func TestNew(t *testing.T) {
 		{models.BankMetro, "Metro Bank", false},
 		{models.BankHSBC, "HSBC", false},
 		{models.BankBarclays, "Barclays", false},
+		{models.BankLloyds, "Lloyds Bank", false},
+		{models.BankNatWest, "NatWest", false},
+		{models.BankRBS, "Royal Bank of Scotland", false},
+		{models.BankSantander, "Santander", false},
+		{models.BankNationwide, "Nationwide", false},
+		{models.BankMonzo, "Monzo", false},
+		{models.BankStarling, "Starling Bank", false},
+		{models.BankRevolut, "Revolut", false},
+		{models.BankAmex, "American Express", false},
+		{models.BankBarclaycard, "Barclaycard", false},
 		{"unknown", "", true},
 	}
 
@@ -84,3 +157,14 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+// loadFixturePages reads an extracted-text statement from testdata and splits
+// it into pages on the same separator the web UI uses for /api/convert.
+func loadFixturePages(t *testing.T, name string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return strings.Split(string(data), "\n---PAGE_BREAK---\n")
+}
@@ -0,0 +1,235 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// RevolutParser handles Revolut personal account statement PDFs.
+//
+// A Revolut statement covers every currency pocket on the account, one
+// section per currency, each with its own balance summary and table:
+//
+//	EUR Statement
+//	Product Opening balance Money out Money in Closing balance
+//	Account (Current Account) €100.00 €25.99 €0.00 €74.01
+//	Date | Description | Money out | Money in | Balance
+//	2 Jan 2024 Card payment CAFE DE FLORE €25.99 €74.01
+//
+// Date format: D Mon YYYY. Amounts carry the pocket's currency symbol.
+// Each transaction is tagged with its pocket currency and balances are
+// tracked per pocket.
+type RevolutParser struct{}
+
+func (p *RevolutParser) BankName() string {
+	return "Revolut"
+}
+
+// revolutSectionHeader matches the per-currency section headings
+// ("GBP Statement", "EUR Statement").
+var revolutSectionHeader = regexp.MustCompile(`^([A-Z]{3})\s+(?i:statement|pocket)\b`)
+
+// Revolut transaction line pattern:
+// DATE  DESCRIPTION  [MONEY_OUT]  [MONEY_IN]  [BALANCE]
+var revolutTxnPattern = regexp.MustCompile(
+	`^(\d{1,2}\s+(?i:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-zA-Z]*\s+\d{4})\s+(.+?)((?:\s+` + signedAmountGroup + `){1,3})\s*$`,
+)
+
+// revolutCurrencySymbols maps the symbols Revolut prints on amounts to
+// ISO 4217 codes, for statements without section headings.
+var revolutCurrencySymbols = map[string]string{
+	"£": "GBP",
+	"€": "EUR",
+	"$": "USD",
+}
+
+func (p *RevolutParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankRevolut,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	state := &revolutState{balances: make(map[string]models.Money)}
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		info.Transactions = append(info.Transactions, p.parseLines(lines, state)...)
+	}
+
+	// The statement-level balances are those of the first (base currency)
+	// pocket; the others are reported per transaction.
+	info.OpeningBalance = state.opening
+	info.ClosingBalance = state.closing
+	info.PrintedTotalDebit = state.moneyOut
+	info.PrintedTotalCredit = state.moneyIn
+
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// revolutState carries the current pocket and per-pocket running balances
+// across pages.
+type revolutState struct {
+	currency     string
+	balances     map[string]models.Money
+	baseCurrency string
+	opening      models.Money
+	closing      models.Money
+	moneyOut     models.Money
+	moneyIn      models.Money
+	inSummary    bool
+}
+
+func (p *RevolutParser) parseLines(lines []string, st *revolutState) []models.Transaction {
+	var transactions []models.Transaction
+	// Continuation lines only belong to a transaction directly above them,
+	// never across a section heading or summary table.
+	canContinue := false
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if m := revolutSectionHeader.FindStringSubmatch(line); m != nil {
+			st.currency = m[1]
+			st.inSummary = false
+			canContinue = false
+			continue
+		}
+
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "opening balance") && strings.Contains(lower, "closing balance") {
+			st.inSummary = true
+			canContinue = false
+			continue
+		}
+
+		if containsTransactionHeader(line) {
+			st.inSummary = false
+			canContinue = false
+			continue
+		}
+
+		if isRevolutFooter(line) {
+			continue
+		}
+
+		// Balance summary row: opening, money out, money in, closing
+		if st.inSummary && !startsWithDate(line) {
+			amounts := signedAmountPattern.FindAllString(line, -1)
+			if len(amounts) >= 4 {
+				opening, _ := parseSignedAmount(amounts[0])
+				closing, _ := parseSignedAmount(amounts[len(amounts)-1])
+				currency := p.currencyFor(st, amounts[0])
+				st.balances[currency] = opening
+				if st.baseCurrency == "" {
+					st.baseCurrency = currency
+				}
+				if currency == st.baseCurrency {
+					st.opening = opening
+					st.closing = closing
+					moneyOut, _ := parseSignedAmount(amounts[1])
+					moneyIn, _ := parseSignedAmount(amounts[2])
+					st.moneyOut, st.moneyIn = moneyOut.Abs(), moneyIn.Abs()
+				}
+				st.inSummary = false
+			}
+			continue
+		}
+
+		if m := revolutTxnPattern.FindStringSubmatch(line); m != nil {
+			amountText := signedAmountPattern.FindAllString(m[3], -1)
+			currency := p.currencyFor(st, amountText[0])
+			txn := models.Transaction{
+				Date:        m[1],
+				Description: stripEmoji(m[2]),
+				Currency:    currency,
+			}
+			amounts := parseSignedAmounts(m[3])
+			// Money out comes before money in, the reverse of assignInOutAmounts.
+			if len(amounts) == 3 {
+				amounts[0], amounts[1] = amounts[1], amounts[0]
+			}
+			assignInOutAmounts(&txn, amounts)
+			txn.Amount, txn.Balance = txn.Amount.In(currency), txn.Balance.In(currency)
+			if txn.Type == "" {
+				txn.Type = classifyWithPrefixes(txn.Description, txn.Amount, txn.Balance, st.balances[currency],
+					revolutDebitTypes, revolutCreditTypes)
+			}
+			if !txn.Balance.IsZero() {
+				st.balances[currency] = txn.Balance
+			}
+			if st.baseCurrency == "" {
+				st.baseCurrency = currency
+			}
+			transactions = append(transactions, txn)
+			canContinue = true
+			continue
+		}
+
+		// Multi-line description continuation ("To: ...", "Reference: ...")
+		if canContinue && len(transactions) > 0 && !startsWithDate(line) && !isSummaryLine(line) {
+			if cont := stripEmoji(line); cont != "" {
+				last := &transactions[len(transactions)-1]
+				last.Description += " " + cont
+			}
+		}
+	}
+
+	return transactions
+}
+
+// currencyFor returns the pocket currency for an amount: the enclosing
+// section's currency when there is one, else the amount's symbol, else GBP.
+func (p *RevolutParser) currencyFor(st *revolutState, amount string) string {
+	if st.currency != "" {
+		return st.currency
+	}
+	for sym, code := range revolutCurrencySymbols {
+		if strings.Contains(amount, sym) {
+			return code
+		}
+	}
+	return "GBP"
+}
+
+// revolutDebitTypes and revolutCreditTypes are the description prefixes
+// Revolut uses. Currency exchanges appear in both pockets with the same
+// wording, so they are left to the per-pocket running balance.
+var revolutDebitTypes = []string{
+	"card payment", "to ", "transfer to", "cash withdrawal", "atm",
+	"fee", "subscription",
+}
+
+var revolutCreditTypes = []string{
+	"payment from", "top-up", "top up", "transfer from", "refund",
+	"interest", "cashback",
+}
+
+// isRevolutFooter detects footer/boilerplate lines in Revolut statements.
+func isRevolutFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"revolut ltd", "revolut bank uab", "registered office",
+		"registered in england", "financial conduct authority",
+		"prudential regulation", "authorised by", "revolut.com",
+		"canada square", "report lost or stolen card",
+		"financial services compensation scheme",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
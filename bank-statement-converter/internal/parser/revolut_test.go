@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestRevolutParser_Parse(t *testing.T) {
+	p := &RevolutParser{}
+
+	pages := []string{
+		`GBP Statement
+Generated on the 1 Feb 2024
+Revolut Ltd
+Balance summary
+Product Opening balance Money out Money in Closing balance
+Account (Current Account) £1,000.00 £125.99 £2,500.00 £3,374.01
+Account transactions from 1 January 2024 to 31 January 2024
+Date Description Money out Money in Balance
+2 Jan 2024 Card payment TESCO STORES £25.99 £974.01
+3 Jan 2024 Payment from ACME LTD £2,500.00 £3,474.01
+Reference: SALARY
+4 Jan 2024 Exchanged to EUR £100.00 £3,374.01`,
+		`EUR Statement
+Balance summary
+Product Opening balance Money out Money in Closing balance
+Account (Current Account) €0.00 €4.50 €115.00 €110.50
+Date Description Money out Money in Balance
+4 Jan 2024 Exchanged to EUR €115.00 €115.00
+6 Jan 2024 Card payment CAFE DE FLORE €4.50 €110.50`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankRevolut {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankRevolut)
+	}
+	// Statement balances are those of the base (first) pocket
+	if info.OpeningBalance.Float64() != 1000.00 {
+		t.Errorf("opening balance: got %.2f, want 1000.00", info.OpeningBalance.Float64())
+	}
+	if info.ClosingBalance.Float64() != 3374.01 {
+		t.Errorf("closing balance: got %.2f, want 3374.01", info.ClosingBalance.Float64())
+	}
+
+	if len(info.Transactions) != 5 {
+		t.Fatalf("transactions: got %d, want 5; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx      int
+		currency string
+		typ      string
+		amount   float64
+		balance  float64
+	}{
+		{0, "GBP", "DEBIT", 25.99, 974.01},
+		{1, "GBP", "CREDIT", 2500.00, 3474.01},
+		// The same exchange is a debit in one pocket and a credit in the other
+		{2, "GBP", "DEBIT", 100.00, 3374.01},
+		{3, "EUR", "CREDIT", 115.00, 115.00},
+		{4, "EUR", "DEBIT", 4.50, 110.50},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Currency != tt.currency {
+			t.Errorf("txn[%d].Currency: got %q, want %q", tt.idx, txn.Currency, tt.currency)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+
+	if got := info.Transactions[1].Description; got != "Payment from ACME LTD Reference: SALARY" {
+		t.Errorf("txn[1].Description: got %q", got)
+	}
+}
@@ -0,0 +1,174 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// SantanderParser handles Santander UK statement PDFs.
+//
+// Santander statements have this layout:
+//
+//	Date | Description | Money in | Money out | Balance
+//
+// Date format: ordinal day + month on personal statements (e.g., 3rd Jan),
+// DD/MM/YYYY on business statements.
+// Example line: "3rd Jan CARD PAYMENT TO TESCO STORES 25.99 974.01"
+type SantanderParser struct{}
+
+func (p *SantanderParser) BankName() string {
+	return "Santander"
+}
+
+const santanderDateGroup = `(\d{1,2}(?:st|nd|rd|th)\s+(?i:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)[a-zA-Z]*(?:\s+\d{4})?|\d{1,2}/\d{1,2}/\d{2,4})`
+
+// Santander transaction line pattern:
+// DATE  DESCRIPTION  [MONEY_IN]  [MONEY_OUT]  [BALANCE]
+var santanderTxnPattern = regexp.MustCompile(
+	`^` + santanderDateGroup + `\s+(.+?)((?:\s+£?[\d,]+\.\d{2}){1,3})\s*$`,
+)
+
+var santanderDateStart = regexp.MustCompile(`^` + santanderDateGroup + `(?:\s|$)`)
+
+func (p *SantanderParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankSantander,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	var lastBalance models.Money
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		txns, openBal, closeBal, newBalance := p.parseLines(lines, lastBalance)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		if !closeBal.IsZero() {
+			info.ClosingBalance = closeBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+		if !newBalance.IsZero() {
+			lastBalance = newBalance
+		}
+	}
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// parseLines parses one page of a Santander statement. It returns the
+// transactions found, the opening and closing balances printed on the page
+// (0 if absent) and the last running balance for the next page.
+func (p *SantanderParser) parseLines(lines []string, initialBalance models.Money) ([]models.Transaction, models.Money, models.Money, models.Money) {
+	var transactions []models.Transaction
+	var openingBalance, closingBalance models.Money
+	inTransactionSection := false
+	lastBalance := initialBalance
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		// "Balance brought forward" appears in the summary box and as the
+		// first table row; both carry the same figure.
+		if bal, ok := extractOpeningBalance(line); ok {
+			if openingBalance.IsZero() {
+				openingBalance = bal
+			}
+			lastBalance = bal
+			continue
+		}
+		if bal, ok := extractClosingBalance(line); ok {
+			closingBalance = bal
+			continue
+		}
+
+		if containsTransactionHeader(line) {
+			inTransactionSection = true
+			continue
+		}
+
+		if isSantanderFooter(line) {
+			continue
+		}
+
+		hasDate := santanderDateStart.MatchString(line)
+		if !inTransactionSection && !hasDate {
+			continue
+		}
+		if hasDate {
+			inTransactionSection = true
+		}
+
+		if m := santanderTxnPattern.FindStringSubmatch(line); m != nil {
+			txn := models.Transaction{
+				Date:        m[1],
+				Description: cleanDescription(m[2]),
+			}
+			assignInOutAmounts(&txn, parseAmounts(m[3]))
+			if txn.Type == "" {
+				txn.Type = classifySantander(txn.Description, txn.Amount, txn.Balance, lastBalance)
+			}
+			if !txn.Balance.IsZero() {
+				lastBalance = txn.Balance
+			}
+			transactions = append(transactions, txn)
+			continue
+		}
+
+		// Multi-line description continuation
+		if len(transactions) > 0 && !hasDate && !isSummaryLine(line) {
+			last := &transactions[len(transactions)-1]
+			last.Description += " " + line
+		}
+	}
+
+	return transactions, openingBalance, closingBalance, lastBalance
+}
+
+// santanderDebitTypes and santanderCreditTypes are the transaction type
+// prefixes Santander prints at the start of each description. Generic
+// keyword matching gets "BILL PAYMENT VIA FASTER PAYMENT TO" wrong because
+// of the "faster payment" credit keyword.
+var santanderDebitTypes = []string{
+	"card payment", "direct debit", "standing order", "bill payment",
+	"transfer to", "cash withdrawal", "cheque paid", "faster payments payment",
+}
+
+var santanderCreditTypes = []string{
+	"faster payments receipt", "bank giro credit", "transfer from",
+	"credit from", "interest paid", "cash deposit", "cheque deposit",
+}
+
+func classifySantander(desc string, amount, balance, prevBalance models.Money) string {
+	return classifyWithPrefixes(desc, amount, balance, prevBalance, santanderDebitTypes, santanderCreditTypes)
+}
+
+// isSantanderFooter detects footer/boilerplate lines in Santander statements.
+func isSantanderFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"santander uk plc", "registered office", "registered in england",
+		"financial conduct authority", "prudential regulation",
+		"authorised by", "triton square", "santander.co.uk",
+		"financial services compensation scheme",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
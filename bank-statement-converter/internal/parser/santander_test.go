@@ -0,0 +1,100 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSantanderParser_MultiPageFixture(t *testing.T) {
+	p := &SantanderParser{}
+
+	info, err := p.Parse(loadFixturePages(t, "santander_multipage.txt"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.AccountNumber != "12345678" {
+		t.Errorf("account number: got %q, want %q", info.AccountNumber, "12345678")
+	}
+	if info.SortCode != "09-01-28" {
+		t.Errorf("sort code: got %q, want %q", info.SortCode, "09-01-28")
+	}
+	if info.StatementPeriod != "01/01/2024 to 31/01/2024" {
+		t.Errorf("statement period: got %q", info.StatementPeriod)
+	}
+	if info.OpeningBalance.Float64() != 1000.00 {
+		t.Errorf("opening balance: got %.2f, want 1000.00", info.OpeningBalance.Float64())
+	}
+	if info.ClosingBalance.Float64() != 2724.01 {
+		t.Errorf("closing balance: got %.2f, want 2724.01", info.ClosingBalance.Float64())
+	}
+
+	if len(info.Transactions) != 5 {
+		t.Fatalf("transactions: got %d, want 5; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx     int
+		date    string
+		typ     string
+		amount  float64
+		balance float64
+	}{
+		{0, "3rd Jan", "DEBIT", 25.99, 974.01},
+		// "VIA FASTER PAYMENT" must not read as a credit
+		{1, "5th Jan", "DEBIT", 750.00, 224.01},
+		// First row of page 2 classified from page 1's running balance
+		{2, "15th Jan", "CREDIT", 2500.00, 2724.01},
+		{3, "20th Jan", "DEBIT", 100.00, 2624.01},
+		{4, "28th Jan", "CREDIT", 100.00, 2724.01},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Date != tt.date {
+			t.Errorf("txn[%d].Date: got %q, want %q", tt.idx, txn.Date, tt.date)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+
+	if !strings.Contains(info.Transactions[1].Description, "MANDATE NO 12") {
+		t.Errorf("txn[1] should include continuation line: got %q", info.Transactions[1].Description)
+	}
+	if strings.Contains(info.Transactions[1].Description, "Triton Square") {
+		t.Errorf("txn[1] should not include footer: got %q", info.Transactions[1].Description)
+	}
+}
+
+func TestSantanderParser_NoBalanceFallback(t *testing.T) {
+	p := &SantanderParser{}
+
+	// Balance column missing: the Santander type prefix decides
+	pages := []string{
+		`Date Description Money in Money out Balance
+02/03/2024 FASTER PAYMENTS RECEIPT REF INVOICE 12 300.00
+03/03/2024 BILL PAYMENT VIA FASTER PAYMENT TO SUPPLIER 80.00`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(info.Transactions) != 2 {
+		t.Fatalf("transactions: got %d, want 2", len(info.Transactions))
+	}
+	if info.Transactions[0].Type != "CREDIT" {
+		t.Errorf("txn[0].Type: got %q, want CREDIT", info.Transactions[0].Type)
+	}
+	if info.Transactions[1].Type != "DEBIT" {
+		t.Errorf("txn[1].Type: got %q, want DEBIT", info.Transactions[1].Type)
+	}
+}
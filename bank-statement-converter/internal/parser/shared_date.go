@@ -0,0 +1,224 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// sharedDateLayout describes a statement table where the date is printed
+// only on the first transaction of each day and later rows inherit it:
+//
+//	4 Dec Start Balance 9,856.68
+//	On-Line Banking Bill Payment to 400.00 9,456.68
+//	Ref: Inv 1
+//	5 Dec Direct Debit to Stripe 58.80 9,397.88
+//
+// The walking logic is shared; each bank supplies the hooks that recognise
+// its header, boilerplate and balance rows and decide debit vs credit.
+type sharedDateLayout struct {
+	// isHeader reports whether a line is the table column header.
+	isHeader func(line string) bool
+	// isSkip reports whether a line is boilerplate (footer, summary, sidebar).
+	isSkip func(line string) bool
+	// isDetail reports whether a line carries extra detail for the previous
+	// transaction even though it contains amounts (e.g. FX rates). Optional.
+	isDetail func(line string) bool
+	// isBalance reports whether a description is a balance row rather than
+	// a transaction; isOpening and isClosing narrow that to the first/last row.
+	isBalance func(desc string) bool
+	isOpening func(desc string) bool
+	isClosing func(desc string) bool
+	// classify returns "DEBIT" or "CREDIT" for a transaction. prevBalance is
+	// the last running balance seen, or 0 when unknown.
+	classify func(desc string, amount, balance, prevBalance models.Money) string
+}
+
+// parseSharedDateLines walks one page of a shared-date statement and returns
+// its transactions (balance rows are emitted with Type "BALANCE") and the
+// opening balance, if the page has one.
+func parseSharedDateLines(lines []string, layout sharedDateLayout) ([]models.Transaction, models.Money) {
+	var transactions []models.Transaction
+	var openingBalance, lastBalance models.Money
+	inTransactionSection := false
+	currentDate := ""
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if layout.isHeader(line) {
+			inTransactionSection = true
+			continue
+		}
+
+		if layout.isSkip(line) {
+			continue
+		}
+
+		if layout.isDetail != nil && layout.isDetail(line) {
+			if len(transactions) > 0 {
+				last := &transactions[len(transactions)-1]
+				last.Description += " " + line
+			}
+			continue
+		}
+
+		// A full date ("02 JAN 2024") takes precedence over the short
+		// "D Mon" form, which would otherwise match its first two fields.
+		datePrefix := extractDate(line)
+		if datePrefix == "" {
+			datePrefix = extractShortDate(line)
+		}
+		if datePrefix != "" {
+			currentDate = datePrefix
+			inTransactionSection = true
+		}
+
+		if !inTransactionSection {
+			continue
+		}
+
+		// Try to parse as a transaction (line has trailing amounts)
+		txn := parseSharedDateTransactionLine(line, datePrefix, currentDate, lastBalance, layout)
+		if txn != nil {
+			if txn.Type == "BALANCE" && layout.isOpening(txn.Description) && openingBalance.IsZero() {
+				openingBalance = txn.Balance
+			}
+			if !txn.Balance.IsZero() {
+				lastBalance = txn.Balance
+			}
+			transactions = append(transactions, *txn)
+			// The closing balance row is always the last item on a page.
+			if txn.Type == "BALANCE" && layout.isClosing(txn.Description) {
+				return transactions, openingBalance
+			}
+			continue
+		}
+
+		// No amounts found — continuation line.
+		// Never append to BALANCE transactions — their descriptions are
+		// always single-line ("Start Balance", "Balance carried forward").
+		if len(transactions) > 0 {
+			last := &transactions[len(transactions)-1]
+			if last.Type == "BALANCE" {
+				continue
+			}
+			cleanLine := line
+			if datePrefix != "" {
+				cleanLine = strings.TrimSpace(strings.TrimPrefix(cleanLine, datePrefix))
+			}
+			if cleanLine != "" && !layout.isSkip(cleanLine) {
+				last.Description += " " + cleanLine
+			}
+		}
+	}
+
+	return transactions, openingBalance
+}
+
+// lastClosingBalance returns the balance of the closing BALANCE row that
+// ends txns, or zero if txns does not end with one.
+func lastClosingBalance(txns []models.Transaction, layout sharedDateLayout) models.Money {
+	for i := len(txns) - 1; i >= 0; i-- {
+		if txns[i].Type != "BALANCE" {
+			// Transactions after the last carried-forward row (a
+			// continuation page without one): the closing balance is unknown
+			return models.Money{}
+		}
+		if layout.isClosing(txns[i].Description) {
+			return txns[i].Balance
+		}
+	}
+	return models.Money{}
+}
+
+// parseSharedDateTransactionLine parses a space/tab-separated line as a
+// transaction.  Returns nil if the line has no monetary amounts.
+func parseSharedDateTransactionLine(line, datePrefix, currentDate string, prevBalance models.Money, layout sharedDateLayout) *models.Transaction {
+	rest := line
+	if datePrefix != "" {
+		idx := strings.Index(rest, datePrefix)
+		if idx >= 0 {
+			rest = strings.TrimSpace(rest[idx+len(datePrefix):])
+		}
+	}
+
+	if rest == "" {
+		return nil
+	}
+
+	allLocs := amountPattern.FindAllStringIndex(rest, -1)
+	if len(allLocs) == 0 {
+		return nil
+	}
+
+	desc := strings.TrimSpace(rest[:allLocs[0][0]])
+	if desc == "" {
+		return nil
+	}
+
+	var amounts []models.Money
+	for _, loc := range allLocs {
+		a, err := parseAmount(rest[loc[0]:loc[1]])
+		if err == nil {
+			amounts = append(amounts, a)
+		}
+	}
+
+	if len(amounts) == 0 {
+		return nil
+	}
+
+	txn := &models.Transaction{
+		Date:        currentDate,
+		Description: cleanDescription(desc),
+	}
+
+	if layout.isBalance(desc) {
+		txn.Balance = amounts[len(amounts)-1]
+		txn.Type = "BALANCE"
+		return txn
+	}
+
+	if len(amounts) >= 2 {
+		txn.Amount = amounts[0]
+		txn.Balance = amounts[len(amounts)-1]
+		// Both money columns present with the empty one rendered as 0.00
+		if txn.Amount.IsZero() && len(amounts) >= 3 {
+			txn.Amount = amounts[1]
+		}
+	} else {
+		txn.Amount = amounts[0]
+	}
+
+	txn.Type = layout.classify(txn.Description, txn.Amount, txn.Balance, prevBalance)
+
+	return txn
+}
+
+// classifyWithPrefixes decides debit vs credit from the running balance when
+// it reconciles, then from the transaction type prefix the bank prints at the
+// start of each description, and finally from generic description keywords.
+func classifyWithPrefixes(desc string, amount, balance, prevBalance models.Money, debitPrefixes, creditPrefixes []string) string {
+	if !balance.IsZero() && !prevBalance.IsZero() {
+		if prevBalance.Sub(amount).Equal(balance) || prevBalance.Add(amount).Equal(balance) {
+			return classifyByBalance(amount, balance, prevBalance, desc)
+		}
+	}
+
+	lower := strings.ToLower(desc)
+	for _, prefix := range debitPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return "DEBIT"
+		}
+	}
+	for _, prefix := range creditPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return "CREDIT"
+		}
+	}
+	return classifyByBalance(amount, models.Money{}, models.Money{}, desc)
+}
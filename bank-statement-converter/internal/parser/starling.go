@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// StarlingParser handles Starling Bank statement PDFs.
+//
+// Starling statements have this layout:
+//
+//	Date | Type | Transaction | In | Out | Account Balance
+//
+// Date format: DD/MM/YYYY. The Type column (CONTACTLESS, FASTER PAYMENT,
+// DIRECT DEBIT, ...) starts the description. Only one of In/Out is printed
+// per row; the balance is signed and goes negative in overdraft.
+// Example line: "02/01/2024 CONTACTLESS TESCO STORES £25.99 £974.01"
+type StarlingParser struct{}
+
+func (p *StarlingParser) BankName() string {
+	return "Starling Bank"
+}
+
+// Starling transaction line pattern:
+// DATE  TYPE DESCRIPTION  [IN]  [OUT]  [BALANCE]
+var starlingTxnPattern = regexp.MustCompile(
+	`^(\d{1,2}/\d{1,2}/\d{2,4})\s+(.+?)((?:\s+` + signedAmountGroup + `){1,3})\s*$`,
+)
+
+func (p *StarlingParser) Parse(pages []string) (*models.StatementInfo, error) {
+	info := &models.StatementInfo{
+		Bank: models.BankStarling,
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	var lastBalance models.Money
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		txns, openBal, closeBal, newBalance := p.parseLines(lines, lastBalance)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		if !closeBal.IsZero() {
+			info.ClosingBalance = closeBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+		if !newBalance.IsZero() {
+			lastBalance = newBalance
+		}
+	}
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info)
+
+	return info, nil
+}
+
+// parseLines parses one page of a Starling statement. It returns the
+// transactions found, the opening and closing balances printed on the page
+// (0 if absent) and the last running balance for the next page.
+func (p *StarlingParser) parseLines(lines []string, initialBalance models.Money) ([]models.Transaction, models.Money, models.Money, models.Money) {
+	var transactions []models.Transaction
+	var openingBalance, closingBalance models.Money
+	inTransactionSection := false
+	lastBalance := initialBalance
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if bal, ok := extractOpeningBalance(line); ok {
+			if openingBalance.IsZero() {
+				openingBalance = bal
+			}
+			lastBalance = bal
+			continue
+		}
+		if bal, ok := extractClosingBalance(line); ok {
+			closingBalance = bal
+			continue
+		}
+
+		if containsTransactionHeader(line) || containsStarlingHeader(line) {
+			inTransactionSection = true
+			continue
+		}
+
+		if isStarlingFooter(line) {
+			continue
+		}
+
+		hasDate := startsWithDate(line)
+		if !inTransactionSection && !hasDate {
+			continue
+		}
+		if hasDate {
+			inTransactionSection = true
+		}
+
+		if m := starlingTxnPattern.FindStringSubmatch(line); m != nil {
+			txn := models.Transaction{
+				Date:        m[1],
+				Description: stripEmoji(m[2]),
+			}
+			assignInOutAmounts(&txn, parseSignedAmounts(m[3]))
+			if txn.Type == "" {
+				txn.Type = classifyWithPrefixes(txn.Description, txn.Amount, txn.Balance, lastBalance,
+					starlingDebitTypes, starlingCreditTypes)
+			}
+			if !txn.Balance.IsZero() {
+				lastBalance = txn.Balance
+			}
+			transactions = append(transactions, txn)
+			continue
+		}
+
+		// Multi-line description continuation
+		if len(transactions) > 0 && !hasDate && !isSummaryLine(line) {
+			if cont := stripEmoji(line); cont != "" {
+				last := &transactions[len(transactions)-1]
+				last.Description += " " + cont
+			}
+		}
+	}
+
+	return transactions, openingBalance, closingBalance, lastBalance
+}
+
+// starlingDebitTypes and starlingCreditTypes are values of Starling's Type
+// column. FASTER PAYMENT is used in both directions, so it is left to the
+// running balance.
+var starlingDebitTypes = []string{
+	"contactless", "chip & pin", "chip and pin", "online payment", "card subscription",
+	"direct debit", "standing order", "apple pay", "google pay", "magstripe",
+	"cash withdrawal", "atm", "fee",
+}
+
+var starlingCreditTypes = []string{
+	"deposit interest", "interest", "cash deposit", "cheque deposit", "refund",
+}
+
+// containsStarlingHeader matches Starling's column header, which names the
+// money columns "In" and "Out" rather than using the generic wording.
+func containsStarlingHeader(line string) bool {
+	upper := strings.ToUpper(line)
+	return strings.Contains(upper, "DATE") && strings.Contains(upper, " IN ") &&
+		strings.Contains(upper, " OUT ") && strings.Contains(upper, "BALANCE")
+}
+
+// isStarlingFooter detects footer/boilerplate lines in Starling statements.
+func isStarlingFooter(line string) bool {
+	lower := strings.ToLower(line)
+	footerKeywords := []string{
+		"starling bank limited", "registered office", "registered in england",
+		"financial conduct authority", "prudential regulation",
+		"authorised by", "starlingbank.com", "finsbury avenue",
+		"financial services compensation scheme",
+	}
+	for _, kw := range footerKeywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestStarlingParser_Parse(t *testing.T) {
+	p := &StarlingParser{}
+
+	pages := []string{
+		`Starling Bank
+Personal Current Account
+Sort code 60-83-71 Account number 12345678
+Opening Balance £1,000.00
+DATE TYPE TRANSACTION IN OUT ACCOUNT BALANCE
+02/01/2024 CONTACTLESS TESCO STORES £25.99 £974.01
+03/01/2024 FASTER PAYMENT JANE DOE £50.00 £1,024.01
+Dinner split
+04/01/2024 FASTER PAYMENT LANDLORD LTD £1,100.00 -£75.99
+05/01/2024 DIRECT DEBIT SKY DIGITAL £45.00
+Starling Bank Limited is registered in England and Wales`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != models.BankStarling {
+		t.Errorf("bank: got %q, want %q", info.Bank, models.BankStarling)
+	}
+	if info.SortCode != "60-83-71" {
+		t.Errorf("sort code: got %q, want %q", info.SortCode, "60-83-71")
+	}
+	if info.OpeningBalance.Float64() != 1000.00 {
+		t.Errorf("opening balance: got %.2f, want 1000.00", info.OpeningBalance.Float64())
+	}
+
+	if len(info.Transactions) != 4 {
+		t.Fatalf("transactions: got %d, want 4; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx     int
+		typ     string
+		amount  float64
+		balance float64
+	}{
+		{0, "DEBIT", 25.99, 974.01},
+		// FASTER PAYMENT goes both ways; the running balance decides
+		{1, "CREDIT", 50.00, 1024.01},
+		{2, "DEBIT", 1100.00, -75.99},
+		// No balance printed — falls back to the Type column
+		{3, "DEBIT", 45.00, 0},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+
+	if got := info.Transactions[1].Description; got != "FASTER PAYMENT JANE DOE Dinner split" {
+		t.Errorf("txn[1].Description: got %q", got)
+	}
+}
@@ -0,0 +1,428 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// Template describes a bank statement layout declaratively, so small banks
+// and building societies can be supported without a hand-written parser.
+// Templates are JSON files loaded at startup with LoadTemplates:
+//
+//	{
+//	  "bank": "coventry",
+//	  "name": "Coventry Building Society",
+//	  "detect": ["Coventry Building Society", "coventrybuildingsociety.co.uk"],
+//	  "headerMarkers": ["Date Description Paid out Paid in Balance"],
+//	  "dateFormats": ["02/01/2006", "2 Jan 2006"],
+//	  "columns": ["out", "in", "balance"],
+//	  "skipLines": ["Coventry Building Society is authorised"],
+//	  "openingBalance": ["Balance brought forward"],
+//	  "closingBalance": ["Balance carried forward"]
+//	}
+//
+// All marker lists are matched case-insensitively as substrings.
+type Template struct {
+	// Bank is the identifier used with --bank and the API "bank" field.
+	Bank string `json:"bank"`
+	// Name is the human-readable bank name.
+	Name string `json:"name"`
+	// Detect lists keywords that identify the bank's statements in AutoDetect.
+	Detect []string `json:"detect"`
+	// HeaderMarkers identify the transaction table's column header line.
+	HeaderMarkers []string `json:"headerMarkers"`
+	// DateFormats are Go reference layouts ("02/01/2006", "2 Jan") a
+	// transaction line may start with, most specific first.
+	DateFormats []string `json:"dateFormats"`
+	// Columns is the order of the money columns after the description:
+	// "in", "out", "balance", or "amount" for a single signed column.
+	Columns []string `json:"columns"`
+	// SkipLines identify footer and boilerplate lines.
+	SkipLines []string `json:"skipLines"`
+	// Continuation is "append" (default) to add undated lines without
+	// amounts to the previous description, or "ignore" to drop them.
+	Continuation string `json:"continuation"`
+	// SharedDate is set when the date is printed only on the first
+	// transaction of each day and later rows inherit it.
+	SharedDate bool `json:"sharedDate"`
+	// OpeningBalance and ClosingBalance identify the balance lines; the
+	// last amount on the line is taken as the balance.
+	OpeningBalance []string `json:"openingBalance"`
+	ClosingBalance []string `json:"closingBalance"`
+	// DebitKeywords and CreditKeywords are description prefixes used to
+	// classify rows whose money column cannot be told from the balance.
+	DebitKeywords  []string `json:"debitKeywords"`
+	CreditKeywords []string `json:"creditKeywords"`
+
+	datePattern *regexp.Regexp
+}
+
+var (
+	templatesMu sync.RWMutex
+	templates   []*Template
+)
+
+// LoadTemplates reads every *.json file in dir as a Template and registers
+// it. It returns the loaded templates in file name order.
+func LoadTemplates(dir string) ([]*Template, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates in %q: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	var loaded []*Template
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %q: %w", path, err)
+		}
+		var t Template
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("invalid template %q: %w", path, err)
+		}
+		if err := RegisterTemplate(&t); err != nil {
+			return nil, fmt.Errorf("invalid template %q: %w", path, err)
+		}
+		loaded = append(loaded, &t)
+	}
+	return loaded, nil
+}
+
+// RegisterTemplate validates t and makes it available to New, AutoDetect and
+// LookupBank alongside the built-in parsers.
+func RegisterTemplate(t *Template) error {
+	t.Bank = strings.ToLower(strings.TrimSpace(t.Bank))
+	if t.Bank == "" {
+		return fmt.Errorf("template has no \"bank\" identifier")
+	}
+	if _, ok := LookupBank(t.Bank); ok {
+		return fmt.Errorf("bank %q is already registered", t.Bank)
+	}
+	if t.Name == "" {
+		t.Name = t.Bank
+	}
+	if len(t.DateFormats) == 0 {
+		return fmt.Errorf("template %q has no dateFormats", t.Bank)
+	}
+	if err := validateTemplateColumns(t.Columns); err != nil {
+		return fmt.Errorf("template %q: %w", t.Bank, err)
+	}
+	switch t.Continuation {
+	case "", "append", "ignore":
+	default:
+		return fmt.Errorf("template %q: continuation must be \"append\" or \"ignore\", got %q", t.Bank, t.Continuation)
+	}
+
+	var alternatives []string
+	for _, layout := range t.DateFormats {
+		alternatives = append(alternatives, layoutPattern(layout))
+	}
+	datePattern, err := regexp.Compile(`^(` + strings.Join(alternatives, "|") + `)(?:\s|$)`)
+	if err != nil {
+		return fmt.Errorf("template %q: invalid dateFormats: %w", t.Bank, err)
+	}
+	t.datePattern = datePattern
+
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates = append(templates, t)
+	return nil
+}
+
+// unregisterTemplate removes a registered template. Used by tests.
+func unregisterTemplate(bank string) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	for i, t := range templates {
+		if t.Bank == bank {
+			templates = append(templates[:i], templates[i+1:]...)
+			return
+		}
+	}
+}
+
+// findTemplate returns the registered template for bank, or nil.
+func findTemplate(bank models.BankType) *Template {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+	for _, t := range templates {
+		if t.Bank == string(bank) {
+			return t
+		}
+	}
+	return nil
+}
+
+// registeredTemplates returns a snapshot of the registered templates.
+func registeredTemplates() []*Template {
+	templatesMu.RLock()
+	defer templatesMu.RUnlock()
+	return append([]*Template(nil), templates...)
+}
+
+func validateTemplateColumns(columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns")
+	}
+	hasMoney := false
+	for _, c := range columns {
+		switch c {
+		case "in", "out", "amount":
+			hasMoney = true
+		case "balance":
+		default:
+			return fmt.Errorf("unknown column %q (want in, out, amount or balance)", c)
+		}
+	}
+	if !hasMoney {
+		return fmt.Errorf("columns need at least one of in, out or amount")
+	}
+	return nil
+}
+
+// layoutTokens maps Go reference layout elements to regular expressions.
+// Longer elements come first so "2006" is not read as "2" + "006".
+var layoutTokens = []struct{ token, pattern string }{
+	{"January", `(?i:January|February|March|April|May|June|July|August|September|October|November|December)`},
+	{"Jan", `(?i:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sept?|Oct|Nov|Dec)`},
+	{"2006", `\d{4}`},
+	{"_2", `\s?\d{1,2}`},
+	{"01", `\d{2}`},
+	{"02", `\d{2}`},
+	{"06", `\d{2}`},
+	{"1", `\d{1,2}`},
+	{"2", `\d{1,2}`},
+}
+
+// layoutPattern converts a Go reference date layout into a regular
+// expression matching dates printed in that layout.
+func layoutPattern(layout string) string {
+	var b strings.Builder
+	for i := 0; i < len(layout); {
+		matched := false
+		for _, lt := range layoutTokens {
+			if strings.HasPrefix(layout[i:], lt.token) {
+				b.WriteString(lt.pattern)
+				i += len(lt.token)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		if layout[i] == ' ' {
+			b.WriteString(`\s+`)
+		} else {
+			b.WriteString(regexp.QuoteMeta(layout[i : i+1]))
+		}
+		i++
+	}
+	return b.String()
+}
+
+// TemplateParser parses statements described by a Template.
+type TemplateParser struct {
+	Template *Template
+}
+
+func (p *TemplateParser) BankName() string {
+	return p.Template.Name
+}
+
+func (p *TemplateParser) Parse(pages []string) (*models.StatementInfo, error) {
+	t := p.Template
+	info := &models.StatementInfo{
+		Bank: models.BankType(t.Bank),
+	}
+
+	allText := strings.Join(pages, "\n")
+
+	info.AccountNumber = findAccountNumber(allText)
+	info.SortCode = findSortCode(allText)
+	info.AccountHolder = extractNameNearLabel(allText, []string{"Account holder", "Account name", "Mr ", "Mrs ", "Ms ", "Miss "})
+	info.StatementPeriod = extractPeriod(allText)
+
+	var lastBalance models.Money
+	currentDate := ""
+	for _, page := range pages {
+		lines := strings.Split(page, "\n")
+		var txns []models.Transaction
+		var openBal, closeBal models.Money
+		txns, openBal, closeBal, lastBalance, currentDate = p.parseLines(lines, lastBalance, currentDate)
+		if info.OpeningBalance.IsZero() && !openBal.IsZero() {
+			info.OpeningBalance = openBal
+		}
+		if !closeBal.IsZero() {
+			info.ClosingBalance = closeBal
+		}
+		info.Transactions = append(info.Transactions, txns...)
+	}
+
+	extractPrintedTotals(allText, info)
+	resolveDates(allText, info, t.DateFormats...)
+
+	return info, nil
+}
+
+// parseLines parses one page. It returns the transactions found, the opening
+// and closing balances printed on the page (0 if absent), and the running
+// balance and current date to carry to the next page.
+func (p *TemplateParser) parseLines(lines []string, lastBalance models.Money, currentDate string) ([]models.Transaction, models.Money, models.Money, models.Money, string) {
+	t := p.Template
+	var transactions []models.Transaction
+	var openingBalance, closingBalance models.Money
+	inTransactionSection := false
+
+	for i := 0; i < len(lines); i++ {
+		line := normalizeLine(lines[i])
+		if line == "" {
+			continue
+		}
+
+		if containsAny(line, t.OpeningBalance) {
+			if bal, ok := lastSignedAmount(line); ok {
+				if openingBalance.IsZero() {
+					openingBalance = bal
+				}
+				lastBalance = bal
+			}
+			continue
+		}
+		if containsAny(line, t.ClosingBalance) {
+			if bal, ok := lastSignedAmount(line); ok {
+				closingBalance = bal
+			}
+			continue
+		}
+
+		if containsAny(line, t.HeaderMarkers) {
+			inTransactionSection = true
+			continue
+		}
+
+		if containsAny(line, t.SkipLines) {
+			continue
+		}
+
+		rest := line
+		date := ""
+		if m := t.datePattern.FindStringSubmatch(line); m != nil {
+			date = m[1]
+			rest = strings.TrimSpace(line[len(m[1]):])
+			currentDate = date
+			inTransactionSection = true
+		}
+		if !inTransactionSection {
+			continue
+		}
+
+		locs := signedAmountPattern.FindAllStringIndex(rest, -1)
+		if len(locs) > 0 && (date != "" || t.SharedDate && currentDate != "") {
+			desc := strings.TrimSpace(rest[:locs[0][0]])
+			if desc != "" {
+				var amounts []models.Money
+				for _, loc := range locs {
+					if a, err := parseSignedAmount(rest[loc[0]:loc[1]]); err == nil {
+						amounts = append(amounts, a)
+					}
+				}
+				txn := models.Transaction{
+					Date:        currentDate,
+					Description: cleanDescription(desc),
+					ParseMethod: "template-" + t.Bank,
+				}
+				p.assignColumns(&txn, amounts)
+				if txn.Type == "" {
+					txn.Type = classifyWithPrefixes(txn.Description, txn.Amount, txn.Balance, lastBalance,
+						lowerAll(t.DebitKeywords), lowerAll(t.CreditKeywords))
+				}
+				if !txn.Balance.IsZero() {
+					lastBalance = txn.Balance
+				}
+				transactions = append(transactions, txn)
+				continue
+			}
+		}
+
+		// Continuation line
+		if t.Continuation != "ignore" && len(transactions) > 0 && date == "" && !isSummaryLine(line) {
+			last := &transactions[len(transactions)-1]
+			last.Description += " " + line
+		}
+	}
+
+	return transactions, openingBalance, closingBalance, lastBalance, currentDate
+}
+
+// assignColumns maps the amounts found on a line onto the template's money
+// columns. When every column is printed the mapping is direct; otherwise the
+// last amount is the balance (if the layout has one) and the first is the
+// transaction amount, leaving Type to be classified.
+func (p *TemplateParser) assignColumns(txn *models.Transaction, amounts []models.Money) {
+	columns := p.Template.Columns
+	if len(amounts) == len(columns) {
+		for i, col := range columns {
+			a := amounts[i]
+			switch col {
+			case "balance":
+				txn.Balance = a
+			case "amount":
+				applySignedAmount(txn, a)
+			case "in":
+				if !a.IsZero() {
+					txn.Amount, txn.Type = a.Abs(), "CREDIT"
+				}
+			case "out":
+				if !a.IsZero() {
+					txn.Amount, txn.Type = a.Abs(), "DEBIT"
+				}
+			}
+		}
+		return
+	}
+
+	hasBalance := false
+	for _, col := range columns {
+		if col == "balance" {
+			hasBalance = true
+		}
+	}
+	if hasBalance && len(amounts) >= 2 {
+		txn.Balance = amounts[len(amounts)-1]
+	}
+	amount := amounts[0]
+	for _, col := range columns {
+		if col == "amount" {
+			applySignedAmount(txn, amount)
+			return
+		}
+	}
+	txn.Amount = amount.Abs()
+}
+
+// lastSignedAmount returns the last amount on a line.
+func lastSignedAmount(line string) (models.Money, bool) {
+	amounts := parseSignedAmounts(line)
+	if len(amounts) == 0 {
+		return models.Money{}, false
+	}
+	return amounts[len(amounts)-1], true
+}
+
+func lowerAll(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = strings.ToLower(s)
+	}
+	return out
+}
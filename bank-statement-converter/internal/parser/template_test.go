@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// loadTestTemplates registers the templates in testdata/templates for the
+// duration of a test.
+func loadTestTemplates(t *testing.T) {
+	t.Helper()
+	loaded, err := LoadTemplates(filepath.Join("testdata", "templates"))
+	if err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, tmpl := range loaded {
+			unregisterTemplate(tmpl.Bank)
+		}
+	})
+}
+
+func TestLoadTemplates(t *testing.T) {
+	loadTestTemplates(t)
+
+	bt, ok := LookupBank("Coventry")
+	if !ok || bt != "coventry" {
+		t.Fatalf("LookupBank(Coventry): got %q, %v", bt, ok)
+	}
+
+	supported := strings.Join(SupportedBanks(), ",")
+	if !strings.HasSuffix(supported, "coventry,teachers") {
+		t.Errorf("SupportedBanks should end with the templates: got %s", supported)
+	}
+
+	p, err := New("teachers")
+	if err != nil {
+		t.Fatalf("New(teachers): %v", err)
+	}
+	if p.BankName() != "Teachers Building Society" {
+		t.Errorf("BankName: got %q", p.BankName())
+	}
+
+	detected, err := AutoDetect([]string{"Coventry Building Society\nStatement"})
+	if err != nil || detected != "coventry" {
+		t.Errorf("AutoDetect: got %q, %v; want coventry", detected, err)
+	}
+
+	// Built-in banks still win over templates
+	detected, _ = AutoDetect([]string{"HSBC UK Bank\nTransfer to Coventry Building Society"})
+	if detected != models.BankHSBC {
+		t.Errorf("AutoDetect: got %q, want %q", detected, models.BankHSBC)
+	}
+
+	// Loading the same directory again clashes with the registered banks
+	if _, err := LoadTemplates(filepath.Join("testdata", "templates")); err == nil {
+		t.Error("expected an error registering a duplicate bank")
+	}
+}
+
+func TestRegisterTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl Template
+		want string
+	}{
+		{"missing bank", Template{DateFormats: []string{"02/01/2006"}, Columns: []string{"amount"}}, "no \"bank\""},
+		{"built-in clash", Template{Bank: "HSBC", DateFormats: []string{"02/01/2006"}, Columns: []string{"amount"}}, "already registered"},
+		{"no date formats", Template{Bank: "x1", Columns: []string{"amount"}}, "no dateFormats"},
+		{"unknown column", Template{Bank: "x2", DateFormats: []string{"02/01/2006"}, Columns: []string{"debit"}}, "unknown column"},
+		{"balance only", Template{Bank: "x3", DateFormats: []string{"02/01/2006"}, Columns: []string{"balance"}}, "at least one"},
+		{"bad continuation", Template{Bank: "x4", DateFormats: []string{"02/01/2006"}, Columns: []string{"amount"}, Continuation: "merge"}, "continuation"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := tt.tmpl
+			err := RegisterTemplate(&tmpl)
+			if err == nil {
+				unregisterTemplate(tmpl.Bank)
+				t.Fatal("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error: got %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayoutPattern(t *testing.T) {
+	tests := []struct {
+		layout string
+		match  string
+		reject string
+	}{
+		{"02/01/2006", "05/01/2024", "5 Jan 2024"},
+		{"2 Jan 2006", "5 Jan 2024", "05/01/2024"},
+		{"2 January 2006", "15 September 2024", "15 Sep 2024"},
+		{"02-Jan-06", "05-Jan-24", "05-01-24"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			re := regexp.MustCompile(`^` + layoutPattern(tt.layout) + `$`)
+			if !re.MatchString(tt.match) {
+				t.Errorf("%q should match %q", tt.layout, tt.match)
+			}
+			if re.MatchString(tt.reject) {
+				t.Errorf("%q should not match %q", tt.layout, tt.reject)
+			}
+		})
+	}
+}
+
+func TestTemplateParser_Parse(t *testing.T) {
+	loadTestTemplates(t)
+
+	p, err := New("coventry")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	pages := []string{
+		`Coventry Building Society
+Account name: MR JOHN SMITH
+Sort code 40-63-01 Account number 12345678
+Date Description Paid out Paid in Balance
+Balance brought forward 1,000.00
+02/01/2024 Card payment TESCO 25.99 974.01
+Ref 1234
+5 Jan 2024 Transfer from SAVINGS 0.00 200.00 1,174.01
+06/01/2024 MISC 74.01 1,100.00
+Coventry Building Society is authorised by the Prudential Regulation Authority`,
+		`Date Description Paid out Paid in Balance
+07/01/2024 Interest 1.00
+Balance carried forward 1,101.00`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if info.Bank != "coventry" {
+		t.Errorf("bank: got %q, want coventry", info.Bank)
+	}
+	if info.SortCode != "40-63-01" {
+		t.Errorf("sort code: got %q", info.SortCode)
+	}
+	if info.OpeningBalance.Float64() != 1000.00 || info.ClosingBalance.Float64() != 1101.00 {
+		t.Errorf("balances: got %.2f/%.2f, want 1000.00/1101.00", info.OpeningBalance.Float64(), info.ClosingBalance.Float64())
+	}
+
+	if len(info.Transactions) != 4 {
+		t.Fatalf("transactions: got %d, want 4; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+
+	tests := []struct {
+		idx     int
+		date    string
+		desc    string
+		typ     string
+		amount  float64
+		balance float64
+	}{
+		{0, "02/01/2024", "Card payment TESCO Ref 1234", "DEBIT", 25.99, 974.01},
+		// All three columns printed: mapped directly
+		{1, "5 Jan 2024", "Transfer from SAVINGS", "CREDIT", 200.00, 1174.01},
+		// Running balance decides when only one money column is printed
+		{2, "06/01/2024", "MISC", "DEBIT", 74.01, 1100.00},
+		// No balance: falls back to the credit keywords
+		{3, "07/01/2024", "Interest", "CREDIT", 1.00, 0},
+	}
+
+	for _, tt := range tests {
+		txn := info.Transactions[tt.idx]
+		if txn.Date != tt.date {
+			t.Errorf("txn[%d].Date: got %q, want %q", tt.idx, txn.Date, tt.date)
+		}
+		if txn.Description != tt.desc {
+			t.Errorf("txn[%d].Description: got %q, want %q", tt.idx, txn.Description, tt.desc)
+		}
+		if txn.Type != tt.typ {
+			t.Errorf("txn[%d].Type: got %q, want %q", tt.idx, txn.Type, tt.typ)
+		}
+		if txn.Amount.Float64() != tt.amount {
+			t.Errorf("txn[%d].Amount: got %.2f, want %.2f", tt.idx, txn.Amount.Float64(), tt.amount)
+		}
+		if txn.Balance.Float64() != tt.balance {
+			t.Errorf("txn[%d].Balance: got %.2f, want %.2f", tt.idx, txn.Balance.Float64(), tt.balance)
+		}
+	}
+}
+
+func TestTemplateParser_SharedDate(t *testing.T) {
+	loadTestTemplates(t)
+
+	p, err := New("teachers")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	pages := []string{
+		`Teachers Building Society
+3 Feb SALARY 1,500.00 2,000.00
+RENT -750.00 1,250.00
+ignored continuation
+4 Feb COFFEE -3.20 1,246.80`,
+	}
+
+	info, err := p.Parse(pages)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(info.Transactions) != 3 {
+		t.Fatalf("transactions: got %d, want 3; parsed: %+v", len(info.Transactions), info.Transactions)
+	}
+	if txn := info.Transactions[1]; txn.Date != "3 Feb" || txn.Type != "DEBIT" || txn.Amount.Float64() != 750.00 || txn.Description != "RENT" {
+		t.Errorf("txn[1]: got %+v, want 3 Feb RENT DEBIT 750.00", txn)
+	}
+	if txn := info.Transactions[0]; txn.Type != "CREDIT" || txn.Balance.Float64() != 2000.00 {
+		t.Errorf("txn[0]: got %+v, want CREDIT with balance 2000.00", txn)
+	}
+}
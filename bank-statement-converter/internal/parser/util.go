@@ -2,8 +2,10 @@ package parser
 
 import (
 	"regexp"
-	"strconv"
 	"strings"
+	"unicode"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
 )
 
 // Common date patterns found in UK bank statements.
@@ -18,8 +20,8 @@ var (
 	datePatternShort = regexp.MustCompile(`(?i)^(\d{1,2}\s+(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec))(?:\s|→|$)`)
 )
 
-// parseAmount converts a string like "1,234.56" or "-£1,234.56" to a float64.
-func parseAmount(s string) (float64, error) {
+// parseAmount converts a string like "1,234.56" or "-£1,234.56" to Money.
+func parseAmount(s string) (models.Money, error) {
 	s = strings.TrimSpace(s)
 	// Remove currency symbols and whitespace (including Unicode variants)
 	s = strings.ReplaceAll(s, "£", "")
@@ -32,10 +34,10 @@ func parseAmount(s string) (float64, error) {
 	s = strings.ReplaceAll(s, "\u00A0", "") // non-breaking space
 
 	if s == "" || s == "-" {
-		return 0, nil
+		return models.Money{}, nil
 	}
 
-	return strconv.ParseFloat(s, 64)
+	return models.ParseMoney(s)
 }
 
 // startsWithDate checks if a line begins with a date pattern.
@@ -122,3 +124,144 @@ func findSortCode(text string) string {
 	m := sortCodePattern.FindString(text)
 	return m
 }
+
+// parseAmounts parses every amount in s (e.g. the trailing columns of a
+// transaction row), skipping any that fail to parse.
+func parseAmounts(s string) []models.Money {
+	var amounts []models.Money
+	for _, a := range amountPattern.FindAllString(s, -1) {
+		amt, err := parseAmount(a)
+		if err == nil {
+			amounts = append(amounts, amt)
+		}
+	}
+	return amounts
+}
+
+// assignInOutAmounts fills Amount, Balance and (when unambiguous) Type from
+// the trailing amounts of a "Money in | Money out | Balance" row. Empty
+// columns are dropped by text extraction, so one amount is the transaction
+// amount alone, two are amount + balance, and three are in, out, balance.
+// Type is left empty when the row alone cannot tell debit from credit.
+func assignInOutAmounts(txn *models.Transaction, amounts []models.Money) {
+	switch len(amounts) {
+	case 1:
+		txn.Amount = amounts[0]
+	case 2:
+		txn.Amount = amounts[0]
+		txn.Balance = amounts[1]
+	case 3:
+		// Both money columns present — a non-zero Money In is unambiguous
+		txn.Balance = amounts[2]
+		if !amounts[0].IsZero() {
+			txn.Amount = amounts[0]
+			txn.Type = "CREDIT"
+		} else {
+			txn.Amount = amounts[1]
+			txn.Type = "DEBIT"
+		}
+	}
+}
+
+// extractClosingBalance looks for closing/carried-forward balance lines
+// and returns the balance amount. Returns (0, false) if not found.
+func extractClosingBalance(line string) (models.Money, bool) {
+	lower := strings.ToLower(line)
+	if !strings.Contains(lower, "closing balance") &&
+		!strings.Contains(lower, "carried forward") &&
+		!strings.Contains(lower, "end balance") {
+		return models.Money{}, false
+	}
+
+	amounts := parseSignedAmounts(line)
+	if len(amounts) == 0 {
+		return models.Money{}, false
+	}
+	return amounts[len(amounts)-1], true
+}
+
+// printedTotalDebitLabels and printedTotalCreditLabels are the labels banks
+// use for the statement's printed money-out and money-in totals.
+var (
+	printedTotalDebitLabels  = []string{"total payments", "total paid out", "total money out", "total outgoings", "total withdrawals"}
+	printedTotalCreditLabels = []string{"total receipts", "total paid in", "total money in", "total incomings", "total deposits"}
+)
+
+// extractPrintedTotals records the statement's printed debit and credit
+// totals. Barclays prints both on one "Total Payments/Receipts" line,
+// payments first; other banks print one labelled line each. When totals are
+// repeated per page, the last (statement) total wins.
+func extractPrintedTotals(text string, info *models.StatementInfo) {
+	for _, raw := range strings.Split(text, "\n") {
+		line := normalizeLine(raw)
+		lower := strings.ToLower(line)
+		amounts := parseSignedAmounts(line)
+		if len(amounts) == 0 {
+			continue
+		}
+
+		switch {
+		case strings.Contains(lower, "total payments/receipts") || strings.Contains(lower, "total paid out/paid in"):
+			if len(amounts) >= 2 {
+				info.PrintedTotalDebit = amounts[len(amounts)-2].Abs()
+				info.PrintedTotalCredit = amounts[len(amounts)-1].Abs()
+			}
+		case containsAny(line, printedTotalDebitLabels):
+			info.PrintedTotalDebit = amounts[len(amounts)-1].Abs()
+		case containsAny(line, printedTotalCreditLabels):
+			info.PrintedTotalCredit = amounts[len(amounts)-1].Abs()
+		}
+	}
+}
+
+// signedAmountPattern matches amounts that may carry a sign before or after
+// the currency symbol, as used by app-based banks: "-25.99", "+£2,500.00",
+// "£-4.50", "−€10.00" (Unicode minus).
+var signedAmountPattern = regexp.MustCompile(`[-+\x{2212}]?[£€$]?[-\x{2212}]?[\d,]+\.\d{2}`)
+
+// parseSignedAmount parses an amount matched by signedAmountPattern,
+// keeping its sign.
+func parseSignedAmount(s string) (models.Money, error) {
+	return parseAmount(strings.ReplaceAll(s, "\u2212", "-"))
+}
+
+// parseSignedAmounts parses every signed amount in s, in order.
+func parseSignedAmounts(s string) []models.Money {
+	var amounts []models.Money
+	for _, a := range signedAmountPattern.FindAllString(s, -1) {
+		amt, err := parseSignedAmount(a)
+		if err == nil {
+			amounts = append(amounts, amt)
+		}
+	}
+	return amounts
+}
+
+// applySignedAmount maps a signed single-column amount onto Type/Amount:
+// negative amounts are money out, positive amounts money in.
+func applySignedAmount(txn *models.Transaction, signed models.Money) {
+	if signed.IsNegative() {
+		txn.Type = "DEBIT"
+		txn.Amount = signed.Neg()
+	} else {
+		txn.Type = "CREDIT"
+		txn.Amount = signed
+	}
+}
+
+// stripEmoji removes emoji and other pictographic symbols that app-based
+// banks put in merchant and pot names, so descriptions stay plain text in
+// CSV output. Currency symbols are kept.
+func stripEmoji(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.Is(unicode.So, r),
+			r == '\u200D',                  // zero-width joiner
+			r >= '\uFE00' && r <= '\uFE0F', // variation selectors
+			r >= 0x1F3FB && r <= 0x1F3FF:   // skin tone modifiers
+			return -1
+		}
+		return r
+	}, s)
+	return cleanDescription(s)
+}
@@ -2,22 +2,27 @@ package parser
 
 import (
 	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
 )
 
 func TestParseAmount(t *testing.T) {
 	tests := []struct {
 		input    string
-		expected float64
+		expected string
 		wantErr  bool
 	}{
-		{"25.99", 25.99, false},
-		{"1,234.56", 1234.56, false},
-		{"£25.99", 25.99, false},
-		{"-25.99", -25.99, false},
-		{"£1,234,567.89", 1234567.89, false},
-		{"0.00", 0.00, false},
-		{"", 0, false},
-		{" 25.99 ", 25.99, false},
+		{"25.99", "25.99", false},
+		{"1,234.56", "1234.56", false},
+		{"£25.99", "25.99", false},
+		{"-25.99", "-25.99", false},
+		{"£1,234,567.89", "1234567.89", false},
+		{"0.00", "0.00", false},
+		{"", "0.00", false},
+		{" 25.99 ", "25.99", false},
+		{"0.1", "0.10", false},
+		{"12.345", "", true},
+		{"n/a", "", true},
 	}
 
 	for _, tt := range tests {
@@ -32,8 +37,8 @@ func TestParseAmount(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if got != tt.expected {
-				t.Errorf("got %f, want %f", got, tt.expected)
+			if got.String() != tt.expected {
+				t.Errorf("got %s, want %s", got, tt.expected)
 			}
 		})
 	}
@@ -123,3 +128,78 @@ func TestFindSortCode(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSignedAmounts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"-25.99 974.01", []string{"-25.99", "974.01"}},
+		{"+£2,500.00 £3,474.01", []string{"2500.00", "3474.01"}},
+		{"£-4.50", []string{"-4.50"}},
+		{"\u2212€10.00", []string{"-10.00"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parseSignedAmounts(tt.input)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("got %v, want %v", got, tt.expected)
+			}
+			for i := range got {
+				if got[i].String() != tt.expected[i] {
+					t.Errorf("[%d]: got %s, want %s", i, got[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"🏠 Rent pot", "Rent pot"},
+		{"Coffee ☕️ Shop", "Coffee Shop"},
+		{"Family 👨‍👩‍👧 Savings", "Family Savings"},
+		{"Paid £5 to Café", "Paid £5 to Café"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := stripEmoji(tt.input)
+			if got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtractPrintedTotals(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantDebit  float64
+		wantCredit float64
+	}{
+		{"barclays combined", "Total Payments/Receipts → 27,129.56 21,000.00", 27129.56, 21000.00},
+		{"separate lines", "Total paid out 1,025.99\nTotal paid in 2,500.00", 1025.99, 2500.00},
+		{"money in/out", "Total money in £100.00\nTotal money out £-45.50", 45.50, 100.00},
+		{"last total wins", "Total payments 10.00\nTotal payments 30.00", 30.00, 0},
+		{"no amounts", "Total payments are shown below", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &models.StatementInfo{}
+			extractPrintedTotals(tt.text, info)
+			if info.PrintedTotalDebit.Float64() != tt.wantDebit {
+				t.Errorf("PrintedTotalDebit: got %.2f, want %.2f", info.PrintedTotalDebit.Float64(), tt.wantDebit)
+			}
+			if info.PrintedTotalCredit.Float64() != tt.wantCredit {
+				t.Errorf("PrintedTotalCredit: got %.2f, want %.2f", info.PrintedTotalCredit.Float64(), tt.wantCredit)
+			}
+		})
+	}
+}
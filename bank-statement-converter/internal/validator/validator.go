@@ -0,0 +1,200 @@
+// Package validator reconciles parsed statements against the balances and
+// totals printed on them, so parsing errors (a missed row, a debit read as a
+// credit) are reported instead of silently exported.
+package validator
+
+import (
+	"fmt"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// Validate walks the statement's transactions from its opening balance and
+// returns a report of every check that failed:
+//
+//   - each printed running balance must equal the previous balance plus or
+//     minus the row's amount;
+//   - the opening balance plus credits minus debits must equal the closing
+//     balance (the statement balance for credit cards);
+//   - the debit and credit sums must equal the printed totals, if any.
+//
+// Rows without a printed balance carry the expected balance forward. When
+// the statement has no opening balance it is derived from the first printed
+// balance. Multi-currency statements are reconciled per currency; the
+// closing balance and totals apply to the first transaction's currency.
+func Validate(info *models.StatementInfo) *models.ValidationReport {
+	if info.CreditCard {
+		return validateCreditCard(info)
+	}
+
+	r := &models.ValidationReport{
+		ClosingBalance:     info.ClosingBalance,
+		PrintedTotalDebit:  info.PrintedTotalDebit,
+		PrintedTotalCredit: info.PrintedTotalCredit,
+	}
+
+	base := ""
+	if len(info.Transactions) > 0 {
+		base = info.Transactions[0].Currency
+	}
+	opening, anchor, haveOpening := openingBalance(info, base)
+	r.OpeningBalance = opening
+
+	prev := make(map[string]models.Money)
+	known := make(map[string]bool)
+	if haveOpening {
+		prev[base] = opening
+		known[base] = true
+	}
+
+	for i, txn := range info.Transactions {
+		cur := txn.Currency
+		if txn.Type == "BALANCE" {
+			// Brought/carried forward rows must repeat the running balance
+			if known[cur] && i != anchor {
+				r.Checked++
+				if !prev[cur].Equal(txn.Balance) {
+					addMismatch(r, i, txn, prev[cur], prev[cur])
+				}
+			}
+			prev[cur] = txn.Balance
+			known[cur] = true
+			if cur == base {
+				r.ClosingBalance = closingOr(info.ClosingBalance, txn.Balance)
+			}
+			continue
+		}
+
+		if cur == base {
+			if txn.Type == "DEBIT" {
+				r.TotalDebit = r.TotalDebit.Add(txn.Amount)
+			} else {
+				r.TotalCredit = r.TotalCredit.Add(txn.Amount)
+			}
+		}
+
+		if txn.Balance.IsZero() {
+			// No printed balance: carry the expected balance forward
+			prev[cur] = prev[cur].Add(txn.SignedAmount())
+			if cur == base {
+				r.ClosingBalance = info.ClosingBalance
+			}
+			continue
+		}
+		if known[cur] && i != anchor {
+			r.Checked++
+			if expected := prev[cur].Add(txn.SignedAmount()); !expected.Equal(txn.Balance) {
+				addMismatch(r, i, txn, prev[cur], expected)
+			}
+		}
+		prev[cur] = txn.Balance
+		known[cur] = true
+		if cur == base {
+			r.ClosingBalance = closingOr(info.ClosingBalance, txn.Balance)
+		}
+	}
+
+	r.ComputedClosingBalance = opening.Add(r.TotalCredit).Sub(r.TotalDebit)
+	if haveOpening && !r.ClosingBalance.IsZero() {
+		checkClosing(r)
+	}
+	checkTotals(r)
+
+	r.Valid = len(r.Issues) == 0
+	return r
+}
+
+// validateCreditCard checks a credit card statement, where balances are the
+// amount owed: charges increase it and payments reduce it. Card statements
+// print no running balance, so only the totals are checked.
+func validateCreditCard(info *models.StatementInfo) *models.ValidationReport {
+	r := &models.ValidationReport{
+		OpeningBalance:     info.OpeningBalance,
+		ClosingBalance:     info.StatementBalance,
+		PrintedTotalDebit:  info.PrintedTotalDebit,
+		PrintedTotalCredit: info.PrintedTotalCredit,
+	}
+	for _, txn := range info.Transactions {
+		switch txn.Type {
+		case "DEBIT":
+			r.TotalDebit = r.TotalDebit.Add(txn.Amount)
+		case "CREDIT":
+			r.TotalCredit = r.TotalCredit.Add(txn.Amount)
+		}
+	}
+	r.ComputedClosingBalance = info.OpeningBalance.Add(r.TotalDebit).Sub(r.TotalCredit)
+	if !r.ClosingBalance.IsZero() {
+		checkClosing(r)
+	}
+	checkTotals(r)
+
+	r.Valid = len(r.Issues) == 0
+	return r
+}
+
+// openingBalance returns the statement's opening balance in the base
+// currency. Without a printed one, it is worked back from the first printed
+// balance, whose index is returned as the anchor (that row cannot then be
+// checked); the anchor is -1 otherwise.
+func openingBalance(info *models.StatementInfo, base string) (opening models.Money, anchor int, ok bool) {
+	if !info.OpeningBalance.IsZero() {
+		return info.OpeningBalance, -1, true
+	}
+	var movement models.Money
+	for i, txn := range info.Transactions {
+		if txn.Currency != base {
+			continue
+		}
+		if txn.Type != "BALANCE" {
+			movement = movement.Add(txn.SignedAmount())
+		}
+		if txn.Type == "BALANCE" || !txn.Balance.IsZero() {
+			return txn.Balance.Sub(movement), i, true
+		}
+	}
+	return models.Money{}, -1, false
+}
+
+// addMismatch records a row whose printed balance is not expected.
+func addMismatch(r *models.ValidationReport, i int, txn models.Transaction, prev, expected models.Money) {
+	r.Mismatches = append(r.Mismatches, models.BalanceMismatch{
+		Index:           i,
+		Date:            txn.Date,
+		Description:     txn.Description,
+		Type:            txn.Type,
+		Amount:          txn.Amount,
+		PreviousBalance: prev,
+		ExpectedBalance: expected,
+		Balance:         txn.Balance,
+	})
+	r.Issues = append(r.Issues, fmt.Sprintf("row %d (%s %s): expected balance %s, statement shows %s",
+		i+1, txn.Date, txn.Description, expected, txn.Balance))
+}
+
+func checkClosing(r *models.ValidationReport) {
+	if !r.ComputedClosingBalance.Equal(r.ClosingBalance) {
+		r.Issues = append(r.Issues, fmt.Sprintf("closing balance: transactions give %s, statement shows %s",
+			r.ComputedClosingBalance, r.ClosingBalance))
+	}
+}
+
+func checkTotals(r *models.ValidationReport) {
+	if !r.PrintedTotalDebit.IsZero() && !r.TotalDebit.Equal(r.PrintedTotalDebit) {
+		r.Issues = append(r.Issues, fmt.Sprintf("total payments: transactions sum to %s, statement prints %s",
+			r.TotalDebit, r.PrintedTotalDebit))
+	}
+	if !r.PrintedTotalCredit.IsZero() && !r.TotalCredit.Equal(r.PrintedTotalCredit) {
+		r.Issues = append(r.Issues, fmt.Sprintf("total receipts: transactions sum to %s, statement prints %s",
+			r.TotalCredit, r.PrintedTotalCredit))
+	}
+}
+
+// closingOr returns the printed closing balance, or the latest running
+// balance when the statement does not print one. Rows after the latest
+// running balance reset the fallback, as it is no longer the closing one.
+func closingOr(printed, latest models.Money) models.Money {
+	if !printed.IsZero() {
+		return printed
+	}
+	return latest
+}
@@ -0,0 +1,236 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name           string
+		info           models.StatementInfo
+		wantValid      bool
+		wantChecked    int
+		wantMismatches []int
+		wantIssues     []string
+		wantOpening    string
+		wantComputed   string
+	}{
+		{
+			name: "reconciles",
+			info: models.StatementInfo{
+				OpeningBalance:     money("1000.00"),
+				ClosingBalance:     money("2474.01"),
+				PrintedTotalDebit:  money("25.99"),
+				PrintedTotalCredit: money("1500.00"),
+				Transactions: []models.Transaction{
+					{Date: "01 Jan 2024", Description: "BALANCE BROUGHT FORWARD", Type: "BALANCE", Balance: money("1000.00")},
+					{Date: "02 Jan 2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99"), Balance: money("974.01")},
+					{Date: "03 Jan 2024", Description: "SALARY", Type: "CREDIT", Amount: money("1500.00"), Balance: money("2474.01")},
+				},
+			},
+			wantValid:    true,
+			wantChecked:  3,
+			wantOpening:  "1000.00",
+			wantComputed: "2474.01",
+		},
+		{
+			name: "row without printed balance carries forward",
+			info: models.StatementInfo{
+				OpeningBalance: money("1000.00"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan 2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99")},
+					{Date: "02 Jan 2024", Description: "COSTA", Type: "DEBIT", Amount: money("4.50"), Balance: money("969.51")},
+				},
+			},
+			wantValid:    true,
+			wantChecked:  1,
+			wantOpening:  "1000.00",
+			wantComputed: "969.51",
+		},
+		{
+			name: "trailing row without balance has no closing to check",
+			info: models.StatementInfo{
+				OpeningBalance: money("1000.00"),
+				Transactions: []models.Transaction{
+					{Date: "02/01/2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99"), Balance: money("974.01")},
+					{Date: "05/01/2024", Description: "SKY DIGITAL", Type: "DEBIT", Amount: money("45.00")},
+				},
+			},
+			wantValid:    true,
+			wantChecked:  1,
+			wantOpening:  "1000.00",
+			wantComputed: "929.01",
+		},
+		{
+			name: "debit read as credit",
+			info: models.StatementInfo{
+				OpeningBalance: money("1000.00"),
+				ClosingBalance: money("948.02"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan 2024", Description: "TESCO", Type: "CREDIT", Amount: money("25.99"), Balance: money("974.01")},
+					{Date: "03 Jan 2024", Description: "SAINSBURYS", Type: "DEBIT", Amount: money("25.99"), Balance: money("948.02")},
+				},
+			},
+			wantChecked:    2,
+			wantMismatches: []int{0},
+			wantIssues: []string{
+				"row 1 (02 Jan 2024 TESCO): expected balance 1025.99, statement shows 974.01",
+				"closing balance: transactions give 1000.00, statement shows 948.02",
+			},
+			wantOpening:  "1000.00",
+			wantComputed: "1000.00",
+		},
+		{
+			name: "printed balance disagrees with amount",
+			info: models.StatementInfo{
+				OpeningBalance:     money("1000.00"),
+				PrintedTotalDebit:  money("30.49"),
+				PrintedTotalCredit: money("0.00"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan 2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99"), Balance: money("974.01")},
+					{Date: "04 Jan 2024", Description: "COSTA", Type: "DEBIT", Amount: money("4.50"), Balance: money("965.00")},
+				},
+			},
+			wantChecked:    2,
+			wantMismatches: []int{1},
+			wantIssues: []string{
+				"row 2 (04 Jan 2024 COSTA): expected balance 969.51, statement shows 965.00",
+				"closing balance: transactions give 969.51, statement shows 965.00",
+			},
+			wantOpening:  "1000.00",
+			wantComputed: "969.51",
+		},
+		{
+			name: "carried forward balance disagrees",
+			info: models.StatementInfo{
+				OpeningBalance: money("1000.00"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan 2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99"), Balance: money("974.01")},
+					{Date: "31 Jan 2024", Description: "BALANCE CARRIED FORWARD", Type: "BALANCE", Balance: money("947.01")},
+				},
+			},
+			wantChecked:    2,
+			wantMismatches: []int{1},
+			wantIssues: []string{
+				"row 2 (31 Jan 2024 BALANCE CARRIED FORWARD): expected balance 974.01, statement shows 947.01",
+				"closing balance: transactions give 974.01, statement shows 947.01",
+			},
+			wantOpening:  "1000.00",
+			wantComputed: "974.01",
+		},
+		{
+			name: "opening derived from first balance",
+			info: models.StatementInfo{
+				PrintedTotalDebit:  money("25.99"),
+				PrintedTotalCredit: money("100.00"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan 2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99"), Balance: money("974.01")},
+					{Date: "03 Jan 2024", Description: "REFUND", Type: "CREDIT", Amount: money("10.00"), Balance: money("984.01")},
+				},
+			},
+			wantChecked: 1,
+			wantIssues: []string{
+				"total receipts: transactions sum to 10.00, statement prints 100.00",
+			},
+			wantOpening:  "1000.00",
+			wantComputed: "984.01",
+		},
+		{
+			name: "pockets reconcile per currency",
+			info: models.StatementInfo{
+				OpeningBalance: money("500.00"),
+				ClosingBalance: money("474.01"),
+				Transactions: []models.Transaction{
+					{Date: "2 Jan 2024", Description: "TESCO", Type: "DEBIT", Amount: money("25.99"), Balance: money("474.01"), Currency: "GBP"},
+					{Date: "3 Jan 2024", Description: "Exchanged to EUR", Type: "CREDIT", Amount: money("100.00"), Balance: money("100.00"), Currency: "EUR"},
+					{Date: "4 Jan 2024", Description: "CAFE DE FLORE", Type: "DEBIT", Amount: money("25.99"), Balance: money("74.01"), Currency: "EUR"},
+				},
+			},
+			wantValid:    true,
+			wantChecked:  2,
+			wantOpening:  "500.00",
+			wantComputed: "474.01",
+		},
+		{
+			name: "credit card balance is amount owed",
+			info: models.StatementInfo{
+				CreditCard:       true,
+				OpeningBalance:   money("500.00"),
+				StatementBalance: money("125.99"),
+				ClosingBalance:   money("125.99"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan", Description: "TESCO", Type: "DEBIT", Amount: money("125.99")},
+					{Date: "15 Jan", Description: "PAYMENT RECEIVED", Type: "CREDIT", Amount: money("500.00")},
+				},
+			},
+			wantValid:    true,
+			wantOpening:  "500.00",
+			wantComputed: "125.99",
+		},
+		{
+			name: "credit card missing charge",
+			info: models.StatementInfo{
+				CreditCard:       true,
+				OpeningBalance:   money("0.00"),
+				StatementBalance: money("150.00"),
+				Transactions: []models.Transaction{
+					{Date: "02 Jan", Description: "TESCO", Type: "DEBIT", Amount: money("125.99")},
+				},
+			},
+			wantIssues: []string{
+				"closing balance: transactions give 125.99, statement shows 150.00",
+			},
+			wantOpening:  "0.00",
+			wantComputed: "125.99",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := Validate(&tt.info)
+
+			if r.Valid != tt.wantValid {
+				t.Errorf("Valid: got %v, want %v (issues: %s)", r.Valid, tt.wantValid, strings.Join(r.Issues, "; "))
+			}
+			if r.Checked != tt.wantChecked {
+				t.Errorf("Checked: got %d, want %d", r.Checked, tt.wantChecked)
+			}
+			if got := r.OpeningBalance.String(); got != tt.wantOpening {
+				t.Errorf("OpeningBalance: got %s, want %s", got, tt.wantOpening)
+			}
+			if got := r.ComputedClosingBalance.String(); got != tt.wantComputed {
+				t.Errorf("ComputedClosingBalance: got %s, want %s", got, tt.wantComputed)
+			}
+
+			if len(r.Mismatches) != len(tt.wantMismatches) {
+				t.Fatalf("got %d mismatches, want %d: %+v", len(r.Mismatches), len(tt.wantMismatches), r.Mismatches)
+			}
+			for i, idx := range tt.wantMismatches {
+				if r.Mismatches[i].Index != idx {
+					t.Errorf("mismatch[%d].Index: got %d, want %d", i, r.Mismatches[i].Index, idx)
+				}
+			}
+
+			if len(r.Issues) != len(tt.wantIssues) {
+				t.Fatalf("got %d issues, want %d: %q", len(r.Issues), len(tt.wantIssues), r.Issues)
+			}
+			for i, want := range tt.wantIssues {
+				if r.Issues[i] != want {
+					t.Errorf("issue[%d]: got %q, want %q", i, r.Issues[i], want)
+				}
+			}
+		})
+	}
+}
+
+// money parses a test amount such as "974.01".
+func money(s string) models.Money {
+	m, err := models.ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+package writer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// BeancountWriter writes a statement as beancount directives: an open
+// directive dated from the statement period, an opening balance transaction
+// and one transaction per statement transaction.
+//
+// Beancount checks balance directives at the start of their day, so each
+// day's last printed running balance is asserted on the following day.
+// Statements without running balances (cards) assert the closing balance.
+// Every transaction needs a resolved date (models.Transaction.ISODate).
+type BeancountWriter struct {
+	Accounts *LedgerAccounts // nil for the default account names
+}
+
+// Write writes the statement as beancount to out.
+func (w *BeancountWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	s, err := newLedgerStatement(info, w.Accounts, "beancount")
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(out)
+	currencies := make([]string, len(s.parts))
+	for i, p := range s.parts {
+		currencies[i] = p.currency
+	}
+	fmt.Fprintf(bw, "%s open %s %s\n", s.open, s.account, strings.Join(currencies, ","))
+	for _, p := range s.parts {
+		if p.opening.IsZero() {
+			continue
+		}
+		fmt.Fprintf(bw, "\n%s * \"Opening balance\"\n", s.open)
+		fmt.Fprintf(bw, "  %s  %s\n", s.account, ledgerAmount(p.opening, p.currency))
+		fmt.Fprintf(bw, "  %s\n", openingBalancesAccount)
+	}
+	for _, p := range s.parts {
+		for i, txn := range p.txns {
+			amount := txn.SignedAmount()
+			fmt.Fprintf(bw, "\n%s * %s\n", txn.ISODate, beancountString(txn.Description))
+			fmt.Fprintf(bw, "  %s  %s\n", s.account, ledgerAmount(amount, p.currency))
+			fmt.Fprintf(bw, "  %s\n", w.Accounts.counterAccount(amount))
+
+			lastOfDay := i == len(p.txns)-1 || !p.txns[i+1].ISODate.Equal(txn.ISODate.Time)
+			if lastOfDay && hasBalance(txn) {
+				fmt.Fprintf(bw, "\n%s balance %s  %s\n", nextDay(txn.ISODate), s.account, ledgerAmount(txn.Balance, p.currency))
+			}
+		}
+		if !hasBalance(p.txns[len(p.txns)-1]) {
+			fmt.Fprintf(bw, "\n%s balance %s  %s\n", nextDay(p.end), s.account, ledgerAmount(p.closing, p.currency))
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write beancount: %w", err)
+	}
+	return nil
+}
+
+// beancountString quotes s as a beancount string on one line.
+func beancountString(s string) string {
+	s = strings.ReplaceAll(qifText(s), `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func nextDay(d models.Date) models.Date {
+	t := d.AddDate(0, 0, 1)
+	return models.NewDate(t.Year(), t.Month(), t.Day())
+}
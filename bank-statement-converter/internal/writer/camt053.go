@@ -0,0 +1,307 @@
+package writer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// camtNamespace is the camt.053 schema version written; .001.02 is the one
+// UK treasury and ERP systems most widely accept.
+const camtNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camtUstrdLen is the maximum length of unstructured remittance
+// information; the full description also goes in AddtlNtryInf.
+const camtUstrdLen = 140
+
+// Camt053Writer writes a statement as an ISO 20022 camt.053 (bank to
+// customer statement) XML document. Multi-currency statements get one
+// <Stmt> per currency.
+//
+// The account is identified by IBAN, built from the sort code and account
+// number, when the bank is known; otherwise by the sort code and account
+// number (or card number) as given. Every transaction needs a resolved
+// date (models.Transaction.ISODate).
+type Camt053Writer struct{}
+
+type camtDocument struct {
+	XMLName xml.Name      `xml:"Document"`
+	XMLNS   string        `xml:"xmlns,attr"`
+	Stmts   camtBkToCstmr `xml:"BkToCstmrStmt"`
+}
+
+type camtBkToCstmr struct {
+	GrpHdr camtGrpHdr `xml:"GrpHdr"`
+	Stmt   []camtStmt `xml:"Stmt"`
+}
+
+type camtGrpHdr struct {
+	MsgID    string `xml:"MsgId"`
+	CreDtTm  string `xml:"CreDtTm"`
+	MsgPgntn struct {
+		PgNb      int  `xml:"PgNb"`
+		LastPgInd bool `xml:"LastPgInd"`
+	} `xml:"MsgPgntn"`
+}
+
+type camtStmt struct {
+	ID        string         `xml:"Id"`
+	CreDtTm   string         `xml:"CreDtTm"`
+	FrToDt    camtFrToDt     `xml:"FrToDt"`
+	Acct      camtAcct       `xml:"Acct"`
+	Bal       []camtBal      `xml:"Bal"`
+	TxsSummry camtTxsSummary `xml:"TxsSummry"`
+	Ntry      []camtEntry    `xml:"Ntry"`
+}
+
+type camtFrToDt struct {
+	FrDtTm string `xml:"FrDtTm"`
+	ToDtTm string `xml:"ToDtTm"`
+}
+
+type camtAcct struct {
+	ID   camtAcctID `xml:"Id"`
+	Ccy  string     `xml:"Ccy"`
+	Ownr *camtOwner `xml:"Ownr,omitempty"`
+}
+
+type camtAcctID struct {
+	IBAN string         `xml:"IBAN,omitempty"`
+	Othr *camtOtherAcct `xml:"Othr,omitempty"`
+}
+
+type camtOtherAcct struct {
+	ID      string      `xml:"Id"`
+	SchmeNm *camtScheme `xml:"SchmeNm,omitempty"`
+}
+
+type camtScheme struct {
+	Cd string `xml:"Cd"`
+}
+
+type camtOwner struct {
+	Nm string `xml:"Nm"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+type camtDate struct {
+	Dt string `xml:"Dt"`
+}
+
+type camtBal struct {
+	Tp struct {
+		CdOrPrtry struct {
+			Cd string `xml:"Cd"`
+		} `xml:"CdOrPrtry"`
+	} `xml:"Tp"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Dt        camtDate   `xml:"Dt"`
+}
+
+type camtTxsSummary struct {
+	TtlNtries    camtTotal `xml:"TtlNtries"`
+	TtlCdtNtries camtTotal `xml:"TtlCdtNtries"`
+	TtlDbtNtries camtTotal `xml:"TtlDbtNtries"`
+}
+
+type camtTotal struct {
+	NbOfNtries int    `xml:"NbOfNtries"`
+	Sum        string `xml:"Sum"`
+}
+
+type camtEntry struct {
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Sts       string     `xml:"Sts"`
+	BookgDt   camtDate   `xml:"BookgDt"`
+	ValDt     camtDate   `xml:"ValDt"`
+	BkTxCd    struct {
+		Prtry struct {
+			Cd string `xml:"Cd"`
+		} `xml:"Prtry"`
+	} `xml:"BkTxCd"`
+	NtryDtls struct {
+		TxDtls struct {
+			RmtInf struct {
+				Ustrd string `xml:"Ustrd"`
+			} `xml:"RmtInf"`
+		} `xml:"TxDtls"`
+	} `xml:"NtryDtls"`
+	AddtlNtryInf string `xml:"AddtlNtryInf,omitempty"`
+}
+
+// Write writes the statement as camt.053 to out.
+func (w *Camt053Writer) Write(out io.Writer, info *models.StatementInfo) error {
+	parts, err := splitByCurrency(info, "camt.053")
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("statement has no transactions; camt.053 needs at least one dated entry")
+	}
+
+	acctID := statementAccountID(info)
+	var latest models.Date
+	for _, p := range parts {
+		if p.end.After(latest.Time) {
+			latest = p.end
+		}
+	}
+	doc := camtDocument{XMLNS: camtNamespace}
+	doc.Stmts.GrpHdr.MsgID = camtID(acctID, "", latest)
+	doc.Stmts.GrpHdr.CreDtTm = camtDateTime(latest)
+	doc.Stmts.GrpHdr.MsgPgntn.PgNb = 1
+	doc.Stmts.GrpHdr.MsgPgntn.LastPgInd = true
+
+	for _, p := range parts {
+		stmt := camtStmt{
+			ID:      camtID(acctID, p.currency, p.end),
+			CreDtTm: camtDateTime(p.end),
+			FrToDt:  camtFrToDt{FrDtTm: camtDateTime(p.start), ToDtTm: camtDateTime(p.end)},
+			Acct:    camtAccount(info, p.currency),
+			Bal: []camtBal{
+				camtBalance("OPBD", p.opening, p.currency, p.start),
+				camtBalance("CLBD", p.closing, p.currency, p.end),
+			},
+		}
+		if len(parts) == 1 {
+			stmt.ID = camtID(acctID, "", p.end)
+		}
+
+		var credits, debits models.Money
+		for _, txn := range p.txns {
+			entry := camtEntry{
+				Amt:       camtAmount{Ccy: p.currency, Value: txn.Amount.Abs().String()},
+				CdtDbtInd: camtIndicator(txn.SignedAmount()),
+				Sts:       "BOOK",
+				BookgDt:   camtDate{Dt: txn.ISODate.String()},
+				ValDt:     camtDate{Dt: txn.ISODate.String()},
+			}
+			// Card statements post after the transaction date
+			if !txn.ISOPostingDate.IsZero() {
+				entry.BookgDt.Dt = txn.ISOPostingDate.String()
+			}
+			entry.BkTxCd.Prtry.Cd = txn.Type
+			desc := strings.Join(strings.Fields(txn.Description), " ")
+			entry.NtryDtls.TxDtls.RmtInf.Ustrd = truncateRunes(desc, camtUstrdLen)
+			if entry.NtryDtls.TxDtls.RmtInf.Ustrd != desc {
+				entry.AddtlNtryInf = desc
+			}
+			stmt.Ntry = append(stmt.Ntry, entry)
+
+			if txn.Type == "DEBIT" {
+				debits = debits.Add(txn.Amount.Abs())
+				stmt.TxsSummry.TtlDbtNtries.NbOfNtries++
+			} else {
+				credits = credits.Add(txn.Amount.Abs())
+				stmt.TxsSummry.TtlCdtNtries.NbOfNtries++
+			}
+		}
+		stmt.TxsSummry.TtlNtries = camtTotal{NbOfNtries: len(p.txns), Sum: credits.Add(debits).String()}
+		stmt.TxsSummry.TtlCdtNtries.Sum = credits.String()
+		stmt.TxsSummry.TtlDbtNtries.Sum = debits.String()
+		doc.Stmts.Stmt = append(doc.Stmts.Stmt, stmt)
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return fmt.Errorf("failed to write camt.053 header: %w", err)
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write camt.053: %w", err)
+	}
+	_, err = io.WriteString(out, "\n")
+	return err
+}
+
+// camtAccount identifies the account by IBAN when one can be built,
+// otherwise by sort code and account number (a BBAN) or card number.
+func camtAccount(info *models.StatementInfo, currency string) camtAcct {
+	acct := camtAcct{Ccy: currency}
+	if info.AccountHolder != "" {
+		acct.Ownr = &camtOwner{Nm: info.AccountHolder}
+	}
+	if iban := statementIBAN(info); iban != "" {
+		acct.ID.IBAN = iban
+		return acct
+	}
+	other := &camtOtherAcct{ID: statementAccountID(info)}
+	if !info.CreditCard && info.SortCode != "" {
+		other.SchmeNm = &camtScheme{Cd: "BBAN"}
+	}
+	acct.ID.Othr = other
+	return acct
+}
+
+func camtBalance(code string, amount models.Money, currency string, date models.Date) camtBal {
+	var b camtBal
+	b.Tp.CdOrPrtry.Cd = code
+	b.Amt = camtAmount{Ccy: currency, Value: amount.Abs().String()}
+	b.CdtDbtInd = camtIndicator(amount)
+	b.Dt = camtDate{Dt: date.String()}
+	return b
+}
+
+// camtIndicator is CRDT for money in (or a balance in credit), DBIT for
+// money out (or an overdrawn balance or amount owed).
+func camtIndicator(amount models.Money) string {
+	if amount.IsNegative() {
+		return "DBIT"
+	}
+	return "CRDT"
+}
+
+// camtID derives a message or statement ID from the account and the
+// statement's last date, so that writing the same statement twice gives
+// the same document. IDs are at most 35 characters.
+func camtID(acctID, currency string, end models.Date) string {
+	id := acctID + "-" + end.Format(ofxDateLayout)
+	if currency != "" {
+		id += "-" + currency
+	}
+	return truncateRunes(id, 35)
+}
+
+func camtDateTime(d models.Date) string {
+	return d.String() + "T00:00:00"
+}
+
+// statementIBAN is the account's IBAN, or "" for cards and banks without
+// a known IBAN bank code.
+func statementIBAN(info *models.StatementInfo) string {
+	if info.CreditCard {
+		return ""
+	}
+	return ukIBAN(info.Bank, info.SortCode, info.AccountNumber)
+}
+
+// statementAccountID is the sort code and account number run together, or
+// the card number; "UNKNOWN" when the statement has neither.
+func statementAccountID(info *models.StatementInfo) string {
+	id := models.DigitsOnly(info.SortCode) + strings.ReplaceAll(info.AccountNumber, " ", "")
+	if info.CreditCard {
+		id = info.AccountNumber
+	}
+	if id == "" {
+		return "UNKNOWN"
+	}
+	return id
+}
+
+// truncateRunes shortens s to at most n characters.
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:n]))
+}
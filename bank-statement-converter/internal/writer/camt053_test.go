@@ -0,0 +1,80 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestCamt053Writer_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&Camt053Writer{}).Write(&buf, goldenStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "statement.camt053.xml", buf.Bytes())
+}
+
+func TestCamt053Writer_CreditCard(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank:             models.BankAmex,
+		AccountNumber:    "XXXX-XXXXX6-71005",
+		CreditCard:       true,
+		OpeningBalance:   models.Pence(10000),
+		StatementBalance: models.Pence(12599),
+		Transactions: []models.Transaction{
+			{ISODate: models.NewDate(2024, 1, 2), ISOPostingDate: models.NewDate(2024, 1, 3), Description: "TESCO STORES", Type: "DEBIT", Amount: models.Pence(2599)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&Camt053Writer{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc camtDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	stmt := doc.Stmts.Stmt[0]
+	if stmt.Acct.ID.IBAN != "" || stmt.Acct.ID.Othr == nil || stmt.Acct.ID.Othr.ID != "XXXX-XXXXX6-71005" {
+		t.Errorf("account: got %+v", stmt.Acct.ID)
+	}
+	// Amounts owed are debit balances
+	for i, want := range []string{"DBIT 100.00", "DBIT 125.99"} {
+		if got := stmt.Bal[i].CdtDbtInd + " " + stmt.Bal[i].Amt.Value; got != want {
+			t.Errorf("balance %s: got %s, want %s", stmt.Bal[i].Tp.CdOrPrtry.Cd, got, want)
+		}
+	}
+	entry := stmt.Ntry[0]
+	if entry.CdtDbtInd != "DBIT" || entry.BookgDt.Dt != "2024-01-03" || entry.ValDt.Dt != "2024-01-02" {
+		t.Errorf("entry: got %+v", entry)
+	}
+}
+
+func TestCamt053Writer_MultiCurrency(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank: models.BankRevolut,
+		Transactions: []models.Transaction{
+			{ISODate: models.NewDate(2024, 1, 2), Description: "Tesco", Type: "DEBIT", Amount: models.Pence(2599), Balance: models.Pence(97401), Currency: "GBP"},
+			{ISODate: models.NewDate(2024, 1, 3), Description: "Cafe", Type: "DEBIT", Amount: models.Pence(450), Balance: models.Pence(9550), Currency: "EUR"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&Camt053Writer{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc camtDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v", err)
+	}
+	if len(doc.Stmts.Stmt) != 2 {
+		t.Fatalf("expected one Stmt per currency, got %d", len(doc.Stmts.Stmt))
+	}
+	eur := doc.Stmts.Stmt[1]
+	if eur.Acct.Ccy != "EUR" || eur.Bal[0].Amt.Value != "100.00" || eur.Bal[1].Amt.Value != "95.50" {
+		t.Errorf("EUR statement: got %s opening %s closing %s", eur.Acct.Ccy, eur.Bal[0].Amt.Value, eur.Bal[1].Amt.Value)
+	}
+	if doc.Stmts.Stmt[0].ID == eur.ID {
+		t.Errorf("statement IDs should differ by currency, both %q", eur.ID)
+	}
+}
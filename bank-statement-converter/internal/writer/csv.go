@@ -4,8 +4,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
-	"os"
-	"strconv"
+	"strings"
 
 	"github.com/insightdelivered/bank-statement-converter/internal/models"
 )
@@ -13,23 +12,61 @@ import (
 // CSVWriter writes transactions to CSV format.
 type CSVWriter struct {
 	IncludeHeader bool
+	// DateFormat is the Go layout for the date columns (see DateLayout).
+	// Empty writes dates as printed on the statement.
+	DateFormat string
+	// Preset writes an accounting package's layout instead of the default
+	// columns and metadata rows.
+	Preset *CSVPreset
+	// Schema customises the columns and formatting; nil is the default
+	// layout.
+	Schema *CSVSchema
+	// Sanitize neutralises cells a spreadsheet would run as formulas
+	// (see sanitizeCell). Descriptions come straight from the PDF.
+	Sanitize bool
 }
 
 // WriteToFile writes transactions to a CSV file at the given path.
 func (w *CSVWriter) WriteToFile(path string, info *models.StatementInfo) error {
-	f, err := os.Create(path)
-	if err != nil {
-		return fmt.Errorf("failed to create output file %q: %w", path, err)
-	}
-	defer f.Close()
-
-	return w.Write(f, info)
+	return WriteFile(path, w, info)
 }
 
 // Write writes transactions in CSV format to the given writer.
 func (w *CSVWriter) Write(out io.Writer, info *models.StatementInfo) error {
-	writer := csv.NewWriter(out)
-	defer writer.Flush()
+	if w.Preset != nil {
+		writer := w.sanitizing(csv.NewWriter(out))
+		if err := w.writePreset(writer, info); err != nil {
+			return err
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+		return nil
+	}
+
+	schema := w.Schema
+	if schema == nil {
+		schema = &CSVSchema{}
+	}
+	comma, err := schema.delimiter()
+	if err != nil {
+		return err
+	}
+	if schema.BOM {
+		if _, err := io.WriteString(out, "\ufeff"); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+	var writer recordWriter
+	if schema.QuoteAll {
+		writer = newQuoteAllWriter(out, comma)
+	} else {
+		cw := csv.NewWriter(out)
+		cw.Comma = comma
+		writer = cw
+	}
+	writer = w.sanitizing(writer)
 
 	// Write metadata as comments (CSV header rows)
 	if w.IncludeHeader {
@@ -48,37 +85,139 @@ func (w *CSVWriter) Write(out io.Writer, info *models.StatementInfo) error {
 		if info.StatementPeriod != "" {
 			writer.Write([]string{"# Statement Period", info.StatementPeriod})
 		}
-		if info.OpeningBalance != 0 {
-			writer.Write([]string{"# Opening Balance", formatAmount(info.OpeningBalance)})
+		if info.CreditCard {
+			if !info.OpeningBalance.IsZero() {
+				writer.Write([]string{"# Previous Balance", schema.formatMoney(info.OpeningBalance)})
+			}
+			if !info.StatementBalance.IsZero() {
+				writer.Write([]string{"# Statement Balance", schema.formatMoney(info.StatementBalance)})
+			}
+			if !info.MinimumPayment.IsZero() {
+				writer.Write([]string{"# Minimum Payment", schema.formatMoney(info.MinimumPayment)})
+			}
+			if info.PaymentDueDate != "" {
+				writer.Write([]string{"# Payment Due Date", info.PaymentDueDate})
+			}
+		} else {
+			if !info.OpeningBalance.IsZero() {
+				writer.Write([]string{"# Opening Balance", schema.formatMoney(info.OpeningBalance)})
+			}
+			if !info.ClosingBalance.IsZero() {
+				writer.Write([]string{"# Closing Balance", schema.formatMoney(info.ClosingBalance)})
+			}
 		}
 	}
 
-	// Write column headers
-	header := []string{"Date", "Description", "Type", "Amount", "Balance"}
+	// Write column headers (see CSVSchema.columns for the defaults)
+	columns := schema.columns(info)
+	header := make([]string, len(columns))
+	fields := make([]csvField, len(columns))
+	for i, c := range columns {
+		header[i] = c.header()
+		fields[i] = csvFields[strings.ToLower(c.Field)]
+	}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
 	// Write transaction rows
+	layout := w.DateFormat
+	if layout == "" {
+		layout = schema.DateFormat
+	}
 	for _, txn := range info.Transactions {
-		row := []string{
-			txn.Date,
-			txn.Description,
-			txn.Type,
-			formatAmount(txn.Amount),
-			formatAmount(txn.Balance),
+		r := &csvRow{txn: txn, info: info, schema: schema, layout: layout}
+		row := make([]string, len(fields))
+		for i, f := range fields {
+			row[i] = f.value(r)
 		}
 		if err := writer.Write(row); err != nil {
 			return fmt.Errorf("failed to write CSV row: %w", err)
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
 	return nil
 }
 
-func formatAmount(amount float64) string {
-	if amount == 0 {
+// sanitizing wraps writer to sanitise every cell when w.Sanitize is set.
+func (w *CSVWriter) sanitizing(writer recordWriter) recordWriter {
+	if !w.Sanitize {
+		return writer
+	}
+	return sanitizingWriter{writer}
+}
+
+// sanitizingWriter is a recordWriter that passes every cell through
+// sanitizeCell.
+type sanitizingWriter struct {
+	recordWriter
+}
+
+func (s sanitizingWriter) Write(record []string) error {
+	clean := make([]string, len(record))
+	for i, cell := range record {
+		clean[i] = sanitizeCell(cell)
+	}
+	return s.recordWriter.Write(clean)
+}
+
+// sanitizeCell neutralises a cell that a spreadsheet would treat as a
+// formula: one starting with =, +, -, @, tab or carriage return gets a
+// leading single quote, per the OWASP CSV injection guidance. Plain numbers
+// such as the amount "-4.50" (or "-4,50") are left as they are, since they
+// cannot be formulas.
+func sanitizeCell(cell string) string {
+	if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) || isPlainNumber(cell) {
+		return cell
+	}
+	return "'" + cell
+}
+
+// isPlainNumber reports whether s is an optionally negative decimal
+// number: digits with at most one decimal point or comma.
+func isPlainNumber(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	separators := 0
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+		case (r == '.' || r == ',') && i > 0 && i < len(s)-1:
+			separators++
+		default:
+			return false
+		}
+	}
+	return separators <= 1
+}
+
+// hasMultipleCurrencies reports whether txns span more than one currency.
+// An empty Currency counts as GBP.
+func hasMultipleCurrencies(txns []models.Transaction) bool {
+	first := ""
+	for _, txn := range txns {
+		c := txn.Currency
+		if c == "" {
+			c = "GBP"
+		}
+		if first == "" {
+			first = c
+		} else if c != first {
+			return true
+		}
+	}
+	return false
+}
+
+func formatAmount(amount models.Money) string {
+	if amount.IsZero() {
 		return ""
 	}
-	return strconv.FormatFloat(amount, 'f', 2, 64)
+	return amount.String()
 }
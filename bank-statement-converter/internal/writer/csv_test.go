@@ -16,8 +16,8 @@ func TestCSVWriter_Write(t *testing.T) {
 		SortCode:        "23-05-80",
 		StatementPeriod: "01/01/2024 to 31/01/2024",
 		Transactions: []models.Transaction{
-			{Date: "15/01/2024", Description: "CARD PAYMENT TESCO", Type: "DEBIT", Amount: 25.99, Balance: 1234.56},
-			{Date: "16/01/2024", Description: "SALARY", Type: "CREDIT", Amount: 2500.00, Balance: 3734.56},
+			{Date: "15/01/2024", Description: "CARD PAYMENT TESCO", Type: "DEBIT", Amount: models.Pence(2599), Balance: models.Pence(123456)},
+			{Date: "16/01/2024", Description: "SALARY", Type: "CREDIT", Amount: models.Pence(250000), Balance: models.Pence(373456)},
 		},
 	}
 
@@ -65,7 +65,7 @@ func TestCSVWriter_WriteNoHeader(t *testing.T) {
 	info := &models.StatementInfo{
 		Bank: models.BankHSBC,
 		Transactions: []models.Transaction{
-			{Date: "15/01/2024", Description: "PAYMENT", Type: "DEBIT", Amount: 10.00},
+			{Date: "15/01/2024", Description: "PAYMENT", Type: "DEBIT", Amount: models.Pence(1000)},
 		},
 	}
 
@@ -89,21 +89,222 @@ func TestCSVWriter_WriteNoHeader(t *testing.T) {
 	}
 }
 
+func TestCSVWriter_WriteMultiCurrency(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank: models.BankRevolut,
+		Transactions: []models.Transaction{
+			{Date: "2 Jan 2024", Description: "Tesco", Type: "DEBIT", Amount: models.Pence(2599), Balance: models.Pence(97401), Currency: "GBP"},
+			{Date: "3 Jan 2024", Description: "Cafe", Type: "DEBIT", Amount: models.Pence(450), Balance: models.Pence(9550), Currency: "EUR"},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := &CSVWriter{IncludeHeader: false}
+	if err := w.Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "Date,Description,Type,Amount,Balance,Currency" {
+		t.Errorf("header: got %q", lines[0])
+	}
+	if lines[2] != "3 Jan 2024,Cafe,DEBIT,4.50,95.50,EUR" {
+		t.Errorf("EUR row: got %q", lines[2])
+	}
+
+	// A single currency keeps the standard columns
+	info.Transactions[1].Currency = "GBP"
+	buf.Reset()
+	if err := w.Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Currency") {
+		t.Errorf("single-currency output should not have a Currency column:\n%s", buf.String())
+	}
+}
+
+func TestCSVWriter_WriteCreditCard(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank:             models.BankAmex,
+		CreditCard:       true,
+		OpeningBalance:   models.Pence(50000),
+		StatementBalance: models.Pence(2599),
+		ClosingBalance:   models.Pence(2599),
+		MinimumPayment:   models.Pence(500),
+		PaymentDueDate:   "25 Feb 2024",
+		Transactions: []models.Transaction{
+			{Date: "Jan 02", PostingDate: "Jan 03", Description: "TESCO STORES", Type: "DEBIT", Amount: models.Pence(2599)},
+			{Date: "Jan 15", PostingDate: "Jan 15", Description: "PAYMENT RECEIVED", Type: "CREDIT", Amount: models.Pence(50000)},
+		},
+	}
+
+	var buf bytes.Buffer
+	w := &CSVWriter{IncludeHeader: true}
+	if err := w.Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	output := buf.String()
+
+	for _, want := range []string{
+		"# Previous Balance,500.00",
+		"# Statement Balance,25.99",
+		"# Minimum Payment,5.00",
+		"# Payment Due Date,25 Feb 2024",
+		"Date,Posting Date,Description,Type,Amount\n",
+		"Jan 02,Jan 03,TESCO STORES,DEBIT,25.99\n",
+		"Jan 15,Jan 15,PAYMENT RECEIVED,CREDIT,500.00\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("missing %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "Balance\n") || strings.Contains(output, "# Closing Balance") {
+		t.Errorf("credit card output should not have a balance column or closing balance:\n%s", output)
+	}
+}
+
+func TestCSVWriter_WriteDateFormat(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank: models.BankBarclays,
+		Transactions: []models.Transaction{
+			{Date: "30 Dec", ISODate: models.NewDate(2025, 12, 30), Description: "Stripe", Type: "DEBIT", Amount: models.Pence(5880), Balance: models.Pence(939788)},
+			{Date: "2 Jan", ISODate: models.NewDate(2026, 1, 2), Description: "Antalis", Type: "CREDIT", Amount: models.Pence(80000), Balance: models.Pence(1019788)},
+			{Date: "Pending", Description: "Unresolved", Type: "DEBIT", Amount: models.Pence(100), Balance: models.Pence(1019688)},
+		},
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"", []string{"30 Dec,", "2 Jan,", "Pending,"}},
+		{"iso", []string{"2025-12-30,", "2026-01-02,", "Pending,"}},
+		{"uk", []string{"30/12/2025,", "02/01/2026,", "Pending,"}},
+		{"us", []string{"12/30/2025,", "01/02/2026,", "Pending,"}},
+		{"02 Jan 2006", []string{"30 Dec 2025,", "02 Jan 2026,", "Pending,"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			layout, err := DateLayout(tt.format)
+			if err != nil {
+				t.Fatalf("DateLayout(%q): %v", tt.format, err)
+			}
+			var buf bytes.Buffer
+			w := &CSVWriter{DateFormat: layout}
+			if err := w.Write(&buf, info); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")[1:]
+			for i, want := range tt.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("row %d: got %q, want prefix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDateLayout_Invalid(t *testing.T) {
+	if _, err := DateLayout("dd/mm/yyyy"); err == nil {
+		t.Error("expected error for a layout without date elements")
+	}
+}
+
 func TestFormatAmount(t *testing.T) {
 	tests := []struct {
-		input    float64
+		input    models.Money
 		expected string
 	}{
-		{25.99, "25.99"},
-		{1234.56, "1234.56"},
-		{0, ""},
-		{2500.00, "2500.00"},
+		{models.Pence(2599), "25.99"},
+		{models.Pence(123456), "1234.56"},
+		{models.Pence(0), ""},
+		{models.Pence(250000), "2500.00"},
+		{models.Pence(-450), "-4.50"},
+		{models.Pence(10), "0.10"},
 	}
 
 	for _, tt := range tests {
 		got := formatAmount(tt.input)
 		if got != tt.expected {
-			t.Errorf("formatAmount(%f): got %q, want %q", tt.input, got, tt.expected)
+			t.Errorf("formatAmount(%s): got %q, want %q", tt.input, got, tt.expected)
 		}
 	}
 }
+
+func TestSanitizeCell(t *testing.T) {
+	tests := []struct {
+		input, expected string
+	}{
+		{`=HYPERLINK("http://evil.example","Click")`, `'=HYPERLINK("http://evil.example","Click")`},
+		{"+cmd|' /C calc'!A0", "'+cmd|' /C calc'!A0"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"\tTAB", "'\tTAB"},
+		{"\rCR", "'\rCR"},
+		{"-", "'-"},
+		{"-4.50", "-4.50"},
+		{"-4,50", "-4,50"},
+		{"-2459", "-2459"},
+		{"CARD PAYMENT TESCO", "CARD PAYMENT TESCO"},
+		{"TESCO = GROCERIES", "TESCO = GROCERIES"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := sanitizeCell(tt.input); got != tt.expected {
+			t.Errorf("sanitizeCell(%q): got %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCSVWriter_Sanitize(t *testing.T) {
+	info := &models.StatementInfo{
+		AccountHolder: "=cmd|' /C calc'!A0",
+		Transactions: []models.Transaction{
+			{Date: "15/01/2024", Description: `=HYPERLINK("http://evil.example","Refund")`, Type: "DEBIT", Amount: models.Pence(2599), Balance: models.Pence(-123456)},
+			{Date: "16/01/2024", Description: "@SUM(1+1)", Type: "CREDIT", Amount: models.Pence(250000), Balance: models.Pence(126544)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (&CSVWriter{IncludeHeader: true, Sanitize: true}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# Account Holder,'=cmd|' /C calc'!A0\n" +
+		"Date,Description,Type,Amount,Balance\n" +
+		"15/01/2024,\"'=HYPERLINK(\"\"http://evil.example\"\",\"\"Refund\"\")\",DEBIT,25.99,-1234.56\n" +
+		"16/01/2024,'@SUM(1+1),CREDIT,2500.00,1265.44\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// The csv format used by the CLI passes Sanitize through
+	buf.Reset()
+	csvFormat, _ := LookupFormat("csv")
+	if err := csvFormat.New(Options{Sanitize: true}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n16/01/2024,'@SUM(1+1),") {
+		t.Errorf("csv format output not sanitised:\n%s", buf.String())
+	}
+
+	// Signed amounts in presets stay numbers
+	buf.Reset()
+	xero, _ := LookupFormat("xero")
+	if err := xero.New(Options{Sanitize: true}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), ",-25.99,\"'=HYPERLINK(") {
+		t.Errorf("preset output not sanitised as expected:\n%s", buf.String())
+	}
+
+	// Off by default
+	buf.Reset()
+	if err := (&CSVWriter{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n16/01/2024,@SUM(1+1),") {
+		t.Errorf("cells should be unchanged without Sanitize:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,53 @@
+package writer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// dateFormatPresets are the named output date formats.
+var dateFormatPresets = map[string]string{
+	"iso": "2006-01-02",
+	"uk":  "02/01/2006",
+	"us":  "01/02/2006",
+}
+
+// DateLayout resolves an output date format: "" (dates as printed on the
+// statement), a preset name ("iso", "uk", "us") or a Go time layout such as
+// "02 Jan 2006".
+func DateLayout(format string) (string, error) {
+	if format == "" {
+		return "", nil
+	}
+	if layout, ok := dateFormatPresets[strings.ToLower(format)]; ok {
+		return layout, nil
+	}
+	// A layout without any date element formats to itself
+	probe := time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if probe.Format(format) == format {
+		return "", fmt.Errorf("invalid date format %q: use iso, uk, us or a Go layout such as 02/01/2006", format)
+	}
+	return format, nil
+}
+
+// formatDate formats a resolved date with layout, falling back to the date
+// as printed when no layout is set or the date could not be resolved.
+func formatDate(raw string, d models.Date, layout string) string {
+	if layout == "" || d.IsZero() {
+		return raw
+	}
+	return d.Format(layout)
+}
+
+// ApplyDateFormat rewrites each transaction's Date and PostingDate in
+// layout, for outputs (the JSON response) that carry the date strings
+// directly.
+func ApplyDateFormat(txns []models.Transaction, layout string) {
+	for i := range txns {
+		txns[i].Date = formatDate(txns[i].Date, txns[i].ISODate, layout)
+		txns[i].PostingDate = formatDate(txns[i].PostingDate, txns[i].ISOPostingDate, layout)
+	}
+}
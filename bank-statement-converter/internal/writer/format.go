@@ -0,0 +1,181 @@
+package writer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// StatementWriter writes a parsed statement in one output format.
+type StatementWriter interface {
+	Write(out io.Writer, info *models.StatementInfo) error
+}
+
+// Options are the output settings shared by the CLI and the API. Each
+// format uses the ones that apply to it.
+type Options struct {
+	IncludeHeader  bool            // CSV: account metadata rows before the header
+	DateFormat     string          // CSV: Go layout for dates (see DateLayout)
+	CSVSchema      *CSVSchema      // CSV: columns and formatting; nil for the default
+	Sanitize       bool            // CSV: neutralise cells that would run as formulas
+	DebugLines     bool            // JSON: include the parser's debug lines
+	LedgerAccounts *LedgerAccounts // beancount, ledger: account names; nil for the defaults
+}
+
+// Format is an output format selectable with --format or the API's format
+// field.
+type Format struct {
+	Name        string
+	Extension   string // output file extension, including the dot
+	ContentType string
+	Binary      bool // not text; the API returns it base64-encoded
+	New         func(opts Options) StatementWriter
+}
+
+// formats lists the output formats; the first is the default.
+var formats = []Format{
+	{
+		Name:        "csv",
+		Extension:   ".csv",
+		ContentType: "text/csv",
+		New: func(opts Options) StatementWriter {
+			return &CSVWriter{IncludeHeader: opts.IncludeHeader, DateFormat: opts.DateFormat, Schema: opts.CSVSchema, Sanitize: opts.Sanitize}
+		},
+	},
+	{
+		Name:        "ofx",
+		Extension:   ".ofx",
+		ContentType: "application/x-ofx",
+		New:         func(Options) StatementWriter { return &OFXWriter{} },
+	},
+	{
+		Name:        "qfx",
+		Extension:   ".qfx",
+		ContentType: "application/vnd.intu.qfx",
+		New:         func(Options) StatementWriter { return &OFXWriter{QFX: true} },
+	},
+	{
+		Name:        "qif",
+		Extension:   ".qif",
+		ContentType: "application/qif",
+		New:         func(opts Options) StatementWriter { return &QIFWriter{DateFormat: opts.DateFormat} },
+	},
+	{
+		Name:        "camt053",
+		Extension:   ".xml",
+		ContentType: "application/xml",
+		New:         func(Options) StatementWriter { return &Camt053Writer{} },
+	},
+	{
+		Name:        "mt940",
+		Extension:   ".sta",
+		ContentType: "text/plain",
+		New:         func(Options) StatementWriter { return &MT940Writer{} },
+	},
+	{
+		Name:        "xlsx",
+		Extension:   ".xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Binary:      true,
+		New:         func(opts Options) StatementWriter { return &XLSXWriter{DateFormat: opts.DateFormat} },
+	},
+	{
+		Name:        "report",
+		Extension:   ".html",
+		ContentType: "text/html; charset=utf-8",
+		New:         func(opts Options) StatementWriter { return &ReportWriter{DateFormat: opts.DateFormat} },
+	},
+	{
+		Name:        "report-pdf",
+		Extension:   ".pdf",
+		ContentType: "application/pdf",
+		Binary:      true,
+		New:         func(opts Options) StatementWriter { return &ReportWriter{PDF: true, DateFormat: opts.DateFormat} },
+	},
+	{
+		Name:        "beancount",
+		Extension:   ".beancount",
+		ContentType: "text/plain",
+		New:         func(opts Options) StatementWriter { return &BeancountWriter{Accounts: opts.LedgerAccounts} },
+	},
+	{
+		Name:        "ledger",
+		Extension:   ".ledger",
+		ContentType: "text/plain",
+		New:         func(opts Options) StatementWriter { return &LedgerWriter{Accounts: opts.LedgerAccounts} },
+	},
+}
+
+// init registers each CSV preset as an output format.
+func init() {
+	for _, p := range csvPresets {
+		formats = append(formats, Format{
+			Name:        p.Name,
+			Extension:   ".csv",
+			ContentType: "text/csv",
+			New: func(opts Options) StatementWriter {
+				return &CSVWriter{DateFormat: opts.DateFormat, Preset: p, Sanitize: opts.Sanitize}
+			},
+		})
+	}
+}
+
+// RegisterFormat adds an output format implemented outside this package,
+// such as the API's JSON response document. Call it before any lookups,
+// at the start of main.
+func RegisterFormat(f Format) {
+	formats = append(formats, f)
+}
+
+// LookupFormat returns the output format with the given name
+// (case-insensitive). An empty name is CSV.
+func LookupFormat(name string) (Format, error) {
+	if name == "" {
+		return formats[0], nil
+	}
+	for _, f := range formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, nil
+		}
+	}
+	return Format{}, fmt.Errorf("unknown output format %q: use %s", name, strings.Join(FormatNames(), ", "))
+}
+
+// FormatNames returns the names of every output format.
+func FormatNames() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// WriteFile writes the statement to a file at path; "-" is standard
+// output.
+func WriteFile(path string, w StatementWriter, info *models.StatementInfo) error {
+	return writeFile(path, func(out io.Writer) error { return w.Write(out, info) })
+}
+
+// WriteAllFile writes several statements to one file at path; "-" is
+// standard output.
+func WriteAllFile(path string, w MultiStatementWriter, infos []*models.StatementInfo) error {
+	return writeFile(path, func(out io.Writer) error { return w.WriteAll(out, infos) })
+}
+
+func writeFile(path string, write func(io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file %q: %w", path, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,60 @@
+package writer
+
+import (
+	"fmt"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// ukBankCodes are the four-letter bank codes (the start of the bank's BIC)
+// that UK IBANs carry before the sort code.
+var ukBankCodes = map[models.BankType]string{
+	models.BankMetro:      "MYMB",
+	models.BankHSBC:       "HBUK",
+	models.BankBarclays:   "BARC",
+	models.BankLloyds:     "LOYD",
+	models.BankNatWest:    "NWBK",
+	models.BankRBS:        "RBOS",
+	models.BankSantander:  "ABBY",
+	models.BankNationwide: "NAIA",
+	models.BankMonzo:      "MONZ",
+	models.BankStarling:   "SRLG",
+	models.BankRevolut:    "REVO",
+}
+
+// ukIBAN builds a GB IBAN from the bank's code, a sort code and an account
+// number. It returns "" when the bank has no code or the sort code and
+// account number are not 6 and 8 digits.
+func ukIBAN(bank models.BankType, sortCode, accountNumber string) string {
+	code := ukBankCodes[bank]
+	sortCode = models.DigitsOnly(sortCode)
+	accountNumber = models.DigitsOnly(accountNumber)
+	if code == "" || len(sortCode) != 6 || len(accountNumber) != 8 {
+		return ""
+	}
+	bban := code + sortCode + accountNumber
+	return "GB" + ibanCheckDigits("GB", bban) + bban
+}
+
+// ibanCheckDigits computes the ISO 13616 check digits: the BBAN followed
+// by the country code and "00", letters as 10-35, taken mod 97.
+func ibanCheckDigits(country, bban string) string {
+	rem := 0
+	for _, r := range bban + country + "00" {
+		var v int
+		switch {
+		case r >= '0' && r <= '9':
+			v = int(r - '0')
+		case r >= 'A' && r <= 'Z':
+			v = int(r-'A') + 10
+		default:
+			continue
+		}
+		if v >= 10 {
+			rem = (rem*100 + v) % 97
+		} else {
+			rem = (rem*10 + v) % 97
+		}
+	}
+	return fmt.Sprintf("%02d", 98-rem)
+}
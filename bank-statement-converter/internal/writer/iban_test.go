@@ -0,0 +1,39 @@
+package writer
+
+import (
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestIBANCheckDigits(t *testing.T) {
+	// Published examples from the IBAN registry
+	tests := []struct{ bban, want string }{
+		{"NWBK60161331926819", "29"},
+		{"WEST12345698765432", "82"},
+	}
+	for _, tt := range tests {
+		if got := ibanCheckDigits("GB", tt.bban); got != tt.want {
+			t.Errorf("ibanCheckDigits(GB, %s) = %s, want %s", tt.bban, got, tt.want)
+		}
+	}
+}
+
+func TestUKIBAN(t *testing.T) {
+	tests := []struct {
+		bank              models.BankType
+		sortCode, account string
+		want              string
+	}{
+		{models.BankNatWest, "60-16-13", "31926819", "GB29NWBK60161331926819"},
+		{models.BankBarclays, "20-00-00", "12345678", "GB74BARC20000012345678"},
+		{models.BankBarclays, "20-00-00", "1234567", ""}, // short account number
+		{models.BankAmex, "", "XXXX-XXXXX6-71005", ""},   // no bank code
+		{"", "60-16-13", "31926819", ""},
+	}
+	for _, tt := range tests {
+		if got := ukIBAN(tt.bank, tt.sortCode, tt.account); got != tt.want {
+			t.Errorf("ukIBAN(%s, %s, %s) = %q, want %q", tt.bank, tt.sortCode, tt.account, got, tt.want)
+		}
+	}
+}
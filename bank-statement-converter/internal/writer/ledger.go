@@ -0,0 +1,291 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// Default counter accounts for the plain-text accounting formats.
+const (
+	defaultExpensesAccount = "Expenses:Uncategorized"
+	defaultIncomeAccount   = "Income:Uncategorized"
+	openingBalancesAccount = "Equity:Opening-Balances"
+)
+
+// ledgerRoots are the top-level account names beancount accepts.
+var ledgerRoots = []string{"Assets", "Liabilities", "Equity", "Income", "Expenses"}
+
+// LedgerAccounts names the accounts the beancount and ledger writers post
+// to. Loaded from JSON with LoadLedgerAccounts (--ledger-accounts):
+//
+//	{
+//	  "accounts": {
+//	    "20-00-00 12345678": "Assets:Barclays:Business",
+//	    "XXXX-XXXXXX-51005": "Liabilities:Amex"
+//	  },
+//	  "expenses": "Expenses:Uncategorized",
+//	  "income": "Income:Uncategorized"
+//	}
+type LedgerAccounts struct {
+	// Accounts maps a sort code and account number, or an account or card
+	// number alone, to the statement's asset (or, for cards, liability)
+	// account. Spaces and dashes in the keys are ignored. Statements not
+	// listed get Assets:<Bank>:<last four digits>, or Liabilities:... for
+	// cards.
+	Accounts map[string]string `json:"accounts,omitempty"`
+	// Expenses and Income are the other side of money out and money in.
+	Expenses string `json:"expenses,omitempty"`
+	Income   string `json:"income,omitempty"`
+}
+
+// LoadLedgerAccounts reads a JSON account mapping from path.
+func LoadLedgerAccounts(path string) (*LedgerAccounts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger accounts %q: %w", path, err)
+	}
+	var a LedgerAccounts
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&a); err != nil {
+		return nil, fmt.Errorf("invalid ledger accounts %q: %w", path, err)
+	}
+	if err := a.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ledger accounts %q: %w", path, err)
+	}
+	return &a, nil
+}
+
+// Validate checks the keys and account names, and normalises the keys to
+// digits.
+func (a *LedgerAccounts) Validate() error {
+	accounts := make(map[string]string, len(a.Accounts))
+	for key, name := range a.Accounts {
+		digits := models.DigitsOnly(key)
+		if digits == "" {
+			return fmt.Errorf("account key %q: use a sort code and account number, or an account number", key)
+		}
+		if err := validateLedgerAccount(name); err != nil {
+			return err
+		}
+		accounts[digits] = name
+	}
+	a.Accounts = accounts
+	for _, name := range []string{a.Expenses, a.Income} {
+		if name == "" {
+			continue
+		}
+		if err := validateLedgerAccount(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateLedgerAccount checks that name is an account both beancount and
+// ledger accept: a root such as Assets and at least one more component, each
+// starting with a capital letter or digit, without spaces.
+func validateLedgerAccount(name string) error {
+	parts := strings.Split(name, ":")
+	valid := len(parts) >= 2
+	for i, part := range parts {
+		if !valid {
+			break
+		}
+		r := []rune(part)
+		valid = len(r) > 0 && (unicode.IsUpper(r[0]) || unicode.IsDigit(r[0])) && !strings.ContainsAny(part, " \t\"")
+		if i == 0 {
+			valid = valid && containsString(ledgerRoots, part)
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid account %q: use %s followed by capitalised components, e.g. Assets:Barclays:Current", name, strings.Join(ledgerRoots, ", "))
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// statementAccount is the statement's own account: from the mapping (sort
+// code and account number first, then the account number alone), else
+// derived from the bank and account number.
+func (a *LedgerAccounts) statementAccount(info *models.StatementInfo) string {
+	number := models.DigitsOnly(info.AccountNumber)
+	if a != nil && number != "" {
+		if name, ok := a.Accounts[models.DigitsOnly(info.SortCode)+number]; ok {
+			return name
+		}
+		if name, ok := a.Accounts[number]; ok {
+			return name
+		}
+	}
+
+	root := "Assets"
+	if info.CreditCard {
+		root = "Liabilities"
+	}
+	account := root + ":" + ledgerComponent(string(info.Bank))
+	if len(number) > 4 {
+		number = number[len(number)-4:]
+	}
+	if number != "" {
+		account += ":" + number
+	}
+	return account
+}
+
+// counterAccount is the account on the other side of a transaction.
+func (a *LedgerAccounts) counterAccount(amount models.Money) string {
+	if amount.IsNegative() {
+		if a != nil && a.Expenses != "" {
+			return a.Expenses
+		}
+		return defaultExpensesAccount
+	}
+	if a != nil && a.Income != "" {
+		return a.Income
+	}
+	return defaultIncomeAccount
+}
+
+// ledgerComponent turns a bank name into an account name component:
+// letters, digits and dashes, starting with a capital.
+func ledgerComponent(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = b.Len() > 0
+			continue
+		}
+		if b.Len() == 0 {
+			r = unicode.ToUpper(r)
+		} else if dash {
+			b.WriteByte('-')
+		}
+		dash = false
+		b.WriteRune(r)
+	}
+	if b.Len() == 0 {
+		return "Bank"
+	}
+	return b.String()
+}
+
+// ledgerStatement is a statement as the plain-text accounting formats
+// write it: its account, the date it is opened on and its per-currency
+// parts.
+type ledgerStatement struct {
+	account string
+	open    models.Date
+	parts   []*currencyStatement
+}
+
+// newLedgerStatement splits the statement by currency and dates the
+// account's opening from the printed statement period, or the first
+// transaction when it is later.
+func newLedgerStatement(info *models.StatementInfo, accounts *LedgerAccounts, format string) (*ledgerStatement, error) {
+	parts, err := splitByCurrency(info, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("statement has no transactions; %s needs at least one dated entry", format)
+	}
+	s := &ledgerStatement{account: accounts.statementAccount(info), open: info.PeriodStart, parts: parts}
+	for _, p := range parts {
+		if s.open.IsZero() || p.start.Before(s.open.Time) {
+			s.open = p.start
+		}
+	}
+	return s, nil
+}
+
+// hasBalance reports whether the transaction carries a printed running
+// balance.
+func hasBalance(txn models.Transaction) bool {
+	return !txn.Balance.IsZero()
+}
+
+// ledgerAmount formats an amount with its commodity.
+func ledgerAmount(m models.Money, currency string) string {
+	return m.String() + " " + currency
+}
+
+// LedgerWriter writes a statement as a ledger-cli journal, which hledger
+// also reads: an account declaration, an opening balance transaction and
+// one transaction per statement transaction. Printed running balances
+// become balance assertions on the statement account's posting; statements
+// without them (cards) end with an assertion of the closing balance.
+// Every transaction needs a resolved date (models.Transaction.ISODate).
+type LedgerWriter struct {
+	Accounts *LedgerAccounts // nil for the default account names
+}
+
+// Write writes the statement as a ledger journal to out.
+func (w *LedgerWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	s, err := newLedgerStatement(info, w.Accounts, "ledger")
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(out)
+	fmt.Fprintf(bw, "account %s\n", s.account)
+	for _, p := range s.parts {
+		if p.opening.IsZero() {
+			continue
+		}
+		fmt.Fprintf(bw, "\n%s Opening balance\n", ledgerDate(s.open))
+		fmt.Fprintf(bw, "    %s  %s\n", s.account, ledgerAmount(p.opening, p.currency))
+		fmt.Fprintf(bw, "    %s\n", openingBalancesAccount)
+	}
+	for _, p := range s.parts {
+		for _, txn := range p.txns {
+			amount := txn.SignedAmount()
+			fmt.Fprintf(bw, "\n%s %s\n", ledgerDate(txn.ISODate), ledgerPayee(txn.Description))
+			posting := ledgerAmount(amount, p.currency)
+			if hasBalance(txn) {
+				posting += " = " + ledgerAmount(txn.Balance, p.currency)
+			}
+			fmt.Fprintf(bw, "    %s  %s\n", s.account, posting)
+			fmt.Fprintf(bw, "    %s\n", w.Accounts.counterAccount(amount))
+		}
+		if !hasBalance(p.txns[len(p.txns)-1]) {
+			fmt.Fprintf(bw, "\n%s Closing balance\n", ledgerDate(p.end))
+			fmt.Fprintf(bw, "    %s  %s = %s\n", s.account, ledgerAmount(models.Money{}, p.currency), ledgerAmount(p.closing, p.currency))
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return nil
+}
+
+func ledgerDate(d models.Date) string {
+	return d.Format("2006/01/02")
+}
+
+// ledgerPayee keeps a description on one line and stops it being read as a
+// cleared (*) or pending (!) mark or a (code).
+func ledgerPayee(desc string) string {
+	payee := strings.TrimLeft(qifText(desc), "*!( ")
+	if payee == "" {
+		return "Unknown"
+	}
+	return payee
+}
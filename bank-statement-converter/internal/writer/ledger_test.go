@@ -0,0 +1,136 @@
+package writer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// ledgerStatementFixture is the golden statement with its period resolved,
+// as the parsers leave it.
+func ledgerStatementFixture() *models.StatementInfo {
+	info := goldenStatement()
+	info.PeriodStart = models.NewDate(2025, 12, 4)
+	info.PeriodEnd = models.NewDate(2026, 1, 5)
+	return info
+}
+
+func TestLedgerWriter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&LedgerWriter{}).Write(&buf, ledgerStatementFixture()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "statement.ledger", buf.Bytes())
+}
+
+func TestBeancountWriter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&BeancountWriter{}).Write(&buf, ledgerStatementFixture()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "statement.beancount", buf.Bytes())
+}
+
+func TestLedgerWriters_CreditCard(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank:             models.BankAmex,
+		AccountNumber:    "XXXX-XXXXXX-51005",
+		CreditCard:       true,
+		OpeningBalance:   models.Pence(10000),
+		StatementBalance: models.Pence(7599),
+		PeriodStart:      models.NewDate(2024, 1, 1),
+		Transactions: []models.Transaction{
+			{ISODate: models.NewDate(2024, 1, 2), Description: "TESCO STORES", Type: "DEBIT", Amount: models.Pence(2599)},
+			{ISODate: models.NewDate(2024, 1, 15), Description: "PAYMENT RECEIVED", Type: "CREDIT", Amount: models.Pence(5000)},
+		},
+	}
+	accounts := &LedgerAccounts{Accounts: map[string]string{"51005": "Liabilities:Amex:Gold"}, Expenses: "Expenses:Card"}
+	if err := accounts.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&BeancountWriter{Accounts: accounts}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"2024-01-01 open Liabilities:Amex:Gold GBP\n",
+		"  Liabilities:Amex:Gold  -100.00 GBP\n", // amount owed
+		"  Liabilities:Amex:Gold  -25.99 GBP\n  Expenses:Card\n",
+		"  Liabilities:Amex:Gold  50.00 GBP\n  Income:Uncategorized\n",
+		"2024-01-16 balance Liabilities:Amex:Gold  -75.99 GBP\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("beancount: expected %q in:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	if err := (&LedgerWriter{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"account Liabilities:Amex:1005\n",
+		"2024/01/15 Closing balance\n    Liabilities:Amex:1005  0.00 GBP = -75.99 GBP\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("ledger: expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestLedgerWriters_UnresolvedDate(t *testing.T) {
+	info := &models.StatementInfo{
+		Transactions: []models.Transaction{{Date: "32/13", Description: "X", Type: "DEBIT", Amount: models.Pence(100)}},
+	}
+	for _, w := range []StatementWriter{&LedgerWriter{}, &BeancountWriter{}} {
+		if err := w.Write(&bytes.Buffer{}, info); err == nil {
+			t.Errorf("%T: expected an error for an unresolved date", w)
+		}
+	}
+}
+
+func TestLoadLedgerAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounts.json")
+	os.WriteFile(path, []byte(`{"accounts": {"20-00-00 12345678": "Assets:Barclays:Business"}, "income": "Income:Sales"}`), 0o644)
+	accounts, err := LoadLedgerAccounts(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := accounts.statementAccount(goldenStatement()); got != "Assets:Barclays:Business" {
+		t.Errorf("account: got %q", got)
+	}
+	if got := accounts.counterAccount(models.Pence(100)); got != "Income:Sales" {
+		t.Errorf("income account: got %q", got)
+	}
+
+	for _, bad := range []string{
+		`{"accounts": {"12345678": "Barclays:Business"}}`, // no root
+		`{"accounts": {"12345678": "Assets:my bank"}}`,
+		`{"accounts": {"current": "Assets:Barclays"}}`,
+		`{"expenses": "Expenses"}`,
+		`{"account": {}}`,
+	} {
+		os.WriteFile(path, []byte(bad), 0o644)
+		if _, err := LoadLedgerAccounts(path); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
+
+func TestLedgerComponent(t *testing.T) {
+	for in, want := range map[string]string{
+		"barclays":      "Barclays",
+		"my bank (uk)":  "My-bank-uk",
+		"":              "Bank",
+		"natwest-trial": "Natwest-trial",
+	} {
+		if got := ledgerComponent(in); got != want {
+			t.Errorf("ledgerComponent(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package writer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// mt940DateLayout is the SWIFT date format (YYMMDD).
+const mt940DateLayout = "060102"
+
+// mt940 :86: narrative limits: six lines of 65 characters.
+const (
+	mt940LineLen  = 65
+	mt940MaxLines = 6
+)
+
+// MT940Writer writes a statement as a SWIFT MT940 customer statement
+// message: the text block's fields (:20: to :62F:) ending with "-".
+// Multi-currency statements get one message per currency, numbered in
+// :28C:.
+//
+// :25: carries the IBAN when one can be built from the sort code and
+// account number, otherwise the sort code and account number (or card
+// number). Descriptions are restricted to the SWIFT character set. Every
+// transaction needs a resolved date (models.Transaction.ISODate).
+type MT940Writer struct{}
+
+// Write writes the statement as MT940 to out.
+func (w *MT940Writer) Write(out io.Writer, info *models.StatementInfo) error {
+	parts, err := splitByCurrency(info, "MT940")
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("statement has no transactions; MT940 needs at least one dated entry")
+	}
+
+	account := statementIBAN(info)
+	if account == "" {
+		account = statementAccountID(info)
+	}
+	bw := bufio.NewWriter(out)
+	for i, p := range parts {
+		fmt.Fprintf(bw, ":20:%s\n", truncateRunes(mt940Text("STMT"+p.end.Format("20060102")), 16))
+		fmt.Fprintf(bw, ":25:%s\n", truncateRunes(mt940Text(account), 35))
+		fmt.Fprintf(bw, ":28C:1/%d\n", i+1)
+		fmt.Fprintf(bw, ":60F:%s\n", mt940Balance(p.opening, p.start, p.currency))
+		for _, txn := range p.txns {
+			fmt.Fprintf(bw, ":61:%s\n", mt940Entry(txn))
+			for j, line := range mt940Narrative(txn.Description) {
+				if j == 0 {
+					fmt.Fprintf(bw, ":86:%s\n", line)
+				} else {
+					fmt.Fprintln(bw, line)
+				}
+			}
+		}
+		fmt.Fprintf(bw, ":62F:%s\n", mt940Balance(p.closing, p.end, p.currency))
+		fmt.Fprintln(bw, "-")
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write MT940: %w", err)
+	}
+	return nil
+}
+
+// mt940Balance formats a :60F:/:62F: balance: C or D mark, date, currency
+// and amount. An overdrawn balance or amount owed is D.
+func mt940Balance(amount models.Money, date models.Date, currency string) string {
+	return mt940Mark(amount) + date.Format(mt940DateLayout) + currency + mt940Amount(amount)
+}
+
+// mt940Entry formats a :61: statement line: value date, entry date (MMDD),
+// C or D mark, amount, a miscellaneous transaction type and no reference.
+func mt940Entry(txn models.Transaction) string {
+	entry := txn.ISODate
+	// Card statements post after the transaction date
+	if !txn.ISOPostingDate.IsZero() {
+		entry = txn.ISOPostingDate
+	}
+	amount := txn.SignedAmount()
+	return txn.ISODate.Format(mt940DateLayout) + entry.Format("0102") + mt940Mark(amount) +
+		mt940Amount(amount) + "NMSCNONREF"
+}
+
+func mt940Mark(amount models.Money) string {
+	if amount.IsNegative() {
+		return "D"
+	}
+	return "C"
+}
+
+// mt940Amount formats an unsigned amount with a decimal comma.
+func mt940Amount(amount models.Money) string {
+	return strings.Replace(amount.Abs().String(), ".", ",", 1)
+}
+
+// mt940Narrative splits a description into :86: lines.
+func mt940Narrative(desc string) []string {
+	text := strings.Join(strings.Fields(mt940Text(desc)), " ")
+	if text == "" {
+		return []string{"NONREF"}
+	}
+	var lines []string
+	for text != "" && len(lines) < mt940MaxLines {
+		// A continuation line starting with ':' or '-' would read as a new
+		// field or the end of the message, so it gets a leading space and
+		// its last character moves to the next line
+		prefix := ""
+		if len(lines) > 0 && (text[0] == ':' || text[0] == '-') {
+			prefix = " "
+		}
+		n := min(len(text), mt940LineLen-len(prefix))
+		lines = append(lines, prefix+text[:n])
+		text = text[n:]
+	}
+	return lines
+}
+
+// mt940Text replaces characters outside the SWIFT X character set with
+// spaces.
+func mt940Text(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("/-?:().,'+ ", r):
+			return r
+		}
+		return ' '
+	}, s)
+}
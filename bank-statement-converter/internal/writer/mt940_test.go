@@ -0,0 +1,79 @@
+package writer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestMT940Writer_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&MT940Writer{}).Write(&buf, goldenStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "statement.sta", buf.Bytes())
+}
+
+func TestMT940Writer_Overdrawn(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank:          models.BankHSBC,
+		AccountNumber: "12345678",
+		Transactions: []models.Transaction{
+			{ISODate: models.NewDate(2024, 1, 2), Description: "Rent – flat 2 & parking", Type: "DEBIT", Amount: models.Pence(120000), Balance: models.Pence(-20000)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&MT940Writer{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		":25:12345678\n", // no sort code, so no IBAN
+		":60F:C240102GBP1000,00\n",
+		":61:2401020102D1200,00NMSCNONREF\n",
+		":86:Rent flat 2 parking\n",
+		":62F:D240102GBP200,00\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestMT940Narrative(t *testing.T) {
+	lines := mt940Narrative(strings.Repeat("x", 64) + " -ref" + strings.Repeat("y", 500))
+	if len(lines) != mt940MaxLines {
+		t.Fatalf("expected %d lines, got %d", mt940MaxLines, len(lines))
+	}
+	for i, line := range lines {
+		if len(line) > mt940LineLen {
+			t.Errorf("line %d is %d characters", i, len(line))
+		}
+		if i > 0 && (line[0] == ':' || line[0] == '-') {
+			t.Errorf("line %d starts with %q", i, line[0])
+		}
+	}
+}
+
+func TestMT940Narrative_KeepsLeadingCharacter(t *testing.T) {
+	for _, c := range []string{":", "-"} {
+		desc := strings.Repeat("x", 65) + c + "REF" + strings.Repeat("y", 70)
+		lines := mt940Narrative(desc)
+		if len(lines) != 3 {
+			t.Fatalf("%q: expected 3 lines, got %d: %q", c, len(lines), lines)
+		}
+		if want := " " + c + "REF"; !strings.HasPrefix(lines[1], want) {
+			t.Errorf("%q: line 1 = %q, want prefix %q", c, lines[1], want)
+		}
+		for i, line := range lines {
+			if len(line) > mt940LineLen {
+				t.Errorf("%q: line %d is %d characters", c, i, len(line))
+			}
+		}
+		if got := lines[0] + lines[1][1:] + lines[2]; got != desc {
+			t.Errorf("%q: narrative lost text:\ngot  %q\nwant %q", c, got, desc)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package writer
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// OFXWriter writes a statement as an OFX 2.2 document: a BANKMSGSRSV1
+// statement for current accounts, CREDITCARDMSGSRSV1 for credit cards.
+// Multi-currency statements get one statement response per currency.
+//
+// Every transaction needs a resolved date (models.Transaction.ISODate).
+// FITIDs are derived from the date, amount and description, so importing
+// the same statement twice does not duplicate transactions.
+type OFXWriter struct {
+	// QFX adds the Intuit institution block Quicken expects.
+	QFX bool
+	// IntuBID is the Quicken institution ID written with QFX; defaults to
+	// defaultIntuBID.
+	IntuBID string
+}
+
+// defaultIntuBID is the institution ID written when none is configured.
+const defaultIntuBID = "00000"
+
+// ofxNameLen is the maximum length of an OFX <NAME>; longer descriptions
+// also go in full in <MEMO>.
+const ofxNameLen = 32
+
+// ofxDateLayout is the OFX date format (YYYYMMDD).
+const ofxDateLayout = "20060102"
+
+type ofxDocument struct {
+	XMLName xml.Name     `xml:"OFX"`
+	SignOn  ofxSignOn    `xml:"SIGNONMSGSRSV1>SONRS"`
+	Bank    *ofxBankMsgs `xml:"BANKMSGSRSV1,omitempty"`
+	Card    *ofxCardMsgs `xml:"CREDITCARDMSGSRSV1,omitempty"`
+}
+
+type ofxBankMsgs struct {
+	Statements []ofxStmtTrn `xml:"STMTTRNRS"`
+}
+
+type ofxCardMsgs struct {
+	Statements []ofxCCStmtTrn `xml:"CCSTMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	DTServer string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+	FI       *ofxFI    `xml:"FI,omitempty"`
+	IntuBID  string    `xml:"INTU.BID,omitempty"`
+}
+
+type ofxFI struct {
+	Org string `xml:"ORG"`
+	FID string `xml:"FID"`
+}
+
+type ofxStmtTrn struct {
+	TrnUID string    `xml:"TRNUID"`
+	Status ofxStatus `xml:"STATUS"`
+	Stmt   struct {
+		CurDef   string         `xml:"CURDEF"`
+		Account  ofxBankAccount `xml:"BANKACCTFROM"`
+		TranList ofxTranList    `xml:"BANKTRANLIST"`
+		Ledger   ofxBalance     `xml:"LEDGERBAL"`
+	} `xml:"STMTRS"`
+}
+
+type ofxCCStmtTrn struct {
+	TrnUID string    `xml:"TRNUID"`
+	Status ofxStatus `xml:"STATUS"`
+	Stmt   struct {
+		CurDef   string       `xml:"CURDEF"`
+		Account  ofxCCAccount `xml:"CCACCTFROM"`
+		TranList ofxTranList  `xml:"BANKTRANLIST"`
+		Ledger   ofxBalance   `xml:"LEDGERBAL"`
+	} `xml:"CCSTMTRS"`
+}
+
+type ofxBankAccount struct {
+	BankID   string `xml:"BANKID"`
+	AcctID   string `xml:"ACCTID"`
+	AcctType string `xml:"ACCTTYPE"`
+}
+
+type ofxCCAccount struct {
+	AcctID string `xml:"ACCTID"`
+}
+
+type ofxTranList struct {
+	DTStart string       `xml:"DTSTART"`
+	DTEnd   string       `xml:"DTEND"`
+	Txns    []ofxStmtTxn `xml:"STMTTRN"`
+}
+
+type ofxStmtTxn struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	DTUser   string `xml:"DTUSER,omitempty"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+type ofxBalance struct {
+	BalAmt string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+// Write writes the statement as OFX (or QFX) to out.
+func (w *OFXWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	groups, err := splitByCurrency(info, "OFX")
+	if err != nil {
+		return err
+	}
+
+	doc := ofxDocument{
+		SignOn: ofxSignOn{
+			Status:   ofxStatus{Severity: "INFO"},
+			DTServer: ofxServerDate(groups),
+			Language: "ENG",
+		},
+	}
+	if w.QFX {
+		bid := w.IntuBID
+		if bid == "" {
+			bid = defaultIntuBID
+		}
+		org := string(info.Bank)
+		if org == "" {
+			org = "UNKNOWN"
+		}
+		doc.SignOn.FI = &ofxFI{Org: strings.ToUpper(org), FID: bid}
+		doc.SignOn.IntuBID = bid
+	}
+
+	acctID := info.AccountNumber
+	if acctID == "" {
+		acctID = "0"
+	}
+	bankID := strings.ReplaceAll(info.SortCode, "-", "")
+	if bankID == "" {
+		bankID = "0"
+	}
+
+	for i, g := range groups {
+		list := ofxTranList{DTStart: formatOFXDate(g.start), DTEnd: formatOFXDate(g.end)}
+		list.Txns = ofxTransactions(g.txns)
+		ledger := ofxBalance{BalAmt: g.closing.String(), DTAsOf: formatOFXDate(g.end)}
+		trnUID := fmt.Sprint(i)
+
+		if info.CreditCard {
+			if doc.Card == nil {
+				doc.Card = &ofxCardMsgs{}
+			}
+			var rs ofxCCStmtTrn
+			rs.TrnUID, rs.Status = trnUID, ofxStatus{Severity: "INFO"}
+			rs.Stmt.CurDef = g.currency
+			rs.Stmt.Account = ofxCCAccount{AcctID: acctID}
+			rs.Stmt.TranList, rs.Stmt.Ledger = list, ledger
+			doc.Card.Statements = append(doc.Card.Statements, rs)
+			continue
+		}
+		if doc.Bank == nil {
+			doc.Bank = &ofxBankMsgs{}
+		}
+		var rs ofxStmtTrn
+		rs.TrnUID, rs.Status = trnUID, ofxStatus{Severity: "INFO"}
+		rs.Stmt.CurDef = g.currency
+		rs.Stmt.Account = ofxBankAccount{BankID: bankID, AcctID: acctID, AcctType: "CHECKING"}
+		rs.Stmt.TranList, rs.Stmt.Ledger = list, ledger
+		doc.Bank.Statements = append(doc.Bank.Statements, rs)
+	}
+
+	if _, err := io.WriteString(out, xml.Header+
+		`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`+"\n"); err != nil {
+		return fmt.Errorf("failed to write OFX header: %w", err)
+	}
+	enc := xml.NewEncoder(out)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write OFX: %w", err)
+	}
+	_, err = io.WriteString(out, "\n")
+	return err
+}
+
+// ofxTransactions converts transactions to STMTTRN records. Debits are
+// negative amounts. Identical transactions on the same day get FITIDs
+// numbered in statement order.
+func ofxTransactions(txns []models.Transaction) []ofxStmtTxn {
+	seen := make(map[string]int)
+	var out []ofxStmtTxn
+	for _, txn := range txns {
+		amount := txn.SignedAmount()
+		trnType := "CREDIT"
+		if txn.Type == "DEBIT" {
+			trnType = "DEBIT"
+		}
+
+		fitID := ofxFITID(txn.ISODate, amount, txn.Description)
+		seen[fitID]++
+		if n := seen[fitID]; n > 1 {
+			fitID = fmt.Sprintf("%s-%d", fitID, n)
+		}
+
+		record := ofxStmtTxn{
+			TrnType:  trnType,
+			DTPosted: formatOFXDate(txn.ISODate),
+			TrnAmt:   amount.String(),
+			FITID:    fitID,
+			Name:     ofxName(txn.Description),
+		}
+		// Card statements post after the transaction date
+		if !txn.ISOPostingDate.IsZero() {
+			record.DTPosted = formatOFXDate(txn.ISOPostingDate)
+			record.DTUser = formatOFXDate(txn.ISODate)
+		}
+		if record.Name != txn.Description {
+			record.Memo = txn.Description
+		}
+		out = append(out, record)
+	}
+	return out
+}
+
+// ofxFITID derives a transaction ID from the fields that identify a
+// transaction on the statement.
+func ofxFITID(date models.Date, amount models.Money, desc string) string {
+	sum := sha1.Sum([]byte(date.String() + "|" + amount.String() + "|" + strings.Join(strings.Fields(desc), " ")))
+	return hex.EncodeToString(sum[:])[:20]
+}
+
+// ofxName truncates a description to the length of an OFX <NAME>.
+func ofxName(desc string) string {
+	if desc == "" {
+		return "UNKNOWN"
+	}
+	runes := []rune(desc)
+	if len(runes) <= ofxNameLen {
+		return desc
+	}
+	return strings.TrimSpace(string(runes[:ofxNameLen]))
+}
+
+// ofxServerDate is the latest transaction date, so that writing the same
+// statement twice gives the same document; today for an empty statement.
+func ofxServerDate(groups []*currencyStatement) string {
+	var latest models.Date
+	for _, g := range groups {
+		if g.end.After(latest.Time) {
+			latest = g.end
+		}
+	}
+	if latest.IsZero() {
+		return time.Now().UTC().Format(ofxDateLayout)
+	}
+	return formatOFXDate(latest)
+}
+
+func formatOFXDate(d models.Date) string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(ofxDateLayout)
+}
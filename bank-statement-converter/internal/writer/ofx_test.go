@@ -0,0 +1,204 @@
+package writer
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func ofxStatement() *models.StatementInfo {
+	return &models.StatementInfo{
+		Bank:           models.BankHSBC,
+		AccountNumber:  "12345678",
+		SortCode:       "40-12-34",
+		OpeningBalance: models.Pence(102500),
+		ClosingBalance: models.Pence(347500),
+		Transactions: []models.Transaction{
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "BALANCE BROUGHT FORWARD", Type: "BALANCE", Balance: models.Pence(102500)},
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "COSTA", Type: "DEBIT", Amount: models.Pence(450)},
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "COSTA", Type: "DEBIT", Amount: models.Pence(450), Balance: models.Pence(101600)},
+			{Date: "31 Jan 24", ISODate: models.NewDate(2024, 1, 31), Description: "SALARY ACME WIDGETS LTD JANUARY 2024 PAYROLL", Type: "CREDIT", Amount: models.Pence(245900), Balance: models.Pence(347500)},
+		},
+	}
+}
+
+func writeOFX(t *testing.T, w *OFXWriter, info *models.StatementInfo) (string, ofxDocument) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := w.Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var doc ofxDocument
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+	return buf.String(), doc
+}
+
+func TestOFXWriter_Write(t *testing.T) {
+	output, doc := writeOFX(t, &OFXWriter{}, ofxStatement())
+
+	if !strings.Contains(output, `<?OFX OFXHEADER="200" VERSION="220"`) {
+		t.Error("expected OFX 2.2 processing instruction")
+	}
+	if doc.Bank == nil || len(doc.Bank.Statements) != 1 || doc.Card != nil {
+		t.Fatalf("expected one bank statement, got %+v / %+v", doc.Bank, doc.Card)
+	}
+	stmt := doc.Bank.Statements[0].Stmt
+	if stmt.Account.BankID != "401234" || stmt.Account.AcctID != "12345678" {
+		t.Errorf("account: got %+v", stmt.Account)
+	}
+	if stmt.CurDef != "GBP" {
+		t.Errorf("CURDEF: got %q", stmt.CurDef)
+	}
+	if stmt.TranList.DTStart != "20240102" || stmt.TranList.DTEnd != "20240131" {
+		t.Errorf("range: got %s to %s", stmt.TranList.DTStart, stmt.TranList.DTEnd)
+	}
+	if stmt.Ledger.BalAmt != "3475.00" || stmt.Ledger.DTAsOf != "20240131" {
+		t.Errorf("LEDGERBAL: got %+v", stmt.Ledger)
+	}
+
+	txns := stmt.TranList.Txns
+	if len(txns) != 3 {
+		t.Fatalf("expected 3 STMTTRN (balance row skipped), got %d", len(txns))
+	}
+	if txns[0].TrnType != "DEBIT" || txns[0].TrnAmt != "-4.50" || txns[0].DTPosted != "20240102" {
+		t.Errorf("txn[0]: got %+v", txns[0])
+	}
+	if txns[2].TrnType != "CREDIT" || txns[2].TrnAmt != "2459.00" {
+		t.Errorf("txn[2]: got %+v", txns[2])
+	}
+	if len(txns[2].Name) > ofxNameLen || txns[2].Memo != "SALARY ACME WIDGETS LTD JANUARY 2024 PAYROLL" {
+		t.Errorf("long description: NAME %q, MEMO %q", txns[2].Name, txns[2].Memo)
+	}
+
+	// Two identical coffees on the same day still get distinct IDs
+	if txns[0].FITID == txns[1].FITID {
+		t.Errorf("duplicate FITID %q", txns[0].FITID)
+	}
+	if !strings.HasPrefix(txns[1].FITID, txns[0].FITID) {
+		t.Errorf("repeat FITID %q should number %q", txns[1].FITID, txns[0].FITID)
+	}
+
+	// Re-exporting gives the same IDs, so imports de-duplicate
+	again, _ := writeOFX(t, &OFXWriter{}, ofxStatement())
+	if again != output {
+		t.Error("writing the same statement twice should give the same document")
+	}
+}
+
+func TestOFXWriter_QFX(t *testing.T) {
+	output, doc := writeOFX(t, &OFXWriter{QFX: true}, ofxStatement())
+
+	if doc.SignOn.FI == nil || doc.SignOn.FI.Org != "HSBC" {
+		t.Errorf("expected FI block, got %+v", doc.SignOn.FI)
+	}
+	if !strings.Contains(output, "<INTU.BID>"+defaultIntuBID+"</INTU.BID>") {
+		t.Errorf("expected INTU.BID:\n%s", output)
+	}
+}
+
+func TestOFXWriter_CreditCard(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank:             models.BankAmex,
+		AccountNumber:    "XXXX-XXXXX6-71005",
+		CreditCard:       true,
+		StatementBalance: models.Pence(2599),
+		Transactions: []models.Transaction{
+			{Date: "Jan 02", PostingDate: "Jan 03", ISODate: models.NewDate(2024, 1, 2), ISOPostingDate: models.NewDate(2024, 1, 3), Description: "TESCO STORES", Type: "DEBIT", Amount: models.Pence(2599)},
+		},
+	}
+	_, doc := writeOFX(t, &OFXWriter{}, info)
+
+	if doc.Card == nil || len(doc.Card.Statements) != 1 || doc.Bank != nil {
+		t.Fatalf("expected one card statement, got %+v / %+v", doc.Card, doc.Bank)
+	}
+	stmt := doc.Card.Statements[0].Stmt
+	if stmt.Account.AcctID != "XXXX-XXXXX6-71005" {
+		t.Errorf("ACCTID: got %q", stmt.Account.AcctID)
+	}
+	// The amount owed is a negative balance
+	if stmt.Ledger.BalAmt != "-25.99" {
+		t.Errorf("LEDGERBAL: got %q, want -25.99", stmt.Ledger.BalAmt)
+	}
+	txn := stmt.TranList.Txns[0]
+	if txn.DTPosted != "20240103" || txn.DTUser != "20240102" || txn.TrnAmt != "-25.99" {
+		t.Errorf("txn: got %+v", txn)
+	}
+}
+
+func TestOFXWriter_MultiCurrency(t *testing.T) {
+	info := &models.StatementInfo{
+		Bank: models.BankRevolut,
+		Transactions: []models.Transaction{
+			{ISODate: models.NewDate(2024, 1, 2), Description: "Tesco", Type: "DEBIT", Amount: models.Pence(2599), Balance: models.Pence(97401), Currency: "GBP"},
+			{ISODate: models.NewDate(2024, 1, 3), Description: "Cafe", Type: "DEBIT", Amount: models.Pence(450), Balance: models.Pence(9550), Currency: "EUR"},
+		},
+	}
+	_, doc := writeOFX(t, &OFXWriter{}, info)
+
+	if doc.Bank == nil || len(doc.Bank.Statements) != 2 {
+		t.Fatalf("expected one statement per currency, got %+v", doc.Bank)
+	}
+	eur := doc.Bank.Statements[1].Stmt
+	if eur.CurDef != "EUR" || eur.Ledger.BalAmt != "95.50" {
+		t.Errorf("EUR statement: got %s %s", eur.CurDef, eur.Ledger.BalAmt)
+	}
+}
+
+func TestOFXWriter_OpeningBalanceOnly(t *testing.T) {
+	// No running balances and no printed closing balance: the closing
+	// balance is the opening balance plus the movement
+	info := &models.StatementInfo{
+		Bank:           models.BankHSBC,
+		OpeningBalance: models.Pence(100000),
+		Transactions: []models.Transaction{
+			{ISODate: models.NewDate(2024, 1, 2), Description: "Tesco", Type: "DEBIT", Amount: models.Pence(2599)},
+			{ISODate: models.NewDate(2024, 1, 5), Description: "Refund", Type: "CREDIT", Amount: models.Pence(10000)},
+		},
+	}
+	_, doc := writeOFX(t, &OFXWriter{}, info)
+	if doc.Bank == nil || len(doc.Bank.Statements) != 1 {
+		t.Fatalf("expected one bank statement, got %+v", doc.Bank)
+	}
+	if bal := doc.Bank.Statements[0].Stmt.Ledger.BalAmt; bal != "1074.01" {
+		t.Errorf("LEDGERBAL: got %s, want 1074.01", bal)
+	}
+
+	var buf bytes.Buffer
+	if err := (&MT940Writer{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), ":62F:C240105GBP1074,01\n") {
+		t.Errorf("expected :62F: of 1074,01 in:\n%s", buf.String())
+	}
+}
+
+func TestOFXWriter_UnresolvedDate(t *testing.T) {
+	info := &models.StatementInfo{
+		Transactions: []models.Transaction{
+			{Date: "4 Dec", Description: "Stripe", Type: "DEBIT", Amount: models.Pence(100)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&OFXWriter{}).Write(&buf, info); err == nil {
+		t.Error("expected error for a transaction without a resolved date")
+	}
+}
+
+func TestLookupFormat(t *testing.T) {
+	for _, name := range []string{"", "csv", "OFX", "qfx", "camt053", "MT940"} {
+		if _, err := LookupFormat(name); err != nil {
+			t.Errorf("LookupFormat(%q): %v", name, err)
+		}
+	}
+	if f, _ := LookupFormat(""); f.Name != "csv" {
+		t.Errorf("default format: got %q, want csv", f.Name)
+	}
+	if _, err := LookupFormat("pdf"); err == nil {
+		t.Error("expected error for an unknown format")
+	}
+}
@@ -0,0 +1,294 @@
+package writer
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page geometry, in points.
+const (
+	pdfPageWidth  = 595.28
+	pdfPageHeight = 841.89
+	pdfMargin     = 40.0
+	pdfBodyWidth  = pdfPageWidth - 2*pdfMargin
+)
+
+// pdfWidths are the Helvetica and Helvetica-Bold glyph widths (per 1000
+// points of font size) for ASCII 32 to 126, from the standard AFM files.
+// Other characters are measured as 556, the width of a digit.
+var pdfWidths = [2][95]int{
+	{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// pdfWinAnsi maps the non-Latin-1 characters statements use to their
+// WinAnsiEncoding codes.
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfDoc builds a PDF of text and ruled tables in the two standard
+// Helvetica fonts, which every PDF reader has, so nothing is embedded.
+// Positions are measured from the top left of the page, in points.
+type pdfDoc struct {
+	title string
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64 // top of the next line
+}
+
+func newPDFDoc(title string) *pdfDoc {
+	d := &pdfDoc{title: title}
+	d.newPage()
+	return d
+}
+
+func (d *pdfDoc) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pdfMargin
+}
+
+// need starts a new page unless h points are left above the footer.
+func (d *pdfDoc) need(h float64) bool {
+	if d.y+h > pdfPageHeight-pdfMargin {
+		d.newPage()
+		return true
+	}
+	return false
+}
+
+// text draws s with its baseline size points below the cursor, starting
+// at x.
+func (d *pdfDoc) text(x, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, pdfPageHeight-d.y-size, pdfString(s))
+}
+
+// textRight draws s ending at x.
+func (d *pdfDoc) textRight(x, size float64, bold bool, s string) {
+	d.text(x-pdfTextWidth(s, size, bold), size, bold, s)
+}
+
+// fill shades a box h points high across the body, from the cursor.
+func (d *pdfDoc) fill(h, gray float64) {
+	fmt.Fprintf(d.page, "%.2f g %.2f %.2f %.2f %.2f re f 0 g\n", gray, pdfMargin, pdfPageHeight-d.y-h, pdfBodyWidth, h)
+}
+
+// rule draws a line across the body at the cursor.
+func (d *pdfDoc) rule() {
+	y := pdfPageHeight - d.y
+	fmt.Fprintf(d.page, "0.75 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+}
+
+// WriteTo writes the document, numbering the pages in their footers.
+func (d *pdfDoc) WriteTo(out io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1-5 are fixed; each page is then a page and a content stream
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (bank-statement-converter) >>", pdfString(d.title)))
+
+	for i, page := range d.pages {
+		d.page, d.y = page, pdfPageHeight-pdfMargin+12
+		d.textRight(pdfPageWidth-pdfMargin, 7, false, fmt.Sprintf("Page %d of %d", i+1, len(d.pages)))
+		d.text(pdfMargin, 7, false, d.title)
+
+		var content bytes.Buffer
+		zw := zlib.NewWriter(&content)
+		zw.Write(page.Bytes())
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.WriteTo(out)
+}
+
+// pdfString encodes s in WinAnsiEncoding as the body of a PDF string,
+// escaping delimiters; characters it lacks become '?'.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := pdfWinAnsi[r]
+		switch {
+		case ok:
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			c = byte(r)
+		case r >= 32 && r <= 126:
+			c = byte(r)
+		case r >= 0xA0 && r <= 0xFF:
+			c = byte(r)
+		default:
+			c = '?'
+		}
+		if c >= 0x80 {
+			fmt.Fprintf(&b, "\\%03o", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// pdfTextWidth measures s in points.
+func pdfTextWidth(s string, size float64, bold bool) float64 {
+	font := 0
+	if bold {
+		font = 1
+	}
+	total := 0
+	for _, r := range s {
+		if r >= 32 && r <= 126 {
+			total += pdfWidths[font][r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfFit shortens s with an ellipsis to fit width points.
+func pdfFit(s string, width, size float64, bold bool) string {
+	if pdfTextWidth(s, size, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdfTextWidth(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+// pdfWrap splits s into lines of at most width points, between words.
+func pdfWrap(s string, width, size float64, bold bool) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		next := strings.TrimSpace(line + " " + word)
+		if line != "" && pdfTextWidth(next, size, bold) > width {
+			lines = append(lines, line)
+			next = word
+		}
+		line = next
+	}
+	if line != "" {
+		lines = append(lines, pdfFit(line, width, size, bold))
+	}
+	return lines
+}
+
+// pdfColumn is a table column; amounts are right-aligned.
+type pdfColumn struct {
+	header string
+	width  float64
+	right  bool
+}
+
+// Table text size and row height.
+const (
+	pdfTableSize = 8.0
+	pdfRowHeight = 12.0
+)
+
+// table draws rows under a shaded header row, repeating the header on
+// each new page. Cells are cut short to fit their column.
+func (d *pdfDoc) table(cols []pdfColumn, rows [][]string) {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.header
+	}
+	d.need(2 * pdfRowHeight)
+	d.tableRow(cols, header, true)
+	for _, row := range rows {
+		if d.need(pdfRowHeight) {
+			d.tableRow(cols, header, true)
+		}
+		d.tableRow(cols, row, false)
+	}
+}
+
+func (d *pdfDoc) tableRow(cols []pdfColumn, cells []string, header bool) {
+	if header {
+		d.fill(pdfRowHeight, 0.92)
+	}
+	x := pdfMargin
+	for i, c := range cols {
+		const pad = 3
+		s := pdfFit(cells[i], c.width-2*pad, pdfTableSize, header)
+		d.y += 2
+		if c.right {
+			d.textRight(x+c.width-pad, pdfTableSize, header, s)
+		} else {
+			d.text(x+pad, pdfTableSize, header, s)
+		}
+		d.y -= 2
+		x += c.width
+	}
+	d.y += pdfRowHeight
+	if !header {
+		d.rule()
+	}
+}
+
+// heading starts a report section.
+func (d *pdfDoc) heading(s string) {
+	d.need(4 * pdfRowHeight)
+	d.y += 10
+	d.text(pdfMargin, 11, true, s)
+	d.y += 16
+	d.rule()
+	d.y += 4
+}
+
+// paragraph writes wrapped body text.
+func (d *pdfDoc) paragraph(s string, bold bool) {
+	for _, line := range pdfWrap(s, pdfBodyWidth, 9, bold) {
+		d.need(pdfRowHeight)
+		d.text(pdfMargin, 9, bold, line)
+		d.y += pdfRowHeight
+	}
+}
@@ -0,0 +1,95 @@
+package writer
+
+import (
+	"fmt"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// CSVPreset is the column layout an accounting package imports. Presets
+// write no metadata rows and skip BALANCE rows, which are not transactions.
+type CSVPreset struct {
+	Name string
+	// Header is the column header row; nil for packages that import
+	// headerless files.
+	Header []string
+	// DateLayout is the date format the package expects. CSVWriter.DateFormat
+	// overrides it.
+	DateLayout string
+	// Row returns the columns for one transaction; date is already formatted.
+	Row func(txn models.Transaction, date string) []string
+}
+
+// csvPresets are the built-in accounting package layouts.
+var csvPresets = []*CSVPreset{
+	{
+		// Xero bank statement import: signed amounts, payee required
+		Name:       "xero",
+		Header:     []string{"Date", "Amount", "Payee", "Description", "Reference"},
+		DateLayout: "02/01/2006",
+		Row: func(txn models.Transaction, date string) []string {
+			return []string{date, txn.SignedAmount().String(), txn.Description, "", ""}
+		},
+	},
+	{
+		// QuickBooks Online four-column bank upload: unsigned amounts in
+		// separate Credit and Debit columns
+		Name:       "quickbooks",
+		Header:     []string{"Date", "Description", "Credit", "Debit"},
+		DateLayout: "02/01/2006",
+		Row: func(txn models.Transaction, date string) []string {
+			credit, debit := splitAmount(txn)
+			return []string{date, txn.Description, credit, debit}
+		},
+	},
+	{
+		// Sage 50 Accounts bank statement import
+		Name:       "sage50",
+		Header:     []string{"Date", "Reference", "Description", "Payments", "Receipts"},
+		DateLayout: "02/01/2006",
+		Row: func(txn models.Transaction, date string) []string {
+			receipt, payment := splitAmount(txn)
+			return []string{date, "", txn.Description, payment, receipt}
+		},
+	},
+	{
+		// FreeAgent bank statement upload: three columns, no header
+		Name:       "freeagent",
+		DateLayout: "02/01/2006",
+		Row: func(txn models.Transaction, date string) []string {
+			return []string{date, txn.SignedAmount().String(), txn.Description}
+		},
+	},
+}
+
+// writePreset writes the statement's transactions in the preset's layout.
+func (w *CSVWriter) writePreset(writer recordWriter, info *models.StatementInfo) error {
+	p := w.Preset
+	if p.Header != nil {
+		if err := writer.Write(p.Header); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+	}
+
+	layout := p.DateLayout
+	if w.DateFormat != "" {
+		layout = w.DateFormat
+	}
+	for _, txn := range info.Transactions {
+		if txn.Type == "BALANCE" {
+			continue
+		}
+		if err := writer.Write(p.Row(txn, formatDate(txn.Date, txn.ISODate, layout))); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+	return nil
+}
+
+// splitAmount returns the amount in the money-in or money-out column.
+func splitAmount(txn models.Transaction) (in, out string) {
+	if txn.Type == "DEBIT" {
+		return "", txn.Amount.String()
+	}
+	return txn.Amount.String(), ""
+}
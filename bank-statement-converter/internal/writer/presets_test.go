@@ -0,0 +1,119 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenStatement is the statement every golden file is written from.
+func goldenStatement() *models.StatementInfo {
+	return &models.StatementInfo{
+		Bank:            models.BankBarclays,
+		AccountHolder:   "ACME WIDGETS LTD",
+		AccountNumber:   "12345678",
+		SortCode:        "20-00-00",
+		StatementPeriod: "04/12/2025 to 05/01/2026",
+		OpeningBalance:  models.Pence(985668),
+		Transactions: []models.Transaction{
+			{Date: "4 Dec", ISODate: models.NewDate(2025, 12, 4), Description: "Start Balance", Type: "BALANCE", Balance: models.Pence(985668)},
+			{Date: "4 Dec", ISODate: models.NewDate(2025, 12, 4), Description: "Card Payment to Stripe, Ref 4021", Type: "DEBIT", Amount: models.Pence(40000), Balance: models.Pence(945668)},
+			{Date: "30 Dec", ISODate: models.NewDate(2025, 12, 30), Description: "Direct Debit to HMRC \"VAT\"", Type: "DEBIT", Amount: models.Pence(5880), Balance: models.Pence(939788)},
+			{Date: "2 Jan", ISODate: models.NewDate(2026, 1, 2), Description: "Bank Giro Credit Antalis", Type: "CREDIT", Amount: models.Pence(1050000), Balance: models.Pence(1989788)},
+		},
+	}
+}
+
+// checkGolden compares output with testdata/name, rewriting it with -update.
+func checkGolden(t *testing.T, name string, output []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, output, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(output, want) {
+		t.Errorf("output does not match %s:\ngot:\n%s\nwant:\n%s", path, output, want)
+	}
+}
+
+func TestCSVPresets_Golden(t *testing.T) {
+	for _, p := range csvPresets {
+		t.Run(p.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := &CSVWriter{IncludeHeader: true, Preset: p}
+			if err := w.Write(&buf, goldenStatement()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkGolden(t, p.Name+".csv", buf.Bytes())
+		})
+	}
+}
+
+func TestCSVPresets_DateFormat(t *testing.T) {
+	f, err := LookupFormat("xero")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.New(Options{DateFormat: "2006-01-02"}).Write(&buf, goldenStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("\n2025-12-04,-400.00,")) {
+		t.Errorf("--date-format should override the preset's dates:\n%s", buf.String())
+	}
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestCSVPresets_WriteError(t *testing.T) {
+	for _, p := range csvPresets {
+		t.Run(p.Name, func(t *testing.T) {
+			w := &CSVWriter{Preset: p}
+			if err := w.Write(failingWriter{}, goldenStatement()); err == nil {
+				t.Error("expected the write error to be returned")
+			}
+		})
+	}
+}
+
+func TestQIFWriter_Golden(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&QIFWriter{}).Write(&buf, goldenStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkGolden(t, "statement.qif", buf.Bytes())
+}
+
+func TestQIFWriter_CreditCard(t *testing.T) {
+	info := &models.StatementInfo{
+		CreditCard: true,
+		Transactions: []models.Transaction{
+			{Date: "Jan 02", ISODate: models.NewDate(2024, 1, 2), Description: "TESCO STORES", Type: "DEBIT", Amount: models.Pence(2599)},
+			{Date: "Jan 15", ISODate: models.NewDate(2024, 1, 15), Description: "PAYMENT RECEIVED", Type: "CREDIT", Amount: models.Pence(50000)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&QIFWriter{DateFormat: "01/02/2006"}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "!Type:CCard\nD01/02/2024\nT-25.99\nPTESCO STORES\n^\nD01/15/2024\nT500.00\nPPAYMENT RECEIVED\n^\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,55 @@
+package writer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// qifDateLayout is the default QIF date format (UK day first).
+const qifDateLayout = "02/01/2006"
+
+// QIFWriter writes transactions as a Quicken Interchange Format file: one
+// !Type:Bank (or !Type:CCard) section with a D/T/P record per transaction.
+// BALANCE rows are skipped.
+type QIFWriter struct {
+	// DateFormat is the Go layout for dates (see DateLayout); empty uses
+	// DD/MM/YYYY.
+	DateFormat string
+}
+
+// Write writes the statement as QIF to out.
+func (w *QIFWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	layout := w.DateFormat
+	if layout == "" {
+		layout = qifDateLayout
+	}
+
+	bw := bufio.NewWriter(out)
+	accountType := "Bank"
+	if info.CreditCard {
+		accountType = "CCard"
+	}
+	fmt.Fprintf(bw, "!Type:%s\n", accountType)
+	for _, txn := range info.Transactions {
+		if txn.Type == "BALANCE" {
+			continue
+		}
+		fmt.Fprintf(bw, "D%s\n", formatDate(txn.Date, txn.ISODate, layout))
+		fmt.Fprintf(bw, "T%s\n", txn.SignedAmount())
+		fmt.Fprintf(bw, "P%s\n", qifText(txn.Description))
+		fmt.Fprintln(bw, "^")
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write QIF: %w", err)
+	}
+	return nil
+}
+
+// qifText keeps a field on one line; QIF records are line-based.
+func qifText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
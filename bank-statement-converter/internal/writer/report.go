@@ -0,0 +1,364 @@
+package writer
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+	"github.com/insightdelivered/bank-statement-converter/internal/validator"
+)
+
+// reportDateLayout is the default date format in reports.
+const reportDateLayout = "02/01/2006"
+
+// ReportWriter writes a human-readable conversion report: the account
+// details, totals, a monthly money in and out summary, the reconciliation
+// results and the full transaction table. The report is a self-contained
+// HTML page, or with PDF a printable A4 document.
+type ReportWriter struct {
+	PDF bool
+	// DateFormat is the Go layout for dates (see DateLayout); empty uses
+	// DD/MM/YYYY. Dates that could not be resolved are shown as printed.
+	DateFormat string
+}
+
+// statementReport is what a report shows, for both HTML and PDF.
+type statementReport struct {
+	Title        string
+	Details      []reportField
+	Totals       []reportTotal
+	Months       []reportTotal
+	Validation   *models.ValidationReport
+	CreditCard   bool
+	ShowCurrency bool
+	Rows         []reportRow
+}
+
+type reportField struct {
+	Label, Value string
+}
+
+// reportTotal is money in and out for one currency, over the statement or
+// one month of it.
+type reportTotal struct {
+	Label    string
+	Currency string
+	Count    int
+	In, Out  models.Money
+}
+
+// Net is money in less money out.
+func (t reportTotal) Net() models.Money {
+	return t.In.Sub(t.Out)
+}
+
+type reportRow struct {
+	Date, Description, Type string
+	In, Out, Balance        string
+	Currency                string
+}
+
+// Write writes the statement's report to out.
+func (w *ReportWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	layout := w.DateFormat
+	if layout == "" {
+		layout = reportDateLayout
+	}
+	r := newStatementReport(info, layout)
+	if w.PDF {
+		return writeReportPDF(out, r)
+	}
+	var buf bytes.Buffer
+	if err := reportTemplate.Execute(&buf, r); err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+	if _, err := buf.WriteTo(out); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// newStatementReport gathers the report's contents from the statement.
+func newStatementReport(info *models.StatementInfo, layout string) *statementReport {
+	r := &statementReport{
+		Title:        "Statement report",
+		Validation:   validator.Validate(info),
+		CreditCard:   info.CreditCard,
+		ShowCurrency: hasMultipleCurrencies(info.Transactions),
+	}
+	if info.AccountNumber != "" {
+		r.Title += ": " + strings.TrimSpace(info.SortCode+" "+info.AccountNumber)
+	}
+
+	add := func(label, value string) {
+		if value != "" {
+			r.Details = append(r.Details, reportField{label, value})
+		}
+	}
+	add("Bank", string(info.Bank))
+	add("Account holder", info.AccountHolder)
+	if info.CreditCard {
+		add("Card number", info.AccountNumber)
+	} else {
+		add("Sort code", info.SortCode)
+		add("Account number", info.AccountNumber)
+	}
+	add("Statement period", info.StatementPeriod)
+	add("Opening balance", formatAmount(info.OpeningBalance))
+	if info.CreditCard {
+		add("Statement balance", formatAmount(info.StatementBalance))
+		add("Minimum payment", formatAmount(info.MinimumPayment))
+		add("Payment due", info.PaymentDueDate)
+	} else {
+		add("Closing balance", formatAmount(info.ClosingBalance))
+	}
+
+	var totals, months reportTotals
+	for _, txn := range info.Transactions {
+		row := reportRow{
+			Date:        formatDate(txn.Date, txn.ISODate, layout),
+			Description: txn.Description,
+			Type:        txn.Type,
+			Balance:     formatAmount(txn.Balance),
+			Currency:    txn.Currency,
+		}
+		if row.Currency == "" {
+			row.Currency = "GBP"
+		}
+		if txn.Type == "BALANCE" {
+			r.Rows = append(r.Rows, row)
+			continue
+		}
+		row.In, row.Out = splitAmount(txn)
+		r.Rows = append(r.Rows, row)
+
+		month := "Undated"
+		if !txn.ISODate.IsZero() {
+			month = txn.ISODate.Format("Jan 2006")
+		}
+		totals.add("Total", row.Currency, txn)
+		months.add(month, row.Currency, txn)
+	}
+	r.Totals, r.Months = totals.list, months.list
+	return r
+}
+
+// reportTotals accumulates totals by label and currency, in first-seen
+// order.
+type reportTotals struct {
+	list  []reportTotal
+	index map[string]int
+}
+
+func (ts *reportTotals) add(label, currency string, txn models.Transaction) {
+	if ts.index == nil {
+		ts.index = make(map[string]int)
+	}
+	key := label + " " + currency
+	i, ok := ts.index[key]
+	if !ok {
+		i = len(ts.list)
+		ts.index[key] = i
+		ts.list = append(ts.list, reportTotal{Label: label, Currency: currency})
+	}
+	t := &ts.list[i]
+	t.Count++
+	if txn.Type == "DEBIT" {
+		t.Out = t.Out.Add(txn.Amount)
+	} else {
+		t.In = t.In.Add(txn.Amount)
+	}
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; font-size: 13px; color: #1f2933; margin: 32px; }
+h1 { font-size: 22px; margin: 0 0 16px; }
+h2 { font-size: 16px; margin: 28px 0 8px; border-bottom: 1px solid #cbd2d9; padding-bottom: 4px; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f4f8; font-weight: 600; }
+tbody tr:nth-child(even) { background: #f9fafb; }
+td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; white-space: nowrap; }
+table.details { width: auto; }
+table.details th { background: none; padding-left: 0; }
+.ok { color: #1f7a3f; font-weight: 600; }
+.issues { color: #b42318; font-weight: 600; }
+tr.balance td { color: #616e7c; font-style: italic; }
+@media print { body { margin: 0; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+
+<h2>Account details</h2>
+<table class="details">
+{{- range .Details}}
+<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+
+<h2>Totals</h2>
+<table>
+<thead><tr>{{if .ShowCurrency}}<th>Currency</th>{{end}}<th class="num">Transactions</th><th class="num">Money in</th><th class="num">Money out</th><th class="num">Net</th></tr></thead>
+<tbody>
+{{- range .Totals}}
+<tr>{{if $.ShowCurrency}}<td>{{.Currency}}</td>{{end}}<td class="num">{{.Count}}</td><td class="num">{{.In}}</td><td class="num">{{.Out}}</td><td class="num">{{.Net}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Monthly summary</h2>
+<table>
+<thead><tr><th>Month</th>{{if .ShowCurrency}}<th>Currency</th>{{end}}<th class="num">Transactions</th><th class="num">Money in</th><th class="num">Money out</th><th class="num">Net</th></tr></thead>
+<tbody>
+{{- range .Months}}
+<tr><td>{{.Label}}</td>{{if $.ShowCurrency}}<td>{{.Currency}}</td>{{end}}<td class="num">{{.Count}}</td><td class="num">{{.In}}</td><td class="num">{{.Out}}</td><td class="num">{{.Net}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Reconciliation</h2>
+{{- with .Validation}}
+{{- if .Valid}}
+<p class="ok">Reconciled: {{.Checked}} balance(s) checked, no issues.</p>
+{{- else}}
+<p class="issues">{{len .Issues}} issue(s) found:</p>
+<ul>
+{{- range .Issues}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<table class="details">
+<tr><th>Opening balance</th><td class="num">{{.OpeningBalance}}</td></tr>
+<tr><th>Computed closing balance</th><td class="num">{{.ComputedClosingBalance}}</td></tr>
+{{- if not .ClosingBalance.IsZero}}
+<tr><th>Closing balance (printed)</th><td class="num">{{.ClosingBalance}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Transactions</h2>
+<table>
+<thead><tr><th>Date</th><th>Description</th><th>Type</th><th class="num">Money in</th><th class="num">Money out</th>{{if not .CreditCard}}<th class="num">Balance</th>{{end}}{{if .ShowCurrency}}<th>Currency</th>{{end}}</tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr{{if eq .Type "BALANCE"}} class="balance"{{end}}><td>{{.Date}}</td><td>{{.Description}}</td><td>{{.Type}}</td><td class="num">{{.In}}</td><td class="num">{{.Out}}</td>{{if not $.CreditCard}}<td class="num">{{.Balance}}</td>{{end}}{{if $.ShowCurrency}}<td>{{.Currency}}</td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`))
+
+// writeReportPDF lays the report out as an A4 PDF.
+func writeReportPDF(out io.Writer, r *statementReport) error {
+	d := newPDFDoc(r.Title)
+	d.text(pdfMargin, 16, true, r.Title)
+	d.y += 24
+
+	d.heading("Account details")
+	for _, f := range r.Details {
+		d.need(pdfRowHeight)
+		d.text(pdfMargin, 9, true, f.Label)
+		d.text(pdfMargin+120, 9, false, f.Value)
+		d.y += pdfRowHeight
+	}
+
+	d.heading("Totals")
+	cols := []pdfColumn{{"Transactions", 90, true}, {"Money in", 90, true}, {"Money out", 90, true}, {"Net", 90, true}}
+	if r.ShowCurrency {
+		cols = append([]pdfColumn{{"Currency", 60, false}}, cols...)
+	}
+	var rows [][]string
+	for _, t := range r.Totals {
+		rows = append(rows, reportTotalCells(t, "", r.ShowCurrency))
+	}
+	d.table(cols, rows)
+
+	d.heading("Monthly summary")
+	cols = append([]pdfColumn{{"Month", 80, false}}, cols...)
+	rows = nil
+	for _, t := range r.Months {
+		rows = append(rows, reportTotalCells(t, t.Label, r.ShowCurrency))
+	}
+	d.table(cols, rows)
+
+	d.heading("Reconciliation")
+	v := r.Validation
+	if v.Valid {
+		d.paragraph(fmt.Sprintf("Reconciled: %d balance(s) checked, no issues.", v.Checked), true)
+	} else {
+		d.paragraph(fmt.Sprintf("%d issue(s) found:", len(v.Issues)), true)
+		for _, issue := range v.Issues {
+			d.paragraph("- "+issue, false)
+		}
+	}
+	d.y += 4
+	balances := []reportField{
+		{"Opening balance", v.OpeningBalance.String()},
+		{"Computed closing balance", v.ComputedClosingBalance.String()},
+	}
+	if !v.ClosingBalance.IsZero() {
+		balances = append(balances, reportField{"Closing balance (printed)", v.ClosingBalance.String()})
+	}
+	for _, f := range balances {
+		d.need(pdfRowHeight)
+		d.text(pdfMargin, 9, true, f.Label)
+		d.textRight(pdfMargin+220, 9, false, f.Value)
+		d.y += pdfRowHeight
+	}
+
+	d.heading("Transactions")
+	cols = []pdfColumn{{"Date", 58, false}, {"Description", 0, false}, {"Type", 48, false}, {"Money in", 62, true}, {"Money out", 62, true}}
+	if !r.CreditCard {
+		cols = append(cols, pdfColumn{"Balance", 66, true})
+	}
+	if r.ShowCurrency {
+		cols = append(cols, pdfColumn{"Currency", 42, false})
+	}
+	// The description takes the remaining width
+	cols[1].width = pdfBodyWidth
+	for i, c := range cols {
+		if i != 1 {
+			cols[1].width -= c.width
+		}
+	}
+	rows = nil
+	for _, row := range r.Rows {
+		cells := []string{row.Date, row.Description, row.Type, row.In, row.Out}
+		if !r.CreditCard {
+			cells = append(cells, row.Balance)
+		}
+		if r.ShowCurrency {
+			cells = append(cells, row.Currency)
+		}
+		rows = append(rows, cells)
+	}
+	d.table(cols, rows)
+
+	if _, err := d.WriteTo(out); err != nil {
+		return fmt.Errorf("failed to write PDF report: %w", err)
+	}
+	return nil
+}
+
+// reportTotalCells is a totals table row, led by label when not empty.
+func reportTotalCells(t reportTotal, label string, currency bool) []string {
+	var cells []string
+	if label != "" {
+		cells = append(cells, label)
+	}
+	if currency {
+		cells = append(cells, t.Currency)
+	}
+	return append(cells, fmt.Sprint(t.Count), t.In.String(), t.Out.String(), t.Net().String())
+}
@@ -0,0 +1,104 @@
+package writer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/extractor"
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func TestReportWriter_HTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&ReportWriter{}).Write(&buf, goldenStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Statement report: 20-00-00 12345678</title>",
+		"<tr><th>Account holder</th><td>ACME WIDGETS LTD</td></tr>",
+		// Totals: count, in, out, net
+		`<td class="num">3</td><td class="num">10500.00</td><td class="num">458.80</td><td class="num">10041.20</td>`,
+		// Monthly summary
+		`<tr><td>Dec 2025</td><td class="num">2</td><td class="num">0.00</td><td class="num">458.80</td><td class="num">-458.80</td></tr>`,
+		`<tr><td>Jan 2026</td><td class="num">1</td><td class="num">10500.00</td>`,
+		"Reconciled: 4 balance(s) checked, no issues.",
+		// Descriptions are escaped
+		"<td>04/12/2025</td><td>Card Payment to Stripe, Ref 4021</td><td>DEBIT</td><td class=\"num\"></td><td class=\"num\">400.00</td><td class=\"num\">9456.68</td>",
+		"Direct Debit to HMRC &#34;VAT&#34;",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in report:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "<th>Currency</th>") {
+		t.Error("single-currency report should not have a currency column")
+	}
+}
+
+func TestReportWriter_HTMLIssues(t *testing.T) {
+	info := goldenStatement()
+	info.Transactions[1].Balance = models.Pence(945000)
+	var buf bytes.Buffer
+	if err := (&ReportWriter{DateFormat: "2006-01-02"}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `<p class="issues">`) || !strings.Contains(buf.String(), "<td>2025-12-04</td>") {
+		t.Errorf("expected reconciliation issues and ISO dates:\n%s", buf.String())
+	}
+}
+
+func TestReportWriter_PDF(t *testing.T) {
+	info := goldenStatement()
+	// Enough rows for a second page
+	for i := 0; i < 80; i++ {
+		info.Transactions = append(info.Transactions, models.Transaction{
+			Date: "3 Jan", ISODate: models.NewDate(2026, 1, 3), Description: "Faster Payment (ref £5) – Café", Type: "DEBIT", Amount: models.Pence(100),
+		})
+	}
+	var buf bytes.Buffer
+	if err := (&ReportWriter{PDF: true}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-1.4")) || !bytes.HasSuffix(buf.Bytes(), []byte("%%EOF\n")) {
+		t.Fatal("not a PDF file")
+	}
+
+	// Read it back with the converter's own extractor
+	path := filepath.Join(t.TempDir(), "report.pdf")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pages, err := extractor.ExtractText(path)
+	if err != nil {
+		t.Fatalf("extracting the report: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, got %d", len(pages))
+	}
+	if raw, err := extractor.ExtractTextRaw(path); err != nil || len(raw) != len(pages) {
+		t.Errorf("raw extractor: %d pages, err %v; want %d", len(raw), err, len(pages))
+	}
+	text := strings.Join(pages, "\n")
+	for _, want := range []string{"Account details", "ACME WIDGETS LTD", "Monthly summary", "Reconciliation", "Card Payment to Stripe", "Page 2 of 2"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in the PDF text:\n%s", want, text)
+		}
+	}
+}
+
+func TestPDFString(t *testing.T) {
+	if got := pdfString(`a (b) \ £5 – €1 ✓`); got != `a \(b\) \\ \2435 \226 \2001 ?` {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPDFFit(t *testing.T) {
+	s := pdfFit(strings.Repeat("W", 100), 100, 8, false)
+	if !strings.HasSuffix(s, "...") || pdfTextWidth(s, 8, false) > 100 {
+		t.Errorf("got %q (%.1fpt)", s, pdfTextWidth(s, 8, false))
+	}
+}
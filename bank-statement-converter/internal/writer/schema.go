@@ -0,0 +1,315 @@
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// CSVSchema customises the CSV output: which columns, in what order and
+// under what headers, and how values are written. The zero CSVSchema is the
+// default layout. Schemas are JSON, loaded with LoadCSVSchema (--csv-schema)
+// or ParseCSVSchema (the API's csvSchema field):
+//
+//	{
+//	  "columns": ["date", {"field": "description", "header": "Details"}, "debit", "credit", "balance"],
+//	  "dateFormat": "uk",
+//	  "decimalSeparator": ",",
+//	  "delimiter": ";",
+//	  "quoteAll": true,
+//	  "zeroAmounts": true,
+//	  "bom": true
+//	}
+type CSVSchema struct {
+	// Columns are the output columns in order; see CSVFieldNames. Empty
+	// uses the default columns for the statement.
+	Columns []CSVColumn `json:"columns,omitempty"`
+	// Amounts picks the default columns' amount layout: "" (unsigned
+	// Amount with a Type column), "signed" (negative for money out) or
+	// "split" (Debit and Credit columns). Ignored when Columns is set.
+	Amounts string `json:"amounts,omitempty"`
+	// DateFormat is a date format preset or Go layout (see DateLayout);
+	// --date-format and the API's dateFormat take precedence.
+	DateFormat string `json:"dateFormat,omitempty"`
+	// DecimalSeparator is "." (default) or ",".
+	DecimalSeparator string `json:"decimalSeparator,omitempty"`
+	// Delimiter is the field separator: one character, or "tab".
+	// Defaults to ",".
+	Delimiter string `json:"delimiter,omitempty"`
+	// QuoteAll quotes every field, not only those that need it.
+	QuoteAll bool `json:"quoteAll,omitempty"`
+	// ZeroAmounts writes zero amounts and balances as 0.00 rather than
+	// leaving them blank.
+	ZeroAmounts bool `json:"zeroAmounts,omitempty"`
+	// BOM starts the file with a UTF-8 byte order mark, so Excel reads
+	// it as UTF-8.
+	BOM bool `json:"bom,omitempty"`
+}
+
+// CSVColumn is one output column: a field and, optionally, the header to
+// write for it. In JSON a column is either the field name or an object.
+type CSVColumn struct {
+	Field  string `json:"field"`
+	Header string `json:"header,omitempty"` // defaults to the field's header
+}
+
+// csvField is a value the CSV output can carry.
+type csvField struct {
+	header string
+	value  func(r *csvRow) string
+}
+
+// csvRow is the context a field's value is taken from.
+type csvRow struct {
+	txn    models.Transaction
+	info   *models.StatementInfo
+	schema *CSVSchema
+	layout string
+}
+
+// csvFields are the column fields, by lower-case name.
+var csvFields = map[string]csvField{
+	"date":         {"Date", func(r *csvRow) string { return formatDate(r.txn.Date, r.txn.ISODate, r.layout) }},
+	"postingdate":  {"Posting Date", func(r *csvRow) string { return formatDate(r.txn.PostingDate, r.txn.ISOPostingDate, r.layout) }},
+	"description":  {"Description", func(r *csvRow) string { return r.txn.Description }},
+	"type":         {"Type", func(r *csvRow) string { return r.txn.Type }},
+	"amount":       {"Amount", func(r *csvRow) string { return r.schema.formatMoney(r.txn.Amount) }},
+	"signedamount": {"Amount", func(r *csvRow) string { return r.schema.formatMoney(r.txn.SignedAmount()) }},
+	"debit": {"Debit", func(r *csvRow) string {
+		if r.txn.Type == "DEBIT" {
+			return r.schema.formatMoney(r.txn.Amount)
+		}
+		return r.schema.formatMoney(models.Money{})
+	}},
+	"credit": {"Credit", func(r *csvRow) string {
+		if r.txn.Type != "DEBIT" {
+			return r.schema.formatMoney(r.txn.Amount)
+		}
+		return r.schema.formatMoney(models.Money{})
+	}},
+	"balance":       {"Balance", func(r *csvRow) string { return r.schema.formatMoney(r.txn.Balance) }},
+	"currency":      {"Currency", func(r *csvRow) string { return r.txn.Currency }},
+	"bank":          {"Bank", func(r *csvRow) string { return string(r.info.Bank) }},
+	"accountholder": {"Account Holder", func(r *csvRow) string { return r.info.AccountHolder }},
+	"accountnumber": {"Account Number", func(r *csvRow) string { return r.info.AccountNumber }},
+	"sortcode":      {"Sort Code", func(r *csvRow) string { return r.info.SortCode }},
+	"period":        {"Statement Period", func(r *csvRow) string { return r.info.StatementPeriod }},
+}
+
+// CSVFieldNames returns the column field names a schema can use.
+func CSVFieldNames() []string {
+	return []string{
+		"date", "postingDate", "description", "type", "amount", "signedAmount", "debit", "credit",
+		"balance", "currency", "bank", "accountHolder", "accountNumber", "sortCode", "period",
+	}
+}
+
+// UnmarshalJSON accepts a bare field name or {"field": ..., "header": ...}.
+func (c *CSVColumn) UnmarshalJSON(data []byte) error {
+	var field string
+	if err := json.Unmarshal(data, &field); err == nil {
+		*c = CSVColumn{Field: field}
+		return nil
+	}
+	type column CSVColumn
+	var col column
+	if err := json.Unmarshal(data, &col); err != nil {
+		return fmt.Errorf("column must be a field name or {\"field\": ..., \"header\": ...}")
+	}
+	*c = CSVColumn(col)
+	return nil
+}
+
+// LoadCSVSchema reads a JSON CSV schema from path.
+func LoadCSVSchema(path string) (*CSVSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV schema %q: %w", path, err)
+	}
+	s, err := ParseCSVSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV schema %q: %w", path, err)
+	}
+	return s, nil
+}
+
+// ParseCSVSchema parses and validates a JSON CSV schema.
+func ParseCSVSchema(data []byte) (*CSVSchema, error) {
+	var s CSVSchema
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ParseCSVColumns parses a --csv-columns list: comma-separated fields,
+// each optionally renamed with a colon ("date,description:Details,amount").
+func ParseCSVColumns(spec string) ([]CSVColumn, error) {
+	var cols []CSVColumn
+	for _, part := range strings.Split(spec, ",") {
+		field, header, _ := strings.Cut(strings.TrimSpace(part), ":")
+		cols = append(cols, CSVColumn{Field: strings.TrimSpace(field), Header: strings.TrimSpace(header)})
+	}
+	s := CSVSchema{Columns: cols}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// Validate checks the schema's settings and resolves DateFormat to a Go
+// layout.
+func (s *CSVSchema) Validate() error {
+	for _, c := range s.Columns {
+		if _, ok := csvFields[strings.ToLower(c.Field)]; !ok {
+			return fmt.Errorf("unknown CSV column %q: use %s", c.Field, strings.Join(CSVFieldNames(), ", "))
+		}
+	}
+	switch s.Amounts {
+	case "", "signed", "split":
+	default:
+		return fmt.Errorf("invalid amounts %q: use signed or split", s.Amounts)
+	}
+	switch s.DecimalSeparator {
+	case "", ".", ",":
+	default:
+		return fmt.Errorf("invalid decimal separator %q: use . or ,", s.DecimalSeparator)
+	}
+	if _, err := s.delimiter(); err != nil {
+		return err
+	}
+	layout, err := DateLayout(s.DateFormat)
+	if err != nil {
+		return err
+	}
+	s.DateFormat = layout
+	return nil
+}
+
+// delimiter returns the field separator rune.
+func (s *CSVSchema) delimiter() (rune, error) {
+	switch strings.ToLower(s.Delimiter) {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s.Delimiter)
+	if size != len(s.Delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("invalid delimiter %q: use a single character other than a quote or newline", s.Delimiter)
+	}
+	return r, nil
+}
+
+// columns returns the columns to write for the statement.
+func (s *CSVSchema) columns(info *models.StatementInfo) []CSVColumn {
+	if len(s.Columns) > 0 {
+		return s.Columns
+	}
+	var fields []string
+	if info.CreditCard {
+		fields = []string{"date", "postingDate", "description"}
+	} else {
+		fields = []string{"date", "description"}
+	}
+	switch s.Amounts {
+	case "signed":
+		fields = append(fields, "signedAmount")
+	case "split":
+		fields = append(fields, "debit", "credit")
+	default:
+		fields = append(fields, "type", "amount")
+	}
+	// Credit card statements have no running balance
+	if !info.CreditCard {
+		fields = append(fields, "balance")
+	}
+	// Multi-currency statements (e.g. Revolut pockets) get a Currency column
+	// so amounts from different pockets are not mistaken for one another.
+	if hasMultipleCurrencies(info.Transactions) {
+		fields = append(fields, "currency")
+	}
+	cols := make([]CSVColumn, len(fields))
+	for i, f := range fields {
+		cols[i] = CSVColumn{Field: f}
+	}
+	return cols
+}
+
+// formatMoney writes an amount with the schema's decimal separator; zero
+// is blank unless ZeroAmounts is set.
+func (s *CSVSchema) formatMoney(m models.Money) string {
+	v := formatAmount(m)
+	if m.IsZero() && s.ZeroAmounts {
+		v = m.String()
+	}
+	if s.DecimalSeparator == "," {
+		return strings.Replace(v, ".", ",", 1)
+	}
+	return v
+}
+
+// header returns the column's header.
+func (c CSVColumn) header() string {
+	if c.Header != "" {
+		return c.Header
+	}
+	return csvFields[strings.ToLower(c.Field)].header
+}
+
+// recordWriter writes CSV records; *csv.Writer is one.
+type recordWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
+}
+
+// quoteAllWriter is a recordWriter that quotes every field, which
+// encoding/csv cannot do.
+type quoteAllWriter struct {
+	w     *bufio.Writer
+	comma rune
+	err   error
+}
+
+func newQuoteAllWriter(out io.Writer, comma rune) *quoteAllWriter {
+	return &quoteAllWriter{w: bufio.NewWriter(out), comma: comma}
+}
+
+func (q *quoteAllWriter) Write(record []string) error {
+	if q.err != nil {
+		return q.err
+	}
+	for i, field := range record {
+		if i > 0 {
+			q.w.WriteRune(q.comma)
+		}
+		q.w.WriteByte('"')
+		q.w.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		q.w.WriteByte('"')
+	}
+	_, q.err = q.w.WriteString("\n")
+	return q.err
+}
+
+func (q *quoteAllWriter) Flush() {
+	if q.err == nil {
+		q.err = q.w.Flush()
+	}
+}
+
+func (q *quoteAllWriter) Error() error {
+	return q.err
+}
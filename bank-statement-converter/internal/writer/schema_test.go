@@ -0,0 +1,176 @@
+package writer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+func schemaStatement() *models.StatementInfo {
+	return &models.StatementInfo{
+		Bank:          models.BankHSBC,
+		AccountNumber: "01234567",
+		SortCode:      "40-12-34",
+		Transactions: []models.Transaction{
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "BALANCE BROUGHT FORWARD", Type: "BALANCE", Balance: models.Pence(102500)},
+			{Date: "02 Jan 24", ISODate: models.NewDate(2024, 1, 2), Description: "COSTA, LONDON", Type: "DEBIT", Amount: models.Pence(450), Balance: models.Pence(102050)},
+			{Date: "31 Jan 24", ISODate: models.NewDate(2024, 1, 31), Description: "SALARY", Type: "CREDIT", Amount: models.Pence(245900), Balance: models.Pence(347950)},
+		},
+	}
+}
+
+func writeSchemaCSV(t *testing.T, schema *CSVSchema) string {
+	t.Helper()
+	if err := schema.Validate(); err != nil {
+		t.Fatalf("invalid schema: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := (&CSVWriter{Schema: schema}).Write(&buf, schemaStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestCSVSchema(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema *CSVSchema
+		want   string
+	}{
+		{
+			name:   "default",
+			schema: &CSVSchema{},
+			want: "Date,Description,Type,Amount,Balance\n" +
+				"02 Jan 24,BALANCE BROUGHT FORWARD,BALANCE,,1025.00\n" +
+				"02 Jan 24,\"COSTA, LONDON\",DEBIT,4.50,1020.50\n" +
+				"31 Jan 24,SALARY,CREDIT,2459.00,3479.50\n",
+		},
+		{
+			name: "columns and renames",
+			schema: &CSVSchema{
+				Columns:    []CSVColumn{{Field: "date", Header: "Txn Date"}, {Field: "sortCode"}, {Field: "accountNumber"}, {Field: "signedAmount"}},
+				DateFormat: "iso",
+			},
+			want: "Txn Date,Sort Code,Account Number,Amount\n" +
+				"2024-01-02,40-12-34,01234567,\n" +
+				"2024-01-02,40-12-34,01234567,-4.50\n" +
+				"2024-01-31,40-12-34,01234567,2459.00\n",
+		},
+		{
+			name:   "split amounts with zeros",
+			schema: &CSVSchema{Amounts: "split", ZeroAmounts: true},
+			want: "Date,Description,Debit,Credit,Balance\n" +
+				"02 Jan 24,BALANCE BROUGHT FORWARD,0.00,0.00,1025.00\n" +
+				"02 Jan 24,\"COSTA, LONDON\",4.50,0.00,1020.50\n" +
+				"31 Jan 24,SALARY,0.00,2459.00,3479.50\n",
+		},
+		{
+			name:   "signed amounts",
+			schema: &CSVSchema{Amounts: "signed"},
+			want: "Date,Description,Amount,Balance\n" +
+				"02 Jan 24,BALANCE BROUGHT FORWARD,,1025.00\n" +
+				"02 Jan 24,\"COSTA, LONDON\",-4.50,1020.50\n" +
+				"31 Jan 24,SALARY,2459.00,3479.50\n",
+		},
+		{
+			name:   "European",
+			schema: &CSVSchema{Amounts: "signed", Delimiter: ";", DecimalSeparator: ","},
+			want: "Date;Description;Amount;Balance\n" +
+				"02 Jan 24;BALANCE BROUGHT FORWARD;;1025,00\n" +
+				"02 Jan 24;COSTA, LONDON;-4,50;1020,50\n" +
+				"31 Jan 24;SALARY;2459,00;3479,50\n",
+		},
+		{
+			name:   "quote all, tab",
+			schema: &CSVSchema{Columns: []CSVColumn{{Field: "description"}, {Field: "amount"}}, Delimiter: "tab", QuoteAll: true},
+			want: "\"Description\"\t\"Amount\"\n" +
+				"\"BALANCE BROUGHT FORWARD\"\t\"\"\n" +
+				"\"COSTA, LONDON\"\t\"4.50\"\n" +
+				"\"SALARY\"\t\"2459.00\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := writeSchemaCSV(t, tt.schema); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVSchema_BOM(t *testing.T) {
+	got := writeSchemaCSV(t, &CSVSchema{BOM: true})
+	if !strings.HasPrefix(got, "\ufeffDate,") {
+		t.Errorf("expected a UTF-8 BOM, got %q", got[:10])
+	}
+}
+
+func TestCSVSchema_Format(t *testing.T) {
+	f, err := LookupFormat("csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := &CSVSchema{
+		Columns:   []CSVColumn{{Field: "description"}, {Field: "date"}},
+		Delimiter: ";",
+	}
+	var buf bytes.Buffer
+	if err := f.New(Options{CSVSchema: schema}).Write(&buf, schemaStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Description;Date\n" +
+		"BALANCE BROUGHT FORWARD;02 Jan 24\n" +
+		"COSTA, LONDON;02 Jan 24\n" +
+		"SALARY;31 Jan 24\n"
+	if got := buf.String(); got != want {
+		t.Errorf("the csv format should apply Options.CSVSchema:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseCSVSchema(t *testing.T) {
+	s, err := ParseCSVSchema([]byte(`{"columns": ["date", {"field": "description", "header": "Details"}], "dateFormat": "uk", "delimiter": ";"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Columns) != 2 || s.Columns[1] != (CSVColumn{Field: "description", Header: "Details"}) {
+		t.Errorf("columns: got %+v", s.Columns)
+	}
+	if s.DateFormat != "02/01/2006" {
+		t.Errorf("dateFormat should resolve to a layout, got %q", s.DateFormat)
+	}
+
+	for _, bad := range []string{
+		`{"columns": ["payee"]}`,
+		`{"amounts": "net"}`,
+		`{"decimalSeparator": "'"}`,
+		`{"delimiter": ";;"}`,
+		`{"delimiter": "\""}`,
+		`{"dateFormat": "yyyy"}`,
+		`{"colums": ["date"]}`,
+	} {
+		if _, err := ParseCSVSchema([]byte(bad)); err == nil {
+			t.Errorf("ParseCSVSchema(%s): expected error", bad)
+		}
+	}
+}
+
+func TestParseCSVColumns(t *testing.T) {
+	cols, err := ParseCSVColumns("date, description:Details ,debit,credit")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []CSVColumn{{Field: "date"}, {Field: "description", Header: "Details"}, {Field: "debit"}, {Field: "credit"}}
+	if len(cols) != len(want) {
+		t.Fatalf("got %+v, want %+v", cols, want)
+	}
+	for i := range want {
+		if cols[i] != want[i] {
+			t.Errorf("column %d: got %+v, want %+v", i, cols[i], want[i])
+		}
+	}
+	if _, err := ParseCSVColumns("date,,amount"); err == nil {
+		t.Error("expected error for an empty column")
+	}
+}
@@ -0,0 +1,105 @@
+package writer
+
+import (
+	"fmt"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// currencyStatement is the part of a statement in one currency, as the
+// balance-carrying formats (OFX, camt.053, MT940) write it.
+type currencyStatement struct {
+	currency   string
+	txns       []models.Transaction // BALANCE rows excluded
+	start, end models.Date
+	opening    models.Money
+	closing    models.Money
+}
+
+// splitByCurrency splits the statement's transactions by currency, in
+// first-seen order, with each part's date range and opening and closing
+// balances. Every transaction except BALANCE rows needs a resolved date;
+// format names the output in the error.
+//
+// The statement currency (the first) takes the printed opening and closing
+// balances. Other currencies, and statements without them, use the printed
+// running balances; with only a printed opening balance, the closing one is
+// worked forward from it. Card balances are amounts owed, so they are
+// negative.
+func splitByCurrency(info *models.StatementInfo, format string) ([]*currencyStatement, error) {
+	var parts []*currencyStatement
+	byCurrency := make(map[string]*currencyStatement)
+	// firstBalance records, per part, whether a running balance has been
+	// seen; until then the movement so far is tracked in opening.
+	firstBalance := make(map[*currencyStatement]bool)
+	for i, txn := range info.Transactions {
+		if txn.ISODate.IsZero() {
+			if txn.Type == "BALANCE" {
+				continue
+			}
+			return nil, fmt.Errorf("transaction %d (%s %s): date could not be resolved; %s needs a full date", i+1, txn.Date, txn.Description, format)
+		}
+		currency := txn.Currency
+		if currency == "" {
+			currency = "GBP"
+		}
+		p := byCurrency[currency]
+		if p == nil {
+			p = &currencyStatement{currency: currency, start: txn.ISODate, end: txn.ISODate}
+			byCurrency[currency] = p
+			parts = append(parts, p)
+		}
+		if txn.ISODate.Before(p.start.Time) {
+			p.start = txn.ISODate
+		}
+		if txn.ISODate.After(p.end.Time) {
+			p.end = txn.ISODate
+		}
+
+		if txn.Type != "BALANCE" {
+			p.txns = append(p.txns, txn)
+			p.closing = p.closing.Add(txn.SignedAmount())
+		}
+		if !txn.Balance.IsZero() || txn.Type == "BALANCE" {
+			if !firstBalance[p] {
+				// The balance before the first movement
+				p.opening = txn.Balance.Sub(p.closing)
+				firstBalance[p] = true
+			}
+			p.closing = txn.Balance
+		}
+	}
+	for _, p := range parts {
+		if !firstBalance[p] {
+			// No running balances: the movement is all we know
+			p.opening = models.Money{}
+		}
+	}
+
+	if len(parts) > 0 {
+		p := parts[0]
+		switch {
+		case info.CreditCard:
+			p.opening = info.OpeningBalance.Neg()
+			p.closing = p.opening
+			for _, txn := range p.txns {
+				p.closing = p.closing.Add(txn.SignedAmount())
+			}
+			if !info.StatementBalance.IsZero() {
+				p.closing = info.StatementBalance.Neg()
+			}
+		default:
+			if !info.OpeningBalance.IsZero() {
+				if !firstBalance[p] {
+					// Without running balances closing is only the movement
+					p.closing = info.OpeningBalance.Add(p.closing)
+				}
+				p.opening = info.OpeningBalance
+			}
+			if !info.ClosingBalance.IsZero() {
+				p.closing = info.ClosingBalance
+			}
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,393 @@
+package writer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// XLSXWriter writes statements as an Excel workbook: a typed transactions
+// sheet per statement (dates as Excel dates, amounts as numbers, account
+// numbers as text) and a Summary sheet with each statement's account
+// metadata. A single statement's sheet is named "Transactions".
+type XLSXWriter struct {
+	// DateFormat is the Go layout (see DateLayout) the date cells are
+	// displayed in; empty uses DD/MM/YYYY. The cells hold real dates
+	// either way. Dates that could not be resolved are written as text.
+	DateFormat string
+}
+
+// MultiStatementWriter is a StatementWriter that can also put several
+// statements in one output, as the CLI does when converting several PDFs
+// to a single --output file.
+type MultiStatementWriter interface {
+	StatementWriter
+	WriteAll(out io.Writer, infos []*models.StatementInfo) error
+}
+
+// Cell styles, the indexes of the cellXfs in xlsxStyles.
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleHeader
+	xlsxStyleDate
+	xlsxStyleAmount
+	xlsxStyleText
+)
+
+// xlsxMaxSheetName is Excel's limit on sheet name length.
+const xlsxMaxSheetName = 31
+
+// xlsxEpoch is day 0 of Excel's 1900 date system (as corrected for its
+// 1900 leap year bug).
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxPart is one file in the workbook's zip package.
+type xlsxPart struct {
+	name    string
+	content string
+}
+
+type xlsxSheet struct {
+	name   string
+	widths []float64
+	rows   [][]xlsxCell
+}
+
+// xlsxCell is one cell: a number (with a number style) or a string. The
+// zero xlsxCell is left empty.
+type xlsxCell struct {
+	value  string
+	number bool
+	style  int
+}
+
+// Write writes the statement as a workbook to out.
+func (w *XLSXWriter) Write(out io.Writer, info *models.StatementInfo) error {
+	return w.WriteAll(out, []*models.StatementInfo{info})
+}
+
+// WriteAll writes the statements to one workbook, one transactions sheet
+// each, followed by the Summary sheet.
+func (w *XLSXWriter) WriteAll(out io.Writer, infos []*models.StatementInfo) error {
+	names := xlsxSheetNames(infos)
+	var sheets []xlsxSheet
+	for i, info := range infos {
+		sheets = append(sheets, xlsxTransactions(names[i], info))
+	}
+	sheets = append(sheets, xlsxSummary(names, infos))
+
+	zw := zip.NewWriter(out)
+	parts := []xlsxPart{
+		{"[Content_Types].xml", xlsxContentTypes(len(sheets))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook(sheets)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels(len(sheets))},
+		{"xl/styles.xml", xlsxStyles(excelDateFormat(w.DateFormat))},
+	}
+	for i, s := range sheets {
+		parts = append(parts, xlsxPart{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), s.xml()})
+	}
+	for _, p := range parts {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: p.name, Method: zip.Deflate})
+		if err != nil {
+			return fmt.Errorf("failed to write XLSX: %w", err)
+		}
+		if _, err := io.WriteString(f, p.content); err != nil {
+			return fmt.Errorf("failed to write XLSX: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write XLSX: %w", err)
+	}
+	return nil
+}
+
+// xlsxTransactions builds a statement's transactions sheet, with the same
+// columns as the CSV output.
+func xlsxTransactions(name string, info *models.StatementInfo) xlsxSheet {
+	header := []string{"Date", "Description", "Type", "Amount", "Balance"}
+	widths := []float64{12, 48, 10, 14, 14}
+	if info.CreditCard {
+		header = []string{"Date", "Posting Date", "Description", "Type", "Amount"}
+		widths = []float64{12, 12, 48, 10, 14}
+	}
+	multiCurrency := hasMultipleCurrencies(info.Transactions)
+	if multiCurrency {
+		header = append(header, "Currency")
+		widths = append(widths, 10)
+	}
+
+	s := xlsxSheet{name: name, widths: widths, rows: [][]xlsxCell{headerCells(header)}}
+	for _, txn := range info.Transactions {
+		row := []xlsxCell{
+			dateCell(txn.Date, txn.ISODate),
+			textCell(txn.Description),
+			textCell(txn.Type),
+			moneyCell(txn.Amount),
+			moneyCell(txn.Balance),
+		}
+		if info.CreditCard {
+			row = []xlsxCell{
+				dateCell(txn.Date, txn.ISODate),
+				dateCell(txn.PostingDate, txn.ISOPostingDate),
+				textCell(txn.Description),
+				textCell(txn.Type),
+				moneyCell(txn.Amount),
+			}
+		}
+		if multiCurrency {
+			row = append(row, textCell(txn.Currency))
+		}
+		s.rows = append(s.rows, row)
+	}
+	return s
+}
+
+// xlsxSummary builds the Summary sheet: one row of account metadata per
+// statement, naming the statement's sheet. Card statements' opening and
+// closing balances are the previous and new statement balances.
+func xlsxSummary(names []string, infos []*models.StatementInfo) xlsxSheet {
+	s := xlsxSheet{
+		name:   "Summary",
+		widths: []float64{24, 12, 28, 20, 10, 26, 16, 16, 16, 16, 14},
+		rows: [][]xlsxCell{headerCells([]string{
+			"Sheet", "Bank", "Account Holder", "Account Number", "Sort Code", "Statement Period",
+			"Opening Balance", "Closing Balance", "Minimum Payment", "Payment Due Date", "Transactions",
+		})},
+	}
+	for i, info := range infos {
+		closing := info.ClosingBalance
+		if info.CreditCard {
+			closing = info.StatementBalance
+		}
+		s.rows = append(s.rows, []xlsxCell{
+			textCell(names[i]),
+			textCell(string(info.Bank)),
+			textCell(info.AccountHolder),
+			{value: info.AccountNumber, style: xlsxStyleText},
+			{value: info.SortCode, style: xlsxStyleText},
+			textCell(info.StatementPeriod),
+			moneyCell(info.OpeningBalance),
+			moneyCell(closing),
+			moneyCell(info.MinimumPayment),
+			textCell(info.PaymentDueDate),
+			{value: strconv.Itoa(len(info.Transactions)), number: true},
+		})
+	}
+	return s
+}
+
+// xlsxSheetNames names each statement's sheet: "Transactions" for a single
+// statement, otherwise the bank and the end of the account number, made
+// unique and valid as Excel sheet names.
+func xlsxSheetNames(infos []*models.StatementInfo) []string {
+	if len(infos) == 1 {
+		return []string{"Transactions"}
+	}
+	used := map[string]bool{"summary": true} // names are case-insensitive
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		base := string(info.Bank)
+		if base == "" {
+			base = "Statement"
+		}
+		if acct := models.DigitsOnly(info.AccountNumber); len(acct) >= 4 {
+			base += " " + acct[len(acct)-4:]
+		}
+		base = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(`[]:*?/\`, r) {
+				return ' '
+			}
+			return r
+		}, base)
+		base = truncateRunes(strings.Trim(base, "'"), xlsxMaxSheetName-4)
+
+		name := base
+		for n := 2; used[strings.ToLower(name)]; n++ {
+			name = fmt.Sprintf("%s (%d)", base, n)
+		}
+		used[strings.ToLower(name)] = true
+		names[i] = name
+	}
+	return names
+}
+
+func headerCells(names []string) []xlsxCell {
+	cells := make([]xlsxCell, len(names))
+	for i, n := range names {
+		cells[i] = xlsxCell{value: n, style: xlsxStyleHeader}
+	}
+	return cells
+}
+
+func textCell(s string) xlsxCell {
+	return xlsxCell{value: s, style: xlsxStyleDefault}
+}
+
+// moneyCell is a numeric amount; zero amounts are left empty, as in the
+// CSV output.
+func moneyCell(m models.Money) xlsxCell {
+	if m.IsZero() {
+		return xlsxCell{}
+	}
+	return xlsxCell{value: m.String(), number: true, style: xlsxStyleAmount}
+}
+
+// dateCell is an Excel date, or the date as printed when it could not be
+// resolved.
+func dateCell(raw string, d models.Date) xlsxCell {
+	if d.IsZero() {
+		return textCell(raw)
+	}
+	days := int(d.Sub(xlsxEpoch).Hours() / 24)
+	return xlsxCell{value: strconv.Itoa(days), number: true, style: xlsxStyleDate}
+}
+
+// xml renders the worksheet part, with the header row frozen.
+func (s xlsxSheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<cols>`)
+	for i, w := range s.widths {
+		fmt.Fprintf(&b, `<col min="%d" max="%d" width="%g" customWidth="1"/>`, i+1, i+1, w)
+	}
+	b.WriteString(`</cols><sheetData>`)
+	for r, row := range s.rows {
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for c, cell := range row {
+			if cell.value == "" {
+				continue
+			}
+			ref := xlsxColumn(c) + strconv.Itoa(r+1)
+			if cell.number {
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, cell.style, cell.value)
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, cell.style, xmlEscape(cell.value))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+// xlsxColumn returns the column letters for a zero-based index: A..Z, AA...
+func xlsxColumn(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// excelDateFormat converts a Go date layout to an Excel number format.
+// Characters that are not layout elements are written as literals.
+func excelDateFormat(layout string) string {
+	if layout == "" {
+		return "dd/mm/yyyy"
+	}
+	elements := []struct{ layout, excel string }{
+		{"January", "mmmm"}, {"Monday", "dddd"}, {"Jan", "mmm"}, {"Mon", "ddd"},
+		{"2006", "yyyy"}, {"06", "yy"}, {"01", "mm"}, {"02", "dd"}, {"_2", "d"},
+		{"1", "m"}, {"2", "d"},
+	}
+	var b strings.Builder
+next:
+	for layout != "" {
+		for _, e := range elements {
+			if strings.HasPrefix(layout, e.layout) {
+				b.WriteString(e.excel)
+				layout = layout[len(e.layout):]
+				continue next
+			}
+		}
+		r, size := utf8.DecodeRuneInString(layout)
+		if !strings.ContainsRune(" /-.,", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+		layout = layout[size:]
+	}
+	return b.String()
+}
+
+func xmlEscape(s string) string {
+	var b bytes.Buffer
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func xlsxContentTypes(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func xlsxWorkbook(sheets []xlsxSheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(s.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+// xlsxWorkbookRels relates the workbook to its sheets (rId1..n) and the
+// styles part (rId n+1).
+func xlsxWorkbookRels(sheets int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheets; i++ {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, sheets+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// xlsxStyles is the styles part. Its cellXfs are in the order of the
+// xlsxStyle constants: default, bold header, date, amount (#,##0.00) and
+// text (@, so edited sort codes keep their leading zeros).
+func xlsxStyles(dateFormat string) string {
+	return xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<numFmts count="1"><numFmt numFmtId="164" formatCode="` + xmlEscape(dateFormat) + `"/></numFmts>` +
+		`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="5">` +
+		`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+		`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+		`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`<xf numFmtId="49" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+		`</cellXfs>` +
+		`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+		`</styleSheet>`
+}
@@ -0,0 +1,128 @@
+package writer
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/insightdelivered/bank-statement-converter/internal/models"
+)
+
+// readXLSX returns the parts of a workbook by name.
+func readXLSX(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("output is not a zip package: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+	return parts
+}
+
+func TestXLSXWriter_Write(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&XLSXWriter{}).Write(&buf, goldenStatement()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parts := readXLSX(t, buf.Bytes())
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	if wb := parts["xl/workbook.xml"]; !strings.Contains(wb, `<sheet name="Transactions" sheetId="1"`) || !strings.Contains(wb, `<sheet name="Summary" sheetId="2"`) {
+		t.Errorf("sheets: got %s", wb)
+	}
+
+	txns := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A3" s="2"><v>45995</v></c>`,   // 4 Dec 2025 as an Excel date
+		`<c r="D3" s="3"><v>400.00</v></c>`,  // amount as a number
+		`<c r="E3" s="3"><v>9456.68</v></c>`, // balance
+		`<t xml:space="preserve">Direct Debit to HMRC &#34;VAT&#34;</t>`,
+	} {
+		if !strings.Contains(txns, want) {
+			t.Errorf("Transactions sheet: expected %s", want)
+		}
+	}
+
+	// Sort codes and account numbers stay text, leading zeros and all
+	summary := parts["xl/worksheets/sheet2.xml"]
+	for _, want := range []string{
+		`<c r="D2" s="4" t="inlineStr"><is><t xml:space="preserve">12345678</t></is></c>`,
+		`<c r="E2" s="4" t="inlineStr"><is><t xml:space="preserve">20-00-00</t></is></c>`,
+		`<c r="G2" s="3"><v>9856.68</v></c>`,
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary sheet: expected %s", want)
+		}
+	}
+	if !strings.Contains(parts["xl/styles.xml"], `formatCode="dd/mm/yyyy"`) {
+		t.Error("expected DD/MM/YYYY date cells by default")
+	}
+}
+
+func TestXLSXWriter_UnresolvedDate(t *testing.T) {
+	info := &models.StatementInfo{
+		Transactions: []models.Transaction{
+			{Date: "4 Dec", Description: "Stripe", Type: "DEBIT", Amount: models.Pence(100)},
+		},
+	}
+	var buf bytes.Buffer
+	if err := (&XLSXWriter{}).Write(&buf, info); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sheet := readXLSX(t, buf.Bytes())["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet, `<c r="A2" s="0" t="inlineStr"><is><t xml:space="preserve">4 Dec</t></is></c>`) {
+		t.Errorf("unresolved date should be written as printed:\n%s", sheet)
+	}
+}
+
+func TestXLSXWriter_WriteAll(t *testing.T) {
+	second := goldenStatement()
+	second.AccountNumber = "87654321"
+	infos := []*models.StatementInfo{goldenStatement(), second, goldenStatement()}
+
+	var buf bytes.Buffer
+	if err := (&XLSXWriter{}).WriteAll(&buf, infos); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parts := readXLSX(t, buf.Bytes())
+	wb := parts["xl/workbook.xml"]
+	for _, want := range []string{`name="barclays 5678"`, `name="barclays 4321"`, `name="barclays 5678 (2)"`, `name="Summary" sheetId="4"`} {
+		if !strings.Contains(wb, want) {
+			t.Errorf("workbook: expected %s in %s", want, wb)
+		}
+	}
+	if summary := parts["xl/worksheets/sheet4.xml"]; !strings.Contains(summary, `<row r="4">`) {
+		t.Error("Summary should have a row per statement")
+	}
+}
+
+func TestExcelDateFormat(t *testing.T) {
+	tests := map[string]string{
+		"":             "dd/mm/yyyy",
+		"2006-01-02":   "yyyy-mm-dd",
+		"01/02/2006":   "mm/dd/yyyy",
+		"02 Jan 2006":  "dd mmm yyyy",
+		"Monday 2 Jan": "dddd d mmm",
+		"2006年01月02日":  `yyyy\年mm\月dd\日`,
+	}
+	for layout, want := range tests {
+		if got := excelDateFormat(layout); got != want {
+			t.Errorf("excelDateFormat(%q) = %q, want %q", layout, got, want)
+		}
+	}
+}
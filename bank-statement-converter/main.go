@@ -1,8 +1,10 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -15,30 +17,57 @@ import (
 
 	"github.com/insightdelivered/bank-statement-converter/internal/api"
 	"github.com/insightdelivered/bank-statement-converter/internal/extractor"
+	"github.com/insightdelivered/bank-statement-converter/internal/merge"
 	"github.com/insightdelivered/bank-statement-converter/internal/models"
 	"github.com/insightdelivered/bank-statement-converter/internal/parser"
+	"github.com/insightdelivered/bank-statement-converter/internal/validator"
 	"github.com/insightdelivered/bank-statement-converter/internal/writer"
 )
 
 const version = "2.0.0"
 
 func main() {
+	// The json and ndjson formats write the API's response document
+	for _, f := range api.JSONFormats() {
+		writer.RegisterFormat(f)
+	}
+
 	// CLI flags
-	bankFlag := flag.String("bank", "", "Bank type: metro, hsbc, barclays (auto-detected if omitted)")
-	outputFlag := flag.String("output", "", "Output CSV file path (defaults to input filename with .csv extension)")
+	bankFlag := flag.String("bank", "", "Bank type: metro, hsbc, barclays, lloyds, natwest, rbs, santander, nationwide, monzo, starling, revolut, amex, barclaycard (auto-detected if omitted)")
+	outputFlag := flag.String("output", "", "Output file path, or - for stdout (defaults to input filename with the format's extension)")
+	formatFlag := flag.String("format", "csv", "Output format: "+strings.Join(writer.FormatNames(), ", "))
 	headerFlag := flag.Bool("header", true, "Include account metadata header rows in CSV")
 	versionFlag := flag.Bool("version", false, "Print version and exit")
 	helpFlag := flag.Bool("help", false, "Show usage help")
 	serveFlag := flag.Bool("serve", false, "Start web UI server instead of CLI mode")
 	portFlag := flag.String("port", "8080", "Port for web UI server (used with --serve)")
 	staticFlag := flag.String("static", "", "Path to React build directory (used with --serve)")
+	detectOnlyFlag := flag.Bool("detect-only", false, "Print the ranked bank detection for each file and exit without converting")
+	dryRunDetectFlag := flag.Bool("dry-run-detect", false, "During auto-detection, dry-run the leading candidate parsers and prefer the one whose balances reconcile")
+	templatesFlag := flag.String("templates", "", "Directory of JSON bank templates to load alongside the built-in parsers")
+	dateFormatFlag := flag.String("date-format", "", "Output date format: iso, uk, us or a Go layout such as \"02 Jan 2006\" (dates as printed if omitted)")
+	debugLinesFlag := flag.Bool("debug-lines", false, "Include the parser's debug lines in json and ndjson output")
+	sanitizeFlag := flag.Bool("sanitize", false, "Neutralise CSV cells starting with =, +, -, @, tab or CR so spreadsheets do not run them as formulas")
+	csvSchemaFlag := flag.String("csv-schema", "", "JSON file describing the CSV columns and formatting (the --csv-* flags override it)")
+	flag.String("csv-columns", "", "CSV columns in order, optionally renamed: date,description:Details,debit,credit,balance")
+	flag.String("csv-amounts", "", "Default CSV amount columns: signed (one negative-for-out column) or split (Debit and Credit)")
+	flag.String("csv-delimiter", "", "CSV field delimiter: one character, or tab (default ,)")
+	flag.String("csv-decimal", "", "CSV decimal separator: . or , (default .)")
+	flag.Bool("csv-quote-all", false, "Quote every CSV field")
+	flag.Bool("csv-zero-amounts", false, "Write zero CSV amounts as 0.00 instead of leaving them blank")
+	flag.Bool("csv-bom", false, "Start the CSV with a UTF-8 byte order mark for Excel")
+	mergeFlag := flag.Bool("merge", false, "Merge statements for one account into a single de-duplicated output, checking balances and gaps between them")
+	passwordFlag := flag.String("password", "", "Password for encrypted statement PDFs (the user or owner password); unencrypted files ignore it")
+	ledgerAccountsFlag := flag.String("ledger-accounts", "", "JSON file mapping sort codes and account numbers to beancount/ledger account names")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, `Bank Statement PDF to CSV Converter (Fiber v2)
 by Insight Delivered (QEA AutoLens)
 
-Converts bank statement PDFs from Metro Bank, HSBC, and Barclays
-into structured CSV files for analysis.
+Converts bank statement PDFs from Metro Bank, HSBC, Barclays,
+Lloyds Bank, NatWest, RBS, Santander, Nationwide, Monzo, Starling
+and Revolut, and credit card statements from American Express and
+Barclaycard, into structured CSV files for analysis.
 
 Usage:
   bank-statement-converter [flags] <input.pdf> [input2.pdf ...]
@@ -66,20 +95,85 @@ Examples:
   # Start web UI (Go Fiber)
   bank-statement-converter --serve --port=3001
 
+  # Show how each file's bank would be detected, without converting
+  bank-statement-converter --detect-only --dry-run-detect statement.pdf
+
+  # Add banks described by JSON templates
+  bank-statement-converter --templates=./templates statement.pdf
+
+  # Write ISO dates (years inferred for statements that print "4 Dec")
+  bank-statement-converter --date-format=iso statement.pdf
+
+  # CSV with Debit/Credit columns, semicolons and decimal commas for Excel
+  bank-statement-converter --csv-amounts=split --csv-delimiter=";" --csv-decimal=, --csv-bom statement.pdf
+
+  # Export OFX for accounting software (QFX for Quicken)
+  bank-statement-converter --format=ofx statement.pdf
+
+  # CSV in the layout Xero imports (also quickbooks, sage50, freeagent)
+  bank-statement-converter --format=xero statement.pdf
+
+  # One Excel workbook with a sheet per statement
+  bank-statement-converter --format=xlsx --output=statements.xlsx jan.pdf feb.pdf
+
+  # ISO 20022 camt.053 or SWIFT MT940 for treasury systems
+  bank-statement-converter --format=camt053 statement.pdf
+
+  # Plain-text accounting, with account names per sort code and account number
+  bank-statement-converter --format=beancount --ledger-accounts=accounts.json statement.pdf
+
+  # A printable PDF report to attach to client files (HTML without .pdf)
+  bank-statement-converter --format=report --output=report.pdf statement.pdf
+
+  # Merge overlapping monthly statements into one de-duplicated CSV
+  bank-statement-converter --merge --output=2024.csv jan.pdf feb.pdf mar.pdf
+
+  # A statement emailed as a PDF protected with the date of birth
+  bank-statement-converter --password=01011990 statement.pdf
+
+  # The API's JSON response, one statement per line, piped into jq
+  bank-statement-converter --format=ndjson --output=- *.pdf | jq .totalDebit
+
 Supported Banks:
   metro     - Metro Bank (DD/MM/YYYY format)
   hsbc      - HSBC UK (DD Mon YY format)
   barclays  - Barclays (DD/MM/YYYY or DD Mon YYYY format)
+  lloyds    - Lloyds Bank (DD Mon YY format, with type codes)
+  natwest   - NatWest (DD Mon YYYY format, dates shared per day)
+  rbs       - Royal Bank of Scotland (same layout as NatWest)
+  santander - Santander (1st Jan or DD/MM/YYYY format)
+  nationwide - Nationwide (DD Mon format in monthly blocks)
+  monzo     - Monzo (DD/MM/YYYY format, signed amounts)
+  starling  - Starling Bank (DD/MM/YYYY format, In/Out columns)
+  revolut   - Revolut (D Mon YYYY format, one section per currency)
+  amex      - American Express credit card (Mon DD format, CR credits)
+  barclaycard - Barclaycard credit card (DD Mon format, CR credits)
 `)
 	}
 
 	flag.Parse()
 
+	// Keep stdout for the converted output when it is written there
+	if *outputFlag == "-" {
+		progress = os.Stderr
+	}
+
 	if *versionFlag {
 		fmt.Printf("bank-statement-converter v%s (Go Fiber)\n", version)
 		os.Exit(0)
 	}
 
+	// Template banks must be registered before detection or serving
+	if *templatesFlag != "" {
+		loaded, err := parser.LoadTemplates(*templatesFlag)
+		if err != nil {
+			fatalf("Failed to load templates: %v\n", err)
+		}
+		for _, t := range loaded {
+			fmt.Fprintf(progress, "Loaded template: %s (%s)\n", t.Bank, t.Name)
+		}
+	}
+
 	// Web server mode
 	if *serveFlag {
 		startServer(*portFlag, *staticFlag)
@@ -96,21 +190,81 @@ Supported Banks:
 	// Validate bank flag if provided
 	var bankType models.BankType
 	if *bankFlag != "" {
-		switch strings.ToLower(*bankFlag) {
-		case "metro", "metrobank":
-			bankType = models.BankMetro
-		case "hsbc":
-			bankType = models.BankHSBC
-		case "barclays":
-			bankType = models.BankBarclays
-		default:
-			fatalf("Unknown bank type %q. Supported: metro, hsbc, barclays\n", *bankFlag)
+		bt, ok := parser.LookupBank(*bankFlag)
+		if !ok {
+			fatalf("Unknown bank type %q. Supported: %s\n", *bankFlag, strings.Join(parser.SupportedBanks(), ", "))
+		}
+		bankType = bt
+	}
+
+	dateLayout, err := writer.DateLayout(*dateFormatFlag)
+	if err != nil {
+		fatalf("%v\n", err)
+	}
+	format, err := writer.LookupFormat(*formatFlag)
+	if err != nil {
+		fatalf("%v\n", err)
+	}
+	// The report is HTML unless --output asks for a PDF
+	if format.Name == "report" && strings.EqualFold(filepath.Ext(*outputFlag), ".pdf") {
+		format, _ = writer.LookupFormat("report-pdf")
+	}
+	csvSchema, err := csvSchemaFromFlags(*csvSchemaFlag)
+	if err != nil {
+		fatalf("%v\n", err)
+	}
+	var ledgerAccounts *writer.LedgerAccounts
+	if *ledgerAccountsFlag != "" {
+		if ledgerAccounts, err = writer.LoadLedgerAccounts(*ledgerAccountsFlag); err != nil {
+			fatalf("%v\n", err)
+		}
+	}
+
+	if *detectOnlyFlag {
+		for _, inputPath := range inputFiles {
+			if err := detectFile(inputPath, *passwordFlag, *dryRunDetectFlag); err != nil {
+				fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", inputPath, err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	opts := convertOptions{
+		bank:           bankType,
+		outputPath:     *outputFlag,
+		includeHeader:  *headerFlag,
+		dryRunDetect:   *dryRunDetectFlag,
+		password:       *passwordFlag,
+		dateLayout:     dateLayout,
+		format:         format,
+		csvSchema:      csvSchema,
+		sanitize:       *sanitizeFlag,
+		debugLines:     *debugLinesFlag,
+		ledgerAccounts: ledgerAccounts,
+	}
+
+	// Consecutive statements for one account into one ledger
+	if *mergeFlag {
+		if err := mergeFiles(inputFiles, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error merging: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Several PDFs into one --output file, where the format allows it
+	if mw, ok := format.New(opts.writerOptions()).(writer.MultiStatementWriter); ok && len(inputFiles) > 1 && opts.outputPath != "" {
+		if err := processFiles(inputFiles, mw, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing %v\n", err)
+			os.Exit(1)
 		}
+		return
 	}
 
 	// Process each input file
 	for _, inputPath := range inputFiles {
-		if err := processFile(inputPath, bankType, *outputFlag, *headerFlag); err != nil {
+		if err := processFile(inputPath, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error processing %s: %v\n", inputPath, err)
 			os.Exit(1)
 		}
@@ -138,6 +292,7 @@ func startServer(port, staticDir string) {
 	apiGroup := app.Group("/api")
 	apiGroup.Get("/health", api.HandleHealth)
 	apiGroup.Post("/convert", api.HandleConvert)
+	apiGroup.Post("/report", api.HandleReport)
 
 	// Serve React static files (SPA)
 	if staticDir != "" {
@@ -171,92 +326,321 @@ func startServer(port, staticDir string) {
 	log.Fatal(app.Listen(addr))
 }
 
-func processFile(inputPath string, bankType models.BankType, outputPath string, includeHeader bool) error {
+// progress receives the CLI's progress and summary lines: stdout, or
+// stderr when --output=- writes the converted statement to stdout.
+var progress io.Writer = os.Stdout
+
+// convertOptions are the CLI settings applied to every input file.
+type convertOptions struct {
+	bank           models.BankType // empty to auto-detect
+	outputPath     string
+	includeHeader  bool
+	dryRunDetect   bool
+	password       string // for encrypted PDFs
+	dateLayout     string // Go layout for output dates; empty keeps them as printed
+	format         writer.Format
+	csvSchema      *writer.CSVSchema // nil for the default CSV layout
+	sanitize       bool
+	debugLines     bool
+	ledgerAccounts *writer.LedgerAccounts // nil for the default beancount/ledger accounts
+}
+
+// writerOptions are the output settings for opts.format.
+func (opts convertOptions) writerOptions() writer.Options {
+	return writer.Options{
+		IncludeHeader:  opts.includeHeader,
+		DateFormat:     opts.dateLayout,
+		CSVSchema:      opts.csvSchema,
+		Sanitize:       opts.sanitize,
+		DebugLines:     opts.debugLines,
+		LedgerAccounts: opts.ledgerAccounts,
+	}
+}
+
+func processFile(inputPath string, opts convertOptions) error {
+	info, err := convertFile(inputPath, opts)
+	if err != nil {
+		return err
+	}
+
+	// Determine output path
+	outPath := opts.outputPath
+	if outPath == "" {
+		base := strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
+		outPath = base + opts.format.Extension
+	}
+
+	w := opts.format.New(opts.writerOptions())
+	if err := writer.WriteFile(outPath, w, info); err != nil {
+		return fmt.Errorf("%s write failed: %w", strings.ToUpper(opts.format.Name), err)
+	}
+
+	if outPath == "-" {
+		outPath = "stdout"
+	}
+	fmt.Fprintf(progress, "  Output: %s\n", outPath)
+	fmt.Fprintln(progress, "  Done.")
+	return nil
+}
+
+// processFiles converts several PDFs into the one --output file, for
+// formats that hold several statements (one XLSX sheet per statement, a
+// JSON array or NDJSON lines).
+func processFiles(inputPaths []string, w writer.MultiStatementWriter, opts convertOptions) error {
+	var infos []*models.StatementInfo
+	for _, inputPath := range inputPaths {
+		info, err := convertFile(inputPath, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputPath, err)
+		}
+		infos = append(infos, info)
+	}
+	if err := writer.WriteAllFile(opts.outputPath, w, infos); err != nil {
+		return fmt.Errorf("%s write failed: %w", strings.ToUpper(opts.format.Name), err)
+	}
+	fmt.Fprintf(progress, "Output: %s (%d statements)\n", opts.outputPath, len(infos))
+	return nil
+}
+
+// mergeFiles converts several PDFs for one account and writes them as a
+// single statement, reporting duplicates dropped, balance breaks and gaps.
+// Without --output it writes <first input>-merged.<format>.
+func mergeFiles(inputPaths []string, opts convertOptions) error {
+	var infos []*models.StatementInfo
+	for _, inputPath := range inputPaths {
+		info, err := convertFile(inputPath, opts)
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputPath, err)
+		}
+		infos = append(infos, info)
+	}
+	merged, report, err := merge.Merge(infos)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(progress, "Merged %d statement(s): %d transaction(s), %d duplicate(s) removed\n",
+		report.Statements, len(merged.Transactions), report.Duplicates)
+	fmt.Fprintf(progress, "  Period: %s\n", merged.StatementPeriod)
+	if len(report.Issues) == 0 {
+		fmt.Fprintln(progress, "  Continuity: OK (no balance breaks or gaps)")
+	} else {
+		fmt.Fprintf(progress, "  Continuity: %d issue(s)\n", len(report.Issues))
+		for _, issue := range report.Issues {
+			fmt.Fprintf(progress, "    - %s\n", issue)
+		}
+	}
+	printValidation(validator.Validate(merged))
+
+	outPath := opts.outputPath
+	if outPath == "" {
+		outPath = strings.TrimSuffix(inputPaths[0], filepath.Ext(inputPaths[0])) + "-merged" + opts.format.Extension
+	}
+	w := opts.format.New(opts.writerOptions())
+	if err := writer.WriteFile(outPath, w, merged); err != nil {
+		return fmt.Errorf("%s write failed: %w", strings.ToUpper(opts.format.Name), err)
+	}
+	if outPath == "-" {
+		outPath = "stdout"
+	}
+	fmt.Fprintf(progress, "  Output: %s\n", outPath)
+	return nil
+}
+
+// convertFile extracts, parses and validates one PDF, printing progress and
+// the account summary.
+func convertFile(inputPath string, opts convertOptions) (*models.StatementInfo, error) {
 	// Validate input file
 	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
-		return fmt.Errorf("input file not found: %s", inputPath)
+		return nil, fmt.Errorf("input file not found: %s", inputPath)
 	}
 
 	ext := strings.ToLower(filepath.Ext(inputPath))
 	if ext != ".pdf" {
-		return fmt.Errorf("expected .pdf file, got %q", ext)
+		return nil, fmt.Errorf("expected .pdf file, got %q", ext)
 	}
 
-	fmt.Printf("Processing: %s\n", inputPath)
+	fmt.Fprintf(progress, "Processing: %s\n", inputPath)
 
 	// Extract text from PDF
-	pages, err := extractor.ExtractText(inputPath)
+	pages, err := extractText(inputPath, opts.password)
 	if err != nil {
-		return fmt.Errorf("PDF extraction failed: %w", err)
+		return nil, fmt.Errorf("PDF extraction failed: %w", err)
 	}
 
-	fmt.Printf("  Extracted text from %d page(s)\n", len(pages))
+	fmt.Fprintf(progress, "  Extracted text from %d page(s)\n", len(pages))
 
 	// Auto-detect bank if not specified
-	effectiveBank := bankType
+	effectiveBank := opts.bank
 	if effectiveBank == "" {
-		detected, err := parser.AutoDetect(pages)
+		detected, candidates, err := parser.DetectBank(pages, opts.dryRunDetect)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		effectiveBank = detected
-		fmt.Printf("  Auto-detected bank: %s\n", effectiveBank)
+		fmt.Fprintf(progress, "  Auto-detected bank: %s (confidence %.2f)\n", effectiveBank, candidates[0].Confidence)
 	}
 
 	// Create parser for the bank
 	p, err := parser.New(effectiveBank)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	fmt.Printf("  Using %s parser\n", p.BankName())
+	fmt.Fprintf(progress, "  Using %s parser\n", p.BankName())
 
 	// Parse the statement
 	info, err := p.Parse(pages)
 	if err != nil {
-		return fmt.Errorf("parsing failed: %w", err)
+		return nil, fmt.Errorf("parsing failed: %w", err)
 	}
 
-	fmt.Printf("  Found %d transaction(s)\n", len(info.Transactions))
+	fmt.Fprintf(progress, "  Found %d transaction(s)\n", len(info.Transactions))
 
-	if len(info.Transactions) == 0 {
-		fmt.Println("  Warning: No transactions found. The PDF format may not match expected patterns.")
-		fmt.Println("  Try specifying the bank explicitly with --bank flag if auto-detection was used.")
+	if info.CreditCard && !info.StatementBalance.IsZero() {
+		fmt.Fprintf(progress, "  Statement balance: %s", info.StatementBalance)
+		if !info.MinimumPayment.IsZero() {
+			fmt.Fprintf(progress, ", minimum payment %s", info.MinimumPayment)
+		}
+		if info.PaymentDueDate != "" {
+			fmt.Fprintf(progress, " due %s", info.PaymentDueDate)
+		}
+		fmt.Fprintln(progress)
 	}
 
-	// Determine output path
-	outPath := outputPath
-	if outPath == "" {
-		base := strings.TrimSuffix(inputPath, filepath.Ext(inputPath))
-		outPath = base + ".csv"
+	if len(info.Transactions) > 0 {
+		printValidation(validator.Validate(info))
 	}
 
-	// Write CSV
-	w := &writer.CSVWriter{IncludeHeader: includeHeader}
-	if err := w.WriteToFile(outPath, info); err != nil {
-		return fmt.Errorf("CSV write failed: %w", err)
+	if len(info.Transactions) == 0 {
+		fmt.Fprintln(progress, "  Warning: No transactions found. The PDF format may not match expected patterns.")
+		fmt.Fprintln(progress, "  Try specifying the bank explicitly with --bank flag if auto-detection was used.")
 	}
 
-	fmt.Printf("  Output: %s\n", outPath)
-
 	// Print summary
 	if info.AccountHolder != "" {
-		fmt.Printf("  Account holder: %s\n", info.AccountHolder)
+		fmt.Fprintf(progress, "  Account holder: %s\n", info.AccountHolder)
 	}
 	if info.AccountNumber != "" {
-		fmt.Printf("  Account number: %s\n", info.AccountNumber)
+		fmt.Fprintf(progress, "  Account number: %s\n", info.AccountNumber)
 	}
 	if info.SortCode != "" {
-		fmt.Printf("  Sort code: %s\n", info.SortCode)
+		fmt.Fprintf(progress, "  Sort code: %s\n", info.SortCode)
 	}
 	if info.StatementPeriod != "" {
-		fmt.Printf("  Period: %s\n", info.StatementPeriod)
+		fmt.Fprintf(progress, "  Period: %s\n", info.StatementPeriod)
 	}
 
-	fmt.Println("  Done.")
+	return info, nil
+}
+
+// csvSchemaFromFlags builds the CSV schema from the --csv-schema file and
+// any --csv-* flags given, which override the file's settings. It returns
+// nil when neither is used.
+func csvSchemaFromFlags(path string) (*writer.CSVSchema, error) {
+	schema := &writer.CSVSchema{}
+	if path != "" {
+		loaded, err := writer.LoadCSVSchema(path)
+		if err != nil {
+			return nil, err
+		}
+		schema = loaded
+	}
+
+	used := path != ""
+	var err error
+	flag.Visit(func(f *flag.Flag) {
+		if !strings.HasPrefix(f.Name, "csv-") || f.Name == "csv-schema" || err != nil {
+			return
+		}
+		used = true
+		v := f.Value.String()
+		switch f.Name {
+		case "csv-columns":
+			schema.Columns, err = writer.ParseCSVColumns(v)
+		case "csv-amounts":
+			schema.Amounts = v
+		case "csv-delimiter":
+			schema.Delimiter = v
+		case "csv-decimal":
+			schema.DecimalSeparator = v
+		case "csv-quote-all":
+			schema.QuoteAll = v == "true"
+		case "csv-zero-amounts":
+			schema.ZeroAmounts = v == "true"
+		case "csv-bom":
+			schema.BOM = v == "true"
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, nil
+	}
+	if err := schema.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid CSV schema: %w", err)
+	}
+	return schema, nil
+}
+
+// extractText extracts a PDF's text, decrypting it with password if it is
+// encrypted, and says how to give the password when it is missing or
+// wrong.
+func extractText(inputPath, password string) ([]string, error) {
+	pages, err := extractor.ExtractTextWithPassword(inputPath, password)
+	switch {
+	case errors.Is(err, extractor.ErrPasswordRequired):
+		return nil, fmt.Errorf("%w: give it with --password", err)
+	case errors.Is(err, extractor.ErrWrongPassword):
+		return nil, fmt.Errorf("%w: check --password", err)
+	}
+	return pages, err
+}
+
+// detectFile prints the ranked bank detection for a PDF without converting it.
+func detectFile(inputPath, password string, dryRun bool) error {
+	if _, err := os.Stat(inputPath); os.IsNotExist(err) {
+		return fmt.Errorf("input file not found: %s", inputPath)
+	}
+
+	pages, err := extractText(inputPath, password)
+	if err != nil {
+		return fmt.Errorf("PDF extraction failed: %w", err)
+	}
+
+	fmt.Printf("%s:\n", inputPath)
+	candidates := parser.RankBanks(pages, dryRun)
+	if len(candidates) == 0 {
+		fmt.Println("  No bank identified")
+		return nil
+	}
+	for i, c := range candidates {
+		fmt.Printf("  %d. %-12s %-24s confidence %.2f (score %.1f)\n", i+1, c.Bank, c.Name, c.Confidence, c.Score)
+		for _, reason := range c.Reasons {
+			fmt.Printf("       - %s\n", reason)
+		}
+	}
 	return nil
 }
 
+// printValidation prints the reconciliation report under the file's
+// progress lines.
+func printValidation(r *models.ValidationReport) {
+	if r.Valid {
+		fmt.Fprintf(progress, "  Validation: OK (%d balance(s) reconciled", r.Checked)
+		if !r.ClosingBalance.IsZero() {
+			fmt.Fprintf(progress, ", closing balance %s", r.ClosingBalance)
+		}
+		fmt.Fprintln(progress, ")")
+		return
+	}
+	fmt.Fprintf(progress, "  Validation: %d issue(s)\n", len(r.Issues))
+	for _, issue := range r.Issues {
+		fmt.Fprintf(progress, "    - %s\n", issue)
+	}
+}
+
 func fatalf(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, format, args...)
 	os.Exit(1)
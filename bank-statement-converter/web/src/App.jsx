@@ -16,9 +16,9 @@ pdfjsLib.GlobalWorkerOptions.workerSrc = new URL(
 ).toString()
 
 // Extract text from PDF using pdf.js (Mozilla's PDF library)
-async function extractTextFromPDF(file) {
+async function extractTextFromPDF(file, password) {
   const arrayBuffer = await file.arrayBuffer()
-  const pdf = await pdfjsLib.getDocument({ data: arrayBuffer }).promise
+  const pdf = await pdfjsLib.getDocument({ data: arrayBuffer, password }).promise
   const pages = []
 
   for (let i = 1; i <= pdf.numPages; i++) {
@@ -100,7 +100,7 @@ function App() {
   const [loading, setLoading] = useState(false)
   const [error, setError] = useState(null)
 
-  const handleConvert = async (file, bank) => {
+  const handleConvert = async (file, bank, password) => {
     setLoading(true)
     setError(null)
     setResult(null)
@@ -109,7 +109,7 @@ function App() {
       // Step 1: Extract text from PDF client-side using pdf.js
       let extractedText = ''
       try {
-        const pages = await extractTextFromPDF(file)
+        const pages = await extractTextFromPDF(file, password)
         if (pages.length > 0) {
           extractedText = pages.join('\n---PAGE_BREAK---\n')
         }
@@ -121,6 +121,7 @@ function App() {
       const formData = new FormData()
       formData.append('file', file)
       if (bank) formData.append('bank', bank)
+      if (password) formData.append('password', password)
       if (extractedText) formData.append('extractedText', extractedText)
 
       const res = await fetch('/api/convert', {
@@ -129,7 +130,13 @@ function App() {
       })
       const data = await res.json()
       if (!data.success) {
-        setError(data.error || 'Conversion failed.')
+        if (data.errorCode === 'password_required') {
+          setError('This statement is password-protected. Enter its password and convert again.')
+        } else if (data.errorCode === 'wrong_password') {
+          setError('The password is incorrect for this statement.')
+        } else {
+          setError(data.error || 'Conversion failed.')
+        }
       } else {
         // Attach the frontend's own extracted text for debugging
         data.frontendText = extractedText
@@ -6,6 +6,7 @@ import Button from '@mui/material/Button'
 import Alert from '@mui/material/Alert'
 import CircularProgress from '@mui/material/CircularProgress'
 import Stack from '@mui/material/Stack'
+import TextField from '@mui/material/TextField'
 import UploadFileIcon from '@mui/icons-material/UploadFile'
 import InsertDriveFileIcon from '@mui/icons-material/InsertDriveFile'
 const BANKS = [
@@ -13,11 +14,22 @@ const BANKS = [
   { value: 'metro', label: 'Metro Bank', hint: 'DD/MM/YYYY' },
   { value: 'hsbc', label: 'HSBC', hint: 'DD Mon YY' },
   { value: 'barclays', label: 'Barclays', hint: 'DD/MM/YYYY' },
+  { value: 'lloyds', label: 'Lloyds Bank', hint: 'DD Mon YY' },
+  { value: 'natwest', label: 'NatWest', hint: 'DD Mon YYYY' },
+  { value: 'rbs', label: 'RBS', hint: 'DD Mon YYYY' },
+  { value: 'santander', label: 'Santander', hint: '1st Jan' },
+  { value: 'nationwide', label: 'Nationwide', hint: 'DD Mon' },
+  { value: 'monzo', label: 'Monzo', hint: 'DD/MM/YYYY' },
+  { value: 'starling', label: 'Starling Bank', hint: 'DD/MM/YYYY' },
+  { value: 'revolut', label: 'Revolut', hint: 'D Mon YYYY' },
+  { value: 'amex', label: 'American Express', hint: 'Credit card' },
+  { value: 'barclaycard', label: 'Barclaycard', hint: 'Credit card' },
 ]
 
 function FileUpload({ onConvert, loading, error }) {
   const [file, setFile] = useState(null)
   const [bank, setBank] = useState('')
+  const [password, setPassword] = useState('')
   const [dragOver, setDragOver] = useState(false)
   const inputRef = useRef()
 
@@ -46,7 +58,7 @@ function FileUpload({ onConvert, loading, error }) {
   const handleSubmit = (e) => {
     e.preventDefault()
     if (file && !loading) {
-      onConvert(file, bank)
+      onConvert(file, bank, password)
     }
   }
 
@@ -152,6 +164,18 @@ function FileUpload({ onConvert, loading, error }) {
         </Stack>
       </Box>
 
+      {/* Password for encrypted statements */}
+      <TextField
+        type="password"
+        label="PDF password (if the statement is protected)"
+        value={password}
+        onChange={(e) => setPassword(e.target.value)}
+        autoComplete="off"
+        size="small"
+        fullWidth
+        sx={{ mt: 3 }}
+      />
+
       {/* Convert Button */}
       <Button
         type="submit"